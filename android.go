@@ -0,0 +1,194 @@
+package poeditor
+
+import (
+	"bufio"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// ReadAndroidStrings decodes an Android strings.xml resource file. The XML
+// comment directly above a <string> or <plurals> element becomes the term
+// comment. String arrays are not supported and are skipped.
+func ReadAndroidStrings(r io.Reader) ([]TermTranslated, error) {
+	var (
+		terms   []TermTranslated
+		comment string
+	)
+	dec := xml.NewDecoder(r)
+	for {
+		tok, err := dec.Token()
+		if err == io.EOF {
+			return terms, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		switch tok := tok.(type) {
+		case xml.Comment:
+			comment = strings.TrimSpace(string(tok))
+		case xml.StartElement:
+			switch tok.Name.Local {
+			case "resources":
+				continue
+			case "string":
+				var s androidString
+				if err := dec.DecodeElement(&s, &tok); err != nil {
+					return nil, err
+				}
+				t := TermTranslated{}
+				t.Term.Term = s.Name
+				t.Comment = comment
+				t.Translation.Content = unescapeAndroid(decodeXMLText(s.Value))
+				terms = append(terms, t)
+			case "plurals":
+				var s androidPlurals
+				if err := dec.DecodeElement(&s, &tok); err != nil {
+					return nil, err
+				}
+				t := TermTranslated{}
+				t.Term.Term = s.Name
+				t.Plural = s.Name
+				t.Comment = comment
+				var p Plural
+				fields := pluralFields(&p)
+				for _, item := range s.Items {
+					f, ok := fields[item.Quantity]
+					if !ok {
+						return nil, fmt.Errorf("android_strings: invalid quantity %q in %q", item.Quantity, s.Name)
+					}
+					*f = unescapeAndroid(decodeXMLText(item.Value))
+				}
+				t.Translation.Content = p
+				terms = append(terms, t)
+			default:
+				dec.Skip()
+			}
+			comment = ""
+		}
+	}
+}
+
+// WriteAndroidStrings encodes terms as an Android strings.xml resource file
+func WriteAndroidStrings(w io.Writer, terms []TermTranslated) error {
+	bw := bufio.NewWriter(w)
+	bw.WriteString(`<?xml version="1.0" encoding="utf-8"?>` + "\n<resources>\n")
+	for _, t := range terms {
+		if t.Comment != "" {
+			fmt.Fprintf(bw, "    <!-- %s -->\n", strings.Replace(t.Comment, "--", "- -", -1))
+		}
+		name := xmlEscape(t.Term.Term)
+		switch c := t.Translation.Content.(type) {
+		case Plural:
+			fmt.Fprintf(bw, "    <plurals name=\"%s\">\n", name)
+			values := pluralValues(c)
+			for _, category := range pluralForms(c) {
+				fmt.Fprintf(bw, "        <item quantity=\"%s\">%s</item>\n",
+					category, encodeXMLText(escapeAndroid(values[category])))
+			}
+			bw.WriteString("    </plurals>\n")
+		case string:
+			fmt.Fprintf(bw, "    <string name=\"%s\">%s</string>\n", name, encodeXMLText(escapeAndroid(c)))
+		case nil:
+			fmt.Fprintf(bw, "    <string name=\"%s\"></string>\n", name)
+		default:
+			return ErrTranslationInvalid
+		}
+	}
+	bw.WriteString("</resources>\n")
+	return bw.Flush()
+}
+
+type androidString struct {
+	Name  string `xml:"name,attr"`
+	Value string `xml:",innerxml"`
+}
+
+type androidPlurals struct {
+	Name  string `xml:"name,attr"`
+	Items []struct {
+		Quantity string `xml:"quantity,attr"`
+		Value    string `xml:",innerxml"`
+	} `xml:"item"`
+}
+
+// unescapeAndroid resolves Android resource escapes. Whitespace inside double
+// quotes is kept as is, outside it is collapsed and trimmed.
+func unescapeAndroid(s string) string {
+	var (
+		b       strings.Builder
+		quoted  bool
+		pending bool
+	)
+	write := func(c byte) {
+		if pending {
+			b.WriteByte(' ')
+			pending = false
+		}
+		b.WriteByte(c)
+	}
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case c == '\\' && i+1 < len(s):
+			i++
+			switch s[i] {
+			case 'n':
+				write('\n')
+			case 't':
+				write('\t')
+			case 'u':
+				if i+4 < len(s) {
+					if r, err := strconv.ParseUint(s[i+1:i+5], 16, 32); err == nil {
+						for _, c := range []byte(string(rune(r))) {
+							write(c)
+						}
+						i += 4
+						continue
+					}
+				}
+				write('u')
+			default:
+				write(s[i])
+			}
+		case c == '"':
+			quoted = !quoted
+		case !quoted && (c == ' ' || c == '\n' || c == '\t' || c == '\r'):
+			pending = b.Len() > 0
+		default:
+			write(c)
+		}
+	}
+	return b.String()
+}
+
+func escapeAndroid(s string) string {
+	var b strings.Builder
+	for i, r := range s {
+		switch {
+		case r == '\\':
+			b.WriteString(`\\`)
+		case r == '\'':
+			b.WriteString(`\'`)
+		case r == '"':
+			b.WriteString(`\"`)
+		case r == '\n':
+			b.WriteString(`\n`)
+		case r == '\t':
+			b.WriteString(`\t`)
+		case i == 0 && (r == '@' || r == '?'):
+			b.WriteByte('\\')
+			b.WriteRune(r)
+		default:
+			b.WriteRune(r)
+		}
+	}
+	// Leading, trailing and repeated spaces are only kept inside quotes
+	out := b.String()
+	if strings.HasPrefix(out, " ") || strings.HasSuffix(out, " ") || strings.Contains(out, "  ") {
+		return `"` + out + `"`
+	}
+	return out
+}
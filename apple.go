@@ -0,0 +1,265 @@
+package poeditor
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"strconv"
+	"strings"
+	"unicode/utf16"
+)
+
+// ReadAppleStrings decodes an Apple .strings file. Files encoded as UTF-16
+// with a byte order mark are supported. The comment directly above an entry
+// becomes the term comment.
+func ReadAppleStrings(r io.Reader) ([]TermTranslated, error) {
+	b, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	p := appleParser{s: decodeUTF16(b), line: 1}
+	var (
+		terms   []TermTranslated
+		comment string
+	)
+	for {
+		c, ok, err := p.skipSpace()
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			comment = c
+			continue
+		}
+		if p.eof() {
+			return terms, nil
+		}
+		key, err := p.token()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expect('='); err != nil {
+			return nil, err
+		}
+		value, err := p.token()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expect(';'); err != nil {
+			return nil, err
+		}
+		t := TermTranslated{}
+		t.Term.Term = key
+		t.Comment = comment
+		t.Translation.Content = value
+		terms = append(terms, t)
+		comment = ""
+	}
+}
+
+// WriteAppleStrings encodes terms as a UTF-8 Apple .strings file. Apple
+// strings have no plurals, so only the other form of a plural is written.
+func WriteAppleStrings(w io.Writer, terms []TermTranslated) error {
+	bw := bufio.NewWriter(w)
+	for i, t := range terms {
+		var value string
+		switch c := t.Translation.Content.(type) {
+		case Plural:
+			value = c.Other
+		case string:
+			value = c
+		case nil:
+		default:
+			return ErrTranslationInvalid
+		}
+		if i > 0 {
+			bw.WriteString("\n")
+		}
+		if t.Comment != "" {
+			fmt.Fprintf(bw, "/* %s */\n", strings.Replace(t.Comment, "*/", "* /", -1))
+		}
+		fmt.Fprintf(bw, "%s = %s;\n", quoteApple(t.Term.Term), quoteApple(value))
+	}
+	return bw.Flush()
+}
+
+type appleParser struct {
+	s    string
+	pos  int
+	line int
+}
+
+func (p *appleParser) eof() bool {
+	return p.pos >= len(p.s)
+}
+
+func (p *appleParser) errorf(format string, args ...interface{}) error {
+	return fmt.Errorf("apple_strings: line %d: %s", p.line, fmt.Sprintf(format, args...))
+}
+
+// skipSpace skips whitespace up to and including the next comment. The text
+// of the comment is returned if one is found.
+func (p *appleParser) skipSpace() (string, bool, error) {
+	for !p.eof() {
+		switch c := p.s[p.pos]; {
+		case c == '\n':
+			p.line++
+			p.pos++
+		case c == ' ' || c == '\t' || c == '\r':
+			p.pos++
+		case strings.HasPrefix(p.s[p.pos:], "/*"):
+			end := strings.Index(p.s[p.pos+2:], "*/")
+			if end < 0 {
+				return "", false, p.errorf("unterminated comment")
+			}
+			c := p.s[p.pos+2 : p.pos+2+end]
+			p.line += strings.Count(c, "\n")
+			p.pos += end + 4
+			return strings.TrimSpace(c), true, nil
+		case strings.HasPrefix(p.s[p.pos:], "//"):
+			end := strings.IndexByte(p.s[p.pos:], '\n')
+			if end < 0 {
+				end = len(p.s) - p.pos
+			}
+			c := p.s[p.pos+2 : p.pos+end]
+			p.pos += end
+			return strings.TrimSpace(c), true, nil
+		default:
+			return "", false, nil
+		}
+	}
+	return "", false, nil
+}
+
+// skipComments skips whitespace and comments
+func (p *appleParser) skipComments() error {
+	for {
+		_, ok, err := p.skipSpace()
+		if err != nil || !ok {
+			return err
+		}
+	}
+}
+
+func (p *appleParser) expect(c byte) error {
+	if err := p.skipComments(); err != nil {
+		return err
+	}
+	if p.eof() {
+		return p.errorf("expected %q but got end of file", c)
+	}
+	if p.s[p.pos] != c {
+		return p.errorf("expected %q but got %q", c, p.s[p.pos])
+	}
+	p.pos++
+	return nil
+}
+
+// token reads a quoted string or an unquoted identifier
+func (p *appleParser) token() (string, error) {
+	if err := p.skipComments(); err != nil {
+		return "", err
+	}
+	if p.eof() {
+		return "", p.errorf("unexpected end of file")
+	}
+	if p.s[p.pos] != '"' {
+		start := p.pos
+		for !p.eof() && strings.IndexByte(" \t\r\n=;", p.s[p.pos]) < 0 {
+			p.pos++
+		}
+		if start == p.pos {
+			return "", p.errorf("unexpected %q", p.s[p.pos])
+		}
+		return p.s[start:p.pos], nil
+	}
+	p.pos++
+	var (
+		b     strings.Builder
+		units []uint16
+	)
+	flush := func() {
+		if len(units) > 0 {
+			b.WriteString(string(utf16.Decode(units)))
+			units = nil
+		}
+	}
+	for !p.eof() {
+		c := p.s[p.pos]
+		p.pos++
+		switch c {
+		case '"':
+			flush()
+			return b.String(), nil
+		case '\n':
+			p.line++
+		case '\\':
+			if p.eof() {
+				return "", p.errorf("unterminated string")
+			}
+			e := p.s[p.pos]
+			p.pos++
+			if e == 'U' || e == 'u' {
+				if p.pos+4 > len(p.s) {
+					return "", p.errorf("malformed unicode escape")
+				}
+				u, err := strconv.ParseUint(p.s[p.pos:p.pos+4], 16, 16)
+				if err != nil {
+					return "", p.errorf("malformed unicode escape")
+				}
+				units = append(units, uint16(u))
+				p.pos += 4
+				continue
+			}
+			flush()
+			switch e {
+			case 'n':
+				b.WriteByte('\n')
+			case 't':
+				b.WriteByte('\t')
+			case 'r':
+				b.WriteByte('\r')
+			default:
+				b.WriteByte(e)
+			}
+			continue
+		}
+		flush()
+		b.WriteByte(c)
+	}
+	return "", p.errorf("unterminated string")
+}
+
+var appleEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`, "\t", `\t`, "\r", `\r`)
+
+func quoteApple(s string) string {
+	return `"` + appleEscaper.Replace(s) + `"`
+}
+
+// decodeUTF16 converts UTF-16 input with a byte order mark to UTF-8. Other
+// input is returned as is, without a UTF-8 byte order mark.
+func decodeUTF16(b []byte) string {
+	if len(b) < 2 {
+		return string(b)
+	}
+	var bigEndian bool
+	switch {
+	case b[0] == 0xfe && b[1] == 0xff:
+		bigEndian = true
+	case b[0] == 0xff && b[1] == 0xfe:
+	default:
+		return string(bytes.TrimPrefix(b, []byte("\xef\xbb\xbf")))
+	}
+	b = b[2:]
+	units := make([]uint16, len(b)/2)
+	for i := range units {
+		if bigEndian {
+			units[i] = uint16(b[2*i])<<8 | uint16(b[2*i+1])
+		} else {
+			units[i] = uint16(b[2*i+1])<<8 | uint16(b[2*i])
+		}
+	}
+	return string(utf16.Decode(units))
+}
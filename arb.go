@@ -0,0 +1,274 @@
+package poeditor
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// ARBFile is a Flutter Application Resource Bundle. Messages are kept in the
+// order they appear in the file.
+type ARBFile struct {
+	Locale string
+	// Attributes holds the remaining global @@ attributes such as
+	// @@last_modified, without the @@ prefix
+	Attributes map[string]interface{}
+	Messages   []ARBMessage
+}
+
+// ARBMessage is a single message of an ARB file. The @key description is
+// mapped to Term.Comment and the @key context to Term.Context. ICU plural
+// messages are decoded into a Plural translation.
+type ARBMessage struct {
+	TermTranslated
+	Placeholders map[string]ARBPlaceholder
+}
+
+// ARBPlaceholder is the metadata of a single placeholder of an ARB message
+type ARBPlaceholder struct {
+	Type               string                 `json:"type,omitempty"`
+	Description        string                 `json:"description,omitempty"`
+	Example            string                 `json:"example,omitempty"`
+	Format             string                 `json:"format,omitempty"`
+	OptionalParameters map[string]interface{} `json:"optionalParameters,omitempty"`
+	IsCustomDateFormat string                 `json:"isCustomDateFormat,omitempty"`
+}
+
+type arbMetadata struct {
+	Description  string                    `json:"description,omitempty"`
+	Context      string                    `json:"context,omitempty"`
+	Placeholders map[string]ARBPlaceholder `json:"placeholders,omitempty"`
+}
+
+// ReadARB decodes an ARB file
+func ReadARB(r io.Reader) (ARBFile, error) {
+	f := ARBFile{Attributes: make(map[string]interface{})}
+	dec := json.NewDecoder(r)
+	dec.UseNumber()
+	if err := expectDelim(dec, '{'); err != nil {
+		return f, err
+	}
+	meta := make(map[string]arbMetadata)
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return f, err
+		}
+		key := tok.(string)
+		switch {
+		case strings.HasPrefix(key, "@@"):
+			var v interface{}
+			if err := dec.Decode(&v); err != nil {
+				return f, err
+			}
+			if key == "@@locale" {
+				s, _ := v.(string)
+				f.Locale = s
+				continue
+			}
+			f.Attributes[key[2:]] = v
+		case strings.HasPrefix(key, "@"):
+			var m arbMetadata
+			if err := dec.Decode(&m); err != nil {
+				return f, fmt.Errorf("arb: metadata of %q: %s", key[1:], err)
+			}
+			meta[key[1:]] = m
+		default:
+			var s string
+			if err := dec.Decode(&s); err != nil {
+				return f, fmt.Errorf("arb: message %q: %s", key, err)
+			}
+			m := ARBMessage{}
+			m.Term.Term = key
+			m.Translation.Content = s
+			f.Messages = append(f.Messages, m)
+		}
+	}
+	if err := expectDelim(dec, '}'); err != nil {
+		return f, err
+	}
+	for i := range f.Messages {
+		m := &f.Messages[i]
+		md := meta[m.Term.Term]
+		m.Comment = md.Description
+		m.Context = md.Context
+		m.Placeholders = md.Placeholders
+		s := m.Translation.Content.(string)
+		if arg, p, ok := parseICUPlural(s); ok {
+			m.Translation.Content = p
+			m.Plural = m.Term.Term
+			// Make sure the plural argument survives a round trip
+			if _, ok := m.Placeholders[arg]; !ok {
+				if m.Placeholders == nil {
+					m.Placeholders = make(map[string]ARBPlaceholder)
+				}
+				m.Placeholders[arg] = ARBPlaceholder{}
+			}
+		}
+	}
+	return f, nil
+}
+
+// WriteARB encodes an ARB file
+func WriteARB(w io.Writer, f ARBFile) error {
+	var buf bytes.Buffer
+	buf.WriteString("{")
+	first := true
+	writeEntry := func(key string, v interface{}) error {
+		b, err := marshalJSON(v)
+		if err != nil {
+			return err
+		}
+		if !first {
+			buf.WriteString(",")
+		}
+		first = false
+		k, _ := marshalJSON(key)
+		fmt.Fprintf(&buf, "\n  %s: %s", k, b)
+		return nil
+	}
+	if f.Locale != "" {
+		if err := writeEntry("@@locale", f.Locale); err != nil {
+			return err
+		}
+	}
+	attrs := make([]string, 0, len(f.Attributes))
+	for k := range f.Attributes {
+		attrs = append(attrs, k)
+	}
+	sort.Strings(attrs)
+	for _, k := range attrs {
+		if err := writeEntry("@@"+k, f.Attributes[k]); err != nil {
+			return err
+		}
+	}
+	for _, m := range f.Messages {
+		var s string
+		switch c := m.Translation.Content.(type) {
+		case string:
+			s = c
+		case Plural:
+			s = formatICUPlural(m.pluralArgument(), c)
+		case nil:
+		default:
+			return ErrTranslationInvalid
+		}
+		if err := writeEntry(m.Term.Term, s); err != nil {
+			return err
+		}
+		md := arbMetadata{
+			Description:  m.Comment,
+			Context:      m.Context,
+			Placeholders: m.Placeholders,
+		}
+		if md.Description == "" && md.Context == "" && len(md.Placeholders) == 0 {
+			continue
+		}
+		if err := writeEntry("@"+m.Term.Term, md); err != nil {
+			return err
+		}
+	}
+	buf.WriteString("\n}\n")
+	_, err := buf.WriteTo(w)
+	return err
+}
+
+// NewARBFile builds an ARB file for a POEditor language code from a list of
+// translated terms
+func NewARBFile(code string, terms []TermTranslated) ARBFile {
+	f := ARBFile{Locale: arbLocale(code)}
+	f.Messages = make([]ARBMessage, len(terms))
+	for i, t := range terms {
+		f.Messages[i] = ARBMessage{TermTranslated: t}
+	}
+	return f
+}
+
+// Terms returns the terms of the ARB file, ready to be passed to
+// Project.AddTerms or Project.Sync
+func (f ARBFile) Terms() []Term {
+	ts := make([]Term, len(f.Messages))
+	for i, m := range f.Messages {
+		ts[i] = m.Term
+	}
+	return ts
+}
+
+// Translations returns the translations of the ARB file, ready to be passed
+// to Language.Update
+func (f ARBFile) Translations() []TermTranslation {
+	ts := make([]TermTranslation, len(f.Messages))
+	for i, m := range f.Messages {
+		ts[i] = TermTranslation{
+			TermBase:    m.TermBase,
+			Translation: Translation{Content: m.Translation.Content},
+		}
+	}
+	return ts
+}
+
+// ARB returns the terms and translations of the language as an ARB file
+func (l *Language) ARB() (ARBFile, error) {
+	terms, err := l.ListTerms()
+	if err != nil {
+		return ARBFile{}, err
+	}
+	return NewARBFile(l.Code, terms), nil
+}
+
+// ARBBundle returns an ARB file for each of the languages in the project,
+// keyed by language code
+func (p *Project) ARBBundle() (map[string]ARBFile, error) {
+	ls, err := p.ListLanguages()
+	if err != nil {
+		return nil, err
+	}
+	bundle := make(map[string]ARBFile, len(ls))
+	for i := range ls {
+		f, err := ls[i].ARB()
+		if err != nil {
+			return nil, err
+		}
+		bundle[ls[i].Code] = f
+	}
+	return bundle, nil
+}
+
+// pluralArgument picks the ICU argument name used when writing a plural
+// message. Numeric placeholders are preferred.
+func (m ARBMessage) pluralArgument() string {
+	names := make([]string, 0, len(m.Placeholders))
+	for n := range m.Placeholders {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	for _, n := range names {
+		switch m.Placeholders[n].Type {
+		case "num", "int", "double":
+			return n
+		}
+	}
+	if len(names) == 1 {
+		return names[0]
+	}
+	return "count"
+}
+
+// arbLocale converts a POEditor language code like pt-br to an ARB locale
+// like pt_BR
+func arbLocale(code string) string {
+	parts := strings.Split(code, "-")
+	parts[0] = strings.ToLower(parts[0])
+	for i := 1; i < len(parts); i++ {
+		switch len(parts[i]) {
+		case 2:
+			parts[i] = strings.ToUpper(parts[i])
+		case 4:
+			parts[i] = strings.ToUpper(parts[i][:1]) + strings.ToLower(parts[i][1:])
+		}
+	}
+	return strings.Join(parts, "_")
+}
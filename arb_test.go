@@ -0,0 +1,89 @@
+package poeditor_test
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/blacksails/poeditor"
+)
+
+const testARB = `{
+  "@@locale": "pt_BR",
+  "@@last_modified": "2020-01-01T00:00:00Z",
+  "hello": "Olá {name}",
+  "@hello": {
+    "description": "Greeting on the start page",
+    "placeholders": {
+      "name": {
+        "type": "String",
+        "example": "Bob"
+      }
+    }
+  },
+  "items": "{count, plural, zero{Nenhum item} one{# item} other{# itens}}",
+  "@items": {
+    "placeholders": {
+      "count": {
+        "type": "int"
+      }
+    }
+  },
+  "save": "Salvar",
+  "cart": "{count, plural, =0{Carrinho vazio} other{# no carrinho}}"
+}
+`
+
+func TestReadARB(t *testing.T) {
+	f, err := poeditor.ReadARB(strings.NewReader(testARB))
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if f.Locale != "pt_BR" {
+		t.Errorf("Expected locale pt_BR, got %s", f.Locale)
+	}
+	if f.Attributes["last_modified"] != "2020-01-01T00:00:00Z" {
+		t.Errorf("Expected last_modified attribute, got %+v", f.Attributes)
+	}
+	if len(f.Messages) != 4 {
+		t.Fatalf("Expected 4 messages, got %d", len(f.Messages))
+	}
+	hello := f.Messages[0]
+	if hello.Comment != "Greeting on the start page" {
+		t.Errorf("Expected description as comment, got %q", hello.Comment)
+	}
+	if hello.Placeholders["name"].Example != "Bob" {
+		t.Errorf("Expected placeholder metadata, got %+v", hello.Placeholders)
+	}
+	items := f.Messages[1]
+	expected := poeditor.Plural{Zero: "Nenhum item", One: "# item", Other: "# itens"}
+	if !reflect.DeepEqual(items.Translation.Content, expected) {
+		t.Errorf("\nExpected %+v \nGot      %+v", expected, items.Translation.Content)
+	}
+	if items.Plural != "items" {
+		t.Errorf("Expected plural term to be set, got %q", items.Plural)
+	}
+	cart := f.Messages[3]
+	if cart.Translation.Content != "{count, plural, =0{Carrinho vazio} other{# no carrinho}}" || cart.Plural != "" {
+		t.Errorf("Expected exact value plural to be kept as text, got %+v", cart)
+	}
+}
+
+func TestWriteARB(t *testing.T) {
+	f1, err := poeditor.ReadARB(strings.NewReader(testARB))
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	var buf bytes.Buffer
+	if err := poeditor.WriteARB(&buf, f1); err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	f2, err := poeditor.ReadARB(&buf)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if !reflect.DeepEqual(f1, f2) {
+		t.Errorf("\nExpected %+v \nGot      %+v", f1, f2)
+	}
+}
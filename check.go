@@ -0,0 +1,172 @@
+package poeditor
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrFilterUnsupported is returned from Project.Check when a rule forbids a
+// filter which cannot be evaluated from listed terms
+var ErrFilterUnsupported = errors.New("filter is not supported by checks")
+
+// Rule is a requirement on the translations of a project, e.g. all languages
+// are at least 95% translated, or no translation of a tag is fuzzy
+type Rule struct {
+	Name string `json:"name"`
+	// Languages limits the rule to the given language codes. By default the
+	// rule applies to all languages of the project.
+	Languages []string `json:"languages,omitempty"`
+	// Tags limits the rule to terms with at least one of the tags
+	Tags []string `json:"tags,omitempty"`
+	// MinPercentage is the minimum share of translated terms, from 0 to 100
+	MinPercentage float64 `json:"min_percentage,omitempty"`
+	// Forbid lists Filter constants no term may match, e.g. FilterFuzzy or
+	// FilterUntranslated
+	Forbid []string `json:"forbid,omitempty"`
+}
+
+// CheckViolation is a term matching a filter forbidden by a rule
+type CheckViolation struct {
+	TermBase
+	Filter string `json:"filter"`
+}
+
+// CheckResult is the outcome of a rule for a single language
+type CheckResult struct {
+	Rule     Rule   `json:"rule"`
+	Language string `json:"language"`
+	// Terms is the number of terms the rule applies to
+	Terms int `json:"terms"`
+	// Percentage is the share of translated terms, from 0 to 100
+	Percentage float64          `json:"percentage"`
+	Violations []CheckViolation `json:"violations,omitempty"`
+	Passed     bool             `json:"passed"`
+}
+
+// Message describes why the check passed or failed
+func (r CheckResult) Message() string {
+	var msgs []string
+	if r.Percentage < r.Rule.MinPercentage {
+		msgs = append(msgs, fmt.Sprintf("%.1f%% translated, %.1f%% required", r.Percentage, r.Rule.MinPercentage))
+	}
+	counts := make(map[string]int)
+	for _, v := range r.Violations {
+		counts[v.Filter]++
+	}
+	for _, f := range r.Rule.Forbid {
+		if counts[f] > 0 {
+			msgs = append(msgs, fmt.Sprintf("%d %s terms", counts[f], f))
+		}
+	}
+	if len(msgs) == 0 {
+		return fmt.Sprintf("%.1f%% translated", r.Percentage)
+	}
+	return strings.Join(msgs, ", ")
+}
+
+// CheckReport holds the results of Project.Check
+type CheckReport struct {
+	Results []CheckResult `json:"results"`
+}
+
+// Failed returns the results which did not pass
+func (r CheckReport) Failed() []CheckResult {
+	var failed []CheckResult
+	for _, res := range r.Results {
+		if !res.Passed {
+			failed = append(failed, res)
+		}
+	}
+	return failed
+}
+
+// checkFilters holds the filters checks can evaluate from listed terms
+var checkFilters = map[string]func(Translation) bool{
+	FilterTranslated:   translated,
+	FilterUntranslated: func(t Translation) bool { return !translated(t) },
+	FilterFuzzy:        func(t Translation) bool { return translated(t) && t.Fuzzy == 1 },
+	FilterNotFuzzy:     func(t Translation) bool { return translated(t) && t.Fuzzy == 0 },
+	FilterProofread:    func(t Translation) bool { return translated(t) && t.Proofread == 1 },
+	FilterNotProofread: func(t Translation) bool { return translated(t) && t.Proofread == 0 },
+}
+
+// Check evaluates the rules against the translations of the project. Every
+// rule yields a result per language it applies to. The terms of a language
+// are listed once however many rules use them.
+func (p *Project) Check(rules []Rule) (CheckReport, error) {
+	var report CheckReport
+	for _, r := range rules {
+		for _, f := range r.Forbid {
+			if checkFilters[f] == nil {
+				return report, fmt.Errorf("%w: %s", ErrFilterUnsupported, f)
+			}
+		}
+	}
+	langs, err := p.ListLanguages()
+	if err != nil {
+		return report, err
+	}
+	terms := make(map[string][]TermTranslated)
+	for _, r := range rules {
+		codes := r.Languages
+		if len(codes) == 0 {
+			for _, l := range langs {
+				codes = append(codes, l.Code)
+			}
+		}
+		for _, code := range codes {
+			ts, ok := terms[code]
+			if !ok {
+				l := Language{Project: p, Code: code}
+				if ts, err = l.ListTerms(); err != nil {
+					return report, err
+				}
+				terms[code] = ts
+			}
+			report.Results = append(report.Results, r.evaluate(code, ts))
+		}
+	}
+	return report, nil
+}
+
+func (r Rule) evaluate(code string, terms []TermTranslated) CheckResult {
+	res := CheckResult{Rule: r, Language: code, Percentage: 100}
+	var done int
+	for _, t := range terms {
+		if len(r.Tags) > 0 && !hasTag(t.Tags, r.Tags) {
+			continue
+		}
+		res.Terms++
+		if translated(t.Translation) {
+			done++
+		}
+		for _, f := range r.Forbid {
+			if checkFilters[f](t.Translation) {
+				res.Violations = append(res.Violations, CheckViolation{TermBase: t.TermBase, Filter: f})
+			}
+		}
+	}
+	if res.Terms > 0 {
+		res.Percentage = float64(done) * 100 / float64(res.Terms)
+	}
+	res.Passed = res.Percentage >= r.MinPercentage && len(res.Violations) == 0
+	return res
+}
+
+// translated reports whether a translation has content
+func translated(t Translation) bool {
+	return !EmptyContent(t.Content)
+}
+
+// hasTag reports whether tags contain any of want
+func hasTag(tags, want []string) bool {
+	for _, t := range tags {
+		for _, w := range want {
+			if t == w {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,181 @@
+package poeditor_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/blacksails/poeditor"
+)
+
+// checkProject serves a project with German and French translations and
+// counts the term lists per language
+func checkProject(t *testing.T, lists map[string]int) *poeditor.Project {
+	translation := func(term string, tags []string, content interface{}, fuzzy, proofread int) poeditor.TermTranslated {
+		tt := newTermTranslated(term, content, "")
+		tt.Tags = tags
+		tt.Translation.Fuzzy = fuzzy
+		tt.Translation.Proofread = proofread
+		return tt
+	}
+	translations := map[string][]poeditor.TermTranslated{
+		"de": {
+			translation("done", []string{"ui"}, "Fertig", 0, 1),
+			translation("fuzzy", []string{"ui"}, "Unscharf", 1, 0),
+			translation("plural", nil, poeditor.Plural{One: "Datei", Other: "Dateien"}, 0, 0),
+			translation("missing", []string{"ui"}, "", 0, 0),
+			translation("empty plural", nil, poeditor.Plural{}, 0, 0),
+		},
+		"fr": {
+			translation("done", []string{"ui"}, "Fini", 0, 1),
+			translation("fuzzy", []string{"ui"}, "Flou", 0, 1),
+			translation("plural", nil, poeditor.Plural{One: "fichier", Other: "fichiers"}, 0, 1),
+			translation("missing", []string{"ui"}, "Manquant", 0, 1),
+			translation("empty plural", nil, poeditor.Plural{One: "vide", Other: "vides"}, 0, 1),
+		},
+	}
+	poe := newTestPOEditor(t, func(endpoint string, form map[string]string) (interface{}, string) {
+		switch endpoint {
+		case "/languages/list":
+			return map[string]interface{}{"languages": []map[string]string{{"code": "de"}, {"code": "fr"}}}, ""
+		case "/terms/list":
+			lists[form["language"]]++
+			return map[string]interface{}{"terms": translations[form["language"]]}, ""
+		}
+		t.Errorf("Unexpected request to %s", endpoint)
+		return nil, "unexpected request"
+	})
+	return poe.Project(1)
+}
+
+func TestProjectCheck(t *testing.T) {
+	violations := func(filter string, terms ...string) []poeditor.CheckViolation {
+		var vs []poeditor.CheckViolation
+		for _, term := range terms {
+			vs = append(vs, poeditor.CheckViolation{TermBase: poeditor.TermBase{Term: term}, Filter: filter})
+		}
+		return vs
+	}
+	tests := []struct {
+		rule       poeditor.Rule
+		percentage float64
+		terms      int
+		violations []poeditor.CheckViolation
+		passed     bool
+		message    string
+	}{
+		{
+			rule:       poeditor.Rule{MinPercentage: 50},
+			percentage: 60, terms: 5, passed: true,
+			message: "60.0% translated",
+		},
+		{
+			rule:       poeditor.Rule{MinPercentage: 80},
+			percentage: 60, terms: 5,
+			message: "60.0% translated, 80.0% required",
+		},
+		{
+			rule:       poeditor.Rule{MinPercentage: 50, Tags: []string{"ui"}},
+			percentage: 200.0 / 3, terms: 3, passed: true,
+			message: "66.7% translated",
+		},
+		{
+			rule:       poeditor.Rule{Forbid: []string{poeditor.FilterTranslated}},
+			percentage: 60, terms: 5,
+			violations: violations(poeditor.FilterTranslated, "done", "fuzzy", "plural"),
+			message:    "3 translated terms",
+		},
+		{
+			rule:       poeditor.Rule{Forbid: []string{poeditor.FilterUntranslated}},
+			percentage: 60, terms: 5,
+			violations: violations(poeditor.FilterUntranslated, "missing", "empty plural"),
+			message:    "2 untranslated terms",
+		},
+		{
+			rule:       poeditor.Rule{Forbid: []string{poeditor.FilterFuzzy}, Tags: []string{"ui"}},
+			percentage: 200.0 / 3, terms: 3,
+			violations: violations(poeditor.FilterFuzzy, "fuzzy"),
+			message:    "1 fuzzy terms",
+		},
+		{
+			rule:       poeditor.Rule{Forbid: []string{poeditor.FilterNotFuzzy}},
+			percentage: 60, terms: 5,
+			violations: violations(poeditor.FilterNotFuzzy, "done", "plural"),
+			message:    "2 not_fuzzy terms",
+		},
+		{
+			rule:       poeditor.Rule{Forbid: []string{poeditor.FilterProofread}},
+			percentage: 60, terms: 5,
+			violations: violations(poeditor.FilterProofread, "done"),
+			message:    "1 proofread terms",
+		},
+		{
+			rule:       poeditor.Rule{Forbid: []string{poeditor.FilterNotProofread}},
+			percentage: 60, terms: 5,
+			violations: violations(poeditor.FilterNotProofread, "fuzzy", "plural"),
+			message:    "2 not_proofread terms",
+		},
+		{
+			rule:       poeditor.Rule{Tags: []string{"none"}, MinPercentage: 100},
+			percentage: 100, passed: true,
+			message: "100.0% translated",
+		},
+	}
+	for _, test := range tests {
+		test.rule.Name = "rule"
+		test.rule.Languages = []string{"de"}
+		report, err := checkProject(t, make(map[string]int)).Check([]poeditor.Rule{test.rule})
+		if err != nil {
+			t.Fatalf("%+v: Unexpected error: %s", test.rule, err)
+		}
+		expected := poeditor.CheckResult{
+			Rule:       test.rule,
+			Language:   "de",
+			Terms:      test.terms,
+			Percentage: test.percentage,
+			Violations: test.violations,
+			Passed:     test.passed,
+		}
+		if len(report.Results) != 1 || !reflect.DeepEqual(report.Results[0], expected) {
+			t.Errorf("\nExpected %+v \nGot      %+v", expected, report.Results)
+			continue
+		}
+		if msg := report.Results[0].Message(); msg != test.message {
+			t.Errorf("%+v: expected message %q, got %q", test.rule, test.message, msg)
+		}
+	}
+}
+
+func TestProjectCheckLanguages(t *testing.T) {
+	lists := make(map[string]int)
+	rules := []poeditor.Rule{
+		{Name: "complete", MinPercentage: 100},
+		{Name: "proofread", Languages: []string{"fr"}, Forbid: []string{poeditor.FilterNotProofread}},
+	}
+	report, err := checkProject(t, lists).Check(rules)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	var got []string
+	for _, res := range report.Results {
+		got = append(got, res.Rule.Name+"/"+res.Language)
+	}
+	if expected := []string{"complete/de", "complete/fr", "proofread/fr"}; !reflect.DeepEqual(got, expected) {
+		t.Errorf("\nExpected %v \nGot      %v", expected, got)
+	}
+	if !reflect.DeepEqual(lists, map[string]int{"de": 1, "fr": 1}) {
+		t.Errorf("Expected the terms of each language to be listed once, got %v", lists)
+	}
+	failed := report.Failed()
+	if len(failed) != 1 || failed[0].Language != "de" {
+		t.Errorf("Expected the German completeness check to fail, got %+v", failed)
+	}
+}
+
+func TestProjectCheckUnsupportedFilter(t *testing.T) {
+	p := checkProject(t, make(map[string]int))
+	_, err := p.Check([]poeditor.Rule{{Name: "automatic", Forbid: []string{poeditor.FilterAutomatic}}})
+	if !errors.Is(err, poeditor.ErrFilterUnsupported) {
+		t.Errorf("Expected ErrFilterUnsupported, got %v", err)
+	}
+}
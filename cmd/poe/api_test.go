@@ -0,0 +1,64 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+)
+
+// apiError is returned by a fake API handler to fail the request
+type apiError string
+
+// fakeAPI is a test server for the POEditor API. Handlers are keyed by
+// endpoint and get the form values of the request.
+type fakeAPI struct {
+	URL string
+
+	mu       sync.Mutex
+	requests []string
+}
+
+func newFakeAPI(t *testing.T, handlers map[string]func(form map[string]string) interface{}) *fakeAPI {
+	t.Helper()
+	api := &fakeAPI{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseMultipartForm(1 << 20); err != nil {
+			t.Errorf("Unexpected error: %s", err)
+			return
+		}
+		form := make(map[string]string)
+		for k, v := range r.MultipartForm.Value {
+			form[k] = v[0]
+		}
+		api.mu.Lock()
+		api.requests = append(api.requests, r.URL.Path)
+		api.mu.Unlock()
+		res := map[string]interface{}{
+			"response": map[string]string{"status": "success", "code": "200", "message": "OK"},
+		}
+		h, ok := handlers[r.URL.Path]
+		if !ok {
+			t.Errorf("Unexpected request to %s", r.URL.Path)
+			h = func(map[string]string) interface{} { return apiError("unexpected request") }
+		}
+		switch result := h(form).(type) {
+		case apiError:
+			res["response"] = map[string]string{"status": "fail", "code": "4011", "message": string(result)}
+		default:
+			res["result"] = result
+		}
+		json.NewEncoder(w).Encode(res)
+	}))
+	t.Cleanup(srv.Close)
+	api.URL = srv.URL
+	return api
+}
+
+// called returns the endpoints requested so far
+func (api *fakeAPI) called() []string {
+	api.mu.Lock()
+	defer api.mu.Unlock()
+	return append([]string(nil), api.requests...)
+}
@@ -0,0 +1,114 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+
+	"github.com/blacksails/poeditor"
+)
+
+// checkFormats write check reports
+var checkFormats = map[string]func(io.Writer, poeditor.CheckReport) error{
+	"text":  writeCheckText,
+	"json":  writeCheckJSON,
+	"junit": writeCheckJUnit,
+	"sarif": writeCheckSARIF,
+}
+
+func runCheck(a *app, args []string) error {
+	fs := a.flags("check")
+	r := a.repoFlags(fs)
+	format := fs.String("format", "text", "report format: text, json, junit or sarif")
+	output := fs.String("o", "-", "report file, - for stdout")
+	var adhoc poeditor.Rule
+	fs.Float64Var(&adhoc.MinPercentage, "min-percentage", 0, "minimum translated percentage")
+	forbid := fs.String("forbid", "", "comma separated filters no term may match, such as fuzzy or untranslated")
+	tags := fs.String("tags", "", "comma separated tags the -min-percentage and -forbid rule applies to")
+	if err := parse(fs, args, 0, 0); err != nil {
+		return err
+	}
+	write, ok := checkFormats[*format]
+	if !ok {
+		return fmt.Errorf("%w: unknown format %q", errUsage, *format)
+	}
+	adhoc.Forbid = splitList(*forbid)
+	adhoc.Tags = splitList(*tags)
+	var rules []poeditor.Rule
+	if adhoc.MinPercentage > 0 || len(adhoc.Forbid) > 0 {
+		adhoc.Name = "command line"
+		rules = append(rules, adhoc)
+	}
+	c, p, err := r.load()
+	if errors.Is(err, errUsage) && *r.config == "" && len(rules) > 0 {
+		// Rules given as flags do not need a config file
+		c = &repoConfig{Languages: splitList(*r.languages)}
+		p, err = r.api.projectClient()
+	}
+	if err != nil {
+		return err
+	}
+	rules = append(rules, c.Checks...)
+	if len(rules) == 0 {
+		return fmt.Errorf("%w: no rules, use -min-percentage, -forbid or the checks of the config file", errUsage)
+	}
+	for i := range rules {
+		if len(rules[i].Languages) == 0 {
+			rules[i].Languages = c.Languages
+		}
+	}
+	report, err := p.Check(rules)
+	if errors.Is(err, poeditor.ErrFilterUnsupported) {
+		return fmt.Errorf("%w: %s", errUsage, err)
+	}
+	if err != nil {
+		return err
+	}
+	var buf bytes.Buffer
+	if err := write(&buf, report); err != nil {
+		return err
+	}
+	if *output == "-" {
+		_, err = buf.WriteTo(a.stdout)
+	} else {
+		err = os.WriteFile(*output, buf.Bytes(), 0644)
+	}
+	if err != nil {
+		return err
+	}
+	if failed := report.Failed(); len(failed) > 0 {
+		return fmt.Errorf("%d of %d checks failed", len(failed), len(report.Results))
+	}
+	return nil
+}
+
+func writeCheckText(w io.Writer, report poeditor.CheckReport) error {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "RULE\tLANGUAGE\tRESULT\tMESSAGE")
+	for _, res := range report.Results {
+		result := "ok"
+		if !res.Passed {
+			result = "FAIL"
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", res.Rule.Name, res.Language, result, res.Message())
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+	for _, res := range report.Failed() {
+		for _, v := range res.Violations {
+			fmt.Fprintf(w, "%s %s: %s is %s\n", res.Rule.Name, res.Language, termName(v.TermBase), v.Filter)
+		}
+	}
+	return nil
+}
+
+func writeCheckJSON(w io.Writer, report poeditor.CheckReport) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(report)
+}
@@ -0,0 +1,35 @@
+package main
+
+import "testing"
+
+func TestCheck(t *testing.T) {
+	dir := t.TempDir()
+	config := writeFile(t, dir, ".poeditor.yml", `project_id: 1
+languages: [de]
+files:
+  - path: locales/{lang}.json
+    format: key_value_json
+checks:
+  - name: complete
+    min_percentage: 50
+`)
+	release := writeFile(t, dir, "release.yml", `project_id: 1
+languages: [de]
+files:
+  - path: locales/{lang}.json
+    format: key_value_json
+checks:
+  - name: release
+    forbid: [fuzzy]
+`)
+	testApp(t, []appTest{
+		{name: "check", args: []string{"check", "-config", config}, code: exitOK,
+			stdout: []string{"complete  de        ok"}},
+		{name: "check failed", args: []string{"check", "-config", release, "-format", "json"}, code: exitError,
+			stdout: []string{`"filter": "fuzzy"`}, stderr: []string{"1 of 1 checks failed"}},
+		{name: "check adhoc", args: []string{"check", "-config", config, "-min-percentage", "80"}, code: exitError,
+			stderr: []string{"checks failed"}},
+		{name: "check format", args: []string{"check", "-config", config, "-format", "xml"}, code: exitUsage,
+			stderr: []string{`unknown format "xml"`}},
+	})
+}
@@ -0,0 +1,91 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/blacksails/poeditor"
+)
+
+func runContributorsList(a *app, args []string) error {
+	fs := a.flags("contributors list")
+	o := a.apiFlags(fs, true)
+	out := a.outputFlags(fs)
+	language := fs.String("language", "", "list contributors of the language")
+	if err := parse(fs, args, 0, 0); err != nil {
+		return err
+	}
+	cs, err := listContributors(o, *language)
+	if err != nil {
+		return err
+	}
+	rows := make([][]string, len(cs))
+	for i, c := range cs {
+		perms := make([]string, len(c.Permissions))
+		for ip, p := range c.Permissions {
+			perms[ip] = p.ProjectName + ":" + p.Type
+		}
+		rows[i] = []string{c.Name, c.Email, strings.Join(perms, ", ")}
+	}
+	return out.print(cs, []string{"NAME", "EMAIL", "PERMISSIONS"}, rows)
+}
+
+// listContributors lists all contributors, those of the -project or those of
+// a language in it
+func listContributors(o *api, language string) ([]poeditor.Contributor, error) {
+	if *o.project == 0 {
+		if language != "" {
+			return nil, fmt.Errorf("%w: -language requires -project", errUsage)
+		}
+		poe, err := o.client()
+		if err != nil {
+			return nil, err
+		}
+		return poe.ListContributors()
+	}
+	p, err := o.projectClient()
+	if err != nil {
+		return nil, err
+	}
+	if language == "" {
+		return p.ListContributors()
+	}
+	l := poeditor.Language{Project: p, Code: language}
+	return l.ListContributors()
+}
+
+func runContributorsAdd(a *app, args []string) error {
+	fs := a.flags("contributors add")
+	o := a.apiFlags(fs, true)
+	language := fs.String("language", "", "add the contributor to the language instead of as admin")
+	if err := parse(fs, args, 2, 2); err != nil {
+		return err
+	}
+	p, err := o.projectClient()
+	if err != nil {
+		return err
+	}
+	if *language == "" {
+		return p.AddContributor(fs.Arg(0), fs.Arg(1))
+	}
+	l := poeditor.Language{Project: p, Code: *language}
+	return l.AddContributor(fs.Arg(0), fs.Arg(1))
+}
+
+func runContributorsRemove(a *app, args []string) error {
+	fs := a.flags("contributors remove")
+	o := a.apiFlags(fs, true)
+	language := fs.String("language", "", "remove the contributor from the language instead of as admin")
+	if err := parse(fs, args, 1, 1); err != nil {
+		return err
+	}
+	p, err := o.projectClient()
+	if err != nil {
+		return err
+	}
+	if *language == "" {
+		return p.RemoveContributor(fs.Arg(0))
+	}
+	l := poeditor.Language{Project: p, Code: *language}
+	return l.RemoveContributor(fs.Arg(0))
+}
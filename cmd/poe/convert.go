@@ -0,0 +1,67 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/blacksails/poeditor"
+)
+
+func runConvert(a *app, args []string) error {
+	fs := a.flags("convert")
+	from := fs.String("from", "", "source file format, defaults to the format matching the file extension")
+	to := fs.String("to", "", "target file format, defaults to the format matching the file extension")
+	language := fs.String("language", "", "language of files which do not state their own, such as a .po file without a Language header")
+	strict := fs.Bool("strict", false, "fail when data cannot be represented in the target format")
+	if err := parse(fs, args, 2, 2); err != nil {
+		return err
+	}
+	src, dst := fs.Arg(0), fs.Arg(1)
+	srcFormat, err := formatFor(*from, src)
+	if err != nil {
+		return err
+	}
+	dstFormat, err := formatFor(*to, dst)
+	if err != nil {
+		return err
+	}
+	var in io.Reader = a.stdin
+	if src != "-" {
+		f, err := os.Open(src)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		in = f
+	}
+	// Convert into memory first, so a failed conversion leaves dst untouched
+	var out bytes.Buffer
+	report, err := poeditor.ConvertLanguage(in, srcFormat, &out, dstFormat, *language)
+	if err != nil {
+		return err
+	}
+	for _, l := range report.Losses {
+		fmt.Fprintf(a.stderr, "warning: %s\n", l)
+	}
+	if *strict && len(report.Losses) > 0 {
+		return fmt.Errorf("%d terms cannot be represented as %s", len(report.Losses), dstFormat)
+	}
+	if dst == "-" {
+		_, err = out.WriteTo(a.stdout)
+		return err
+	}
+	return os.WriteFile(dst, out.Bytes(), 0644)
+}
+
+// formatFor returns the given format or the one matching the file name
+func formatFor(format, name string) (string, error) {
+	if format != "" {
+		return format, nil
+	}
+	if format = poeditor.FormatFromFilename(name); format == "" {
+		return "", fmt.Errorf("%w: cannot determine format of %s, use -from or -to", errUsage, name)
+	}
+	return format, nil
+}
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestConvert(t *testing.T) {
+	dir := t.TempDir()
+	src := writeFile(t, dir, "files.arb", `{
+  "files": "{count, plural, one{# файл} few{# файла} many{# файлов} other{# файла}}"
+}`)
+	tests := []struct {
+		name   string
+		args   []string
+		code   int
+		po     []string
+		stderr []string
+	}{
+		{name: "language", args: []string{"-language", "ru"}, code: exitOK,
+			po:     []string{"Language: ru", "nplurals=3", `msgstr[2] "# файлов"`},
+			stderr: []string{"files: plural form lost"}},
+		{name: "without language", code: exitOK,
+			po: []string{"nplurals=2", `msgstr[1] "# файла"`}, stderr: []string{"files: plural form lost"}},
+		{name: "strict", args: []string{"-language", "ru", "-strict"}, code: exitError,
+			stderr: []string{"1 terms cannot be represented as po"}},
+	}
+	for _, test := range tests {
+		dst := writeFile(t, dir, test.name+".po", "")
+		args := append(append([]string{"convert"}, test.args...), src, dst)
+		code, stdout, stderr := runApp(t, nil, nil, "", args...)
+		if code != test.code {
+			t.Errorf("%s: expected exit code %d, got %d\nstdout: %s\nstderr: %s", test.name, test.code, code, stdout, stderr)
+			continue
+		}
+		b, err := os.ReadFile(dst)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, want := range test.po {
+			if !strings.Contains(string(b), want) {
+				t.Errorf("%s: expected the .po file to contain %q\nGot\n%s", test.name, want, b)
+			}
+		}
+		for _, want := range test.stderr {
+			if !strings.Contains(stderr, want) {
+				t.Errorf("%s: expected stderr to contain %q\nGot\n%s", test.name, want, stderr)
+			}
+		}
+	}
+}
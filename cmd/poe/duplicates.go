@@ -0,0 +1,92 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"strings"
+
+	"github.com/blacksails/poeditor"
+	"github.com/blacksails/poeditor/duplicates"
+)
+
+func runDuplicates(a *app, args []string) error {
+	fs := a.flags("duplicates")
+	o := a.apiFlags(fs, true)
+	out := a.outputFlags(fs)
+	source := fs.String("source", "", "source language code, defaults to the reference language of the project")
+	keep := fs.String("keep", "", "consolidate the group of this term into it, deleting the other terms")
+	context := fs.String("context", "", "context of the -keep term")
+	yes := fs.Bool("yes", false, "consolidate without asking, requires -keep")
+	if err := parse(fs, args, 0, 0); err != nil {
+		return err
+	}
+	if *yes && *keep == "" {
+		return fmt.Errorf("%w: -yes requires -keep", errUsage)
+	}
+	p, err := o.projectClient()
+	if err != nil {
+		return err
+	}
+	if *source == "" {
+		view, err := p.POEditor.ViewProject(p.ID)
+		if err != nil {
+			return err
+		}
+		if *source = view.ReferenceLanguage; *source == "" {
+			return fmt.Errorf("%w: the project has no reference language, use -source", errUsage)
+		}
+	}
+	groups, err := duplicates.Analyze(p, *source)
+	if err != nil {
+		return err
+	}
+	if *keep != "" {
+		return consolidate(a, p, groups, poeditor.TermBase{Term: *keep, Context: *context}, *yes)
+	}
+	var rows [][]string
+	for _, g := range groups {
+		divergent := strings.Join(g.Divergent, ",")
+		for _, t := range g.Terms {
+			rows = append(rows, []string{g.Source, g.Kind, termName(t.TermBase), divergent})
+		}
+	}
+	if groups == nil {
+		groups = []duplicates.Group{}
+	}
+	return out.print(groups, []string{"SOURCE", "KIND", "TERM", "DIVERGENT"}, rows)
+}
+
+// consolidate merges the group holding the term into it, after asking for
+// confirmation unless yes is set
+func consolidate(a *app, p *poeditor.Project, groups []duplicates.Group, keep poeditor.TermBase, yes bool) error {
+	for _, g := range groups {
+		m, err := g.Merge(keep)
+		if err == duplicates.ErrNotInGroup {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		names := make([]string, len(m.Delete))
+		for i, t := range m.Delete {
+			names[i] = termName(t)
+		}
+		fmt.Fprintf(a.stderr, "keeping %s, deleting %s\n", termName(keep), strings.Join(names, ", "))
+		if len(g.Divergent) > 0 {
+			fmt.Fprintf(a.stderr, "translations diverge in %s, those of %s are kept\n", strings.Join(g.Divergent, ", "), termName(keep))
+		}
+		if !yes {
+			fmt.Fprintf(a.stderr, "consolidate %d terms? [y/N] ", len(g.Terms))
+			answer, _ := bufio.NewReader(a.stdin).ReadString('\n')
+			if answer = strings.ToLower(strings.TrimSpace(answer)); answer != "y" && answer != "yes" {
+				return nil
+			}
+		}
+		if _, err := duplicates.Consolidate(p, g, keep); err != nil {
+			return err
+		}
+		fmt.Fprintf(a.stderr, "consolidated %d terms into %s\n", len(g.Terms), termName(keep))
+		return nil
+	}
+	return fmt.Errorf("%w: %s has no duplicates", errUsage, termName(keep))
+}
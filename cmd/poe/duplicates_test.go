@@ -0,0 +1,16 @@
+package main
+
+import "testing"
+
+func TestDuplicates(t *testing.T) {
+	testApp(t, []appTest{
+		{name: "duplicates", args: []string{"duplicates", "-project", "1"}, code: exitOK,
+			stdout: []string{"save", "store", "de"}},
+		{name: "duplicates declined", args: []string{"duplicates", "-project", "1", "-keep", "save"}, stdin: "n\n",
+			code: exitOK, stderr: []string{"keeping save, deleting store", "consolidate 2 terms? [y/N]"}},
+		{name: "duplicates none", args: []string{"duplicates", "-project", "1", "-keep", "welcome", "-yes"},
+			code: exitUsage, stderr: []string{"welcome has no duplicates"}},
+		{name: "duplicates yes", args: []string{"duplicates", "-project", "1", "-yes"}, code: exitUsage,
+			stderr: []string{"-yes requires -keep"}},
+	})
+}
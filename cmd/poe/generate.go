@@ -0,0 +1,123 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/blacksails/poeditor"
+	"github.com/blacksails/poeditor/codegen"
+)
+
+func runGenerate(a *app, args []string) error {
+	fs := a.flags("generate")
+	o := a.apiFlags(fs, true)
+	from := fs.String("from", "", "read the terms from an exported file instead of the project")
+	format := fs.String("format", "", "format of the -from file, defaults to the format matching the file extension")
+	language := fs.String("language", "", "read placeholders from the translations of the language, usually the source language")
+	pkg := fs.String("package", a.getenv("GOPACKAGE"), "package name, defaults to $GOPACKAGE as set by go generate")
+	typ := fs.String("type", "Messages", "name of the generated type")
+	xtext := fs.Bool("xtext", false, "generate a golang.org/x/text message catalog of the project instead of accessors")
+	languages := fs.String("languages", "", "comma separated languages of the -xtext catalog, defaults to all")
+	fallback := fs.String("fallback", "", "fallback language of the -xtext catalog, defaults to the reference language")
+	variable := fs.String("var", "", "variable holding the -xtext catalog, which is installed as message.DefaultCatalog by default")
+	output := fs.String("o", "-", "output file, - for stdout")
+	if err := parse(fs, args, 0, 0); err != nil {
+		return err
+	}
+	if *pkg == "" {
+		return fmt.Errorf("%w: -package is required", errUsage)
+	}
+	var (
+		src []byte
+		err error
+	)
+	if *xtext {
+		if *from != "" {
+			return fmt.Errorf("%w: -xtext reads the translations from the project, not -from", errUsage)
+		}
+		src, err = generateXText(o, splitList(*languages), *fallback,
+			codegen.XTextCatalog{Package: *pkg, Var: *variable, Command: "poe generate"})
+	} else {
+		src, err = generateAccessors(o, *from, *format, *language,
+			codegen.Accessors{Package: *pkg, Type: *typ, Command: "poe generate"})
+	}
+	if err != nil {
+		return err
+	}
+	if *output == "-" {
+		_, err = a.stdout.Write(src)
+		return err
+	}
+	return os.WriteFile(*output, src, 0644)
+}
+
+// generateAccessors reads the terms from a file, the translations of a
+// language or the terms of the project, in that order
+func generateAccessors(o *api, from, format, language string, acc codegen.Accessors) ([]byte, error) {
+	var terms []poeditor.TermTranslated
+	if from != "" {
+		f, err := formatFor(format, from)
+		if err != nil {
+			return nil, err
+		}
+		if terms, err = decodeFile(from, f); err != nil {
+			return nil, err
+		}
+		return acc.Generate(terms)
+	}
+	p, err := o.projectClient()
+	if err != nil {
+		return nil, err
+	}
+	if language != "" {
+		l := poeditor.Language{Project: p, Code: language}
+		if terms, err = l.ListTerms(); err != nil {
+			return nil, err
+		}
+		return acc.Generate(terms)
+	}
+	list, err := p.ListTerms()
+	if err != nil {
+		return nil, err
+	}
+	for _, t := range list {
+		terms = append(terms, poeditor.TermTranslated{Term: t})
+	}
+	return acc.Generate(terms)
+}
+
+// generateXText downloads the translations of the languages, or of every
+// language of the project when none are given
+func generateXText(o *api, languages []string, fallback string, cat codegen.XTextCatalog) ([]byte, error) {
+	p, err := o.projectClient()
+	if err != nil {
+		return nil, err
+	}
+	if fallback == "" {
+		view, err := p.POEditor.ViewProject(p.ID)
+		if err != nil {
+			return nil, err
+		}
+		fallback = view.ReferenceLanguage
+	}
+	cat.Fallback = fallback
+	if len(languages) == 0 {
+		ls, err := p.ListLanguages()
+		if err != nil {
+			return nil, err
+		}
+		for _, l := range ls {
+			languages = append(languages, l.Code)
+		}
+	}
+	translations := make(map[string][]poeditor.TermTranslated)
+	for _, code := range languages {
+		l := poeditor.Language{Project: p, Code: code}
+		terms, err := l.ListTerms()
+		if err != nil {
+			return nil, err
+		}
+		translations[code] = terms
+	}
+	return cat.Generate(translations)
+}
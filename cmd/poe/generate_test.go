@@ -0,0 +1,14 @@
+package main
+
+import "testing"
+
+func TestGenerate(t *testing.T) {
+	testApp(t, []appTest{
+		{name: "generate", args: []string{"generate", "-project", "1", "-package", "msgs"}, code: exitOK,
+			stdout: []string{"package msgs", "func (m Messages) Welcome() string"}},
+		{name: "generate xtext", args: []string{"generate", "-xtext", "-project", "1", "-package", "msgs", "-var", "Catalog"},
+			code: exitOK, stdout: []string{`langDe := language.MustParse("de")`, `"Speichern"`}},
+		{name: "generate package", args: []string{"generate", "-project", "1"}, code: exitUsage,
+			stderr: []string{"-package is required"}},
+	})
+}
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/blacksails/poeditor/glossary"
+)
+
+func runGlossary(a *app, args []string) error {
+	fs := a.flags("glossary")
+	o := a.apiFlags(fs, true)
+	out := a.outputFlags(fs)
+	file := fs.String("glossary", "", "glossary file, YAML or JSON")
+	comment := fs.Bool("comment", false, "comment on the terms with violations")
+	if err := parse(fs, args, 0, 0); err != nil {
+		return err
+	}
+	if *file == "" {
+		return fmt.Errorf("%w: -glossary is required", errUsage)
+	}
+	g, err := glossary.Load(*file)
+	if err != nil {
+		return err
+	}
+	poe, err := o.client()
+	if err != nil {
+		return err
+	}
+	var violations []glossary.Violation
+	if *o.project != 0 {
+		violations, err = g.CheckProject(poe.Project(*o.project))
+	} else {
+		violations, err = g.CheckAll(poe)
+	}
+	if err != nil {
+		return err
+	}
+	if *comment {
+		c, err := glossary.AddComments(poe, violations)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(a.stderr, "commented on %d terms\n", c.WithAddedComment)
+	}
+	rows := make([][]string, len(violations))
+	for i, v := range violations {
+		rows[i] = []string{strconv.Itoa(v.Project), v.Language, termName(v.TermBase), v.Entry, v.Message}
+	}
+	if violations == nil {
+		violations = []glossary.Violation{}
+	}
+	if err := out.print(violations, []string{"PROJECT", "LANGUAGE", "TERM", "ENTRY", "MESSAGE"}, rows); err != nil {
+		return err
+	}
+	if len(violations) > 0 {
+		return fmt.Errorf("%d glossary violations", len(violations))
+	}
+	return nil
+}
@@ -0,0 +1,19 @@
+package main
+
+import "testing"
+
+func TestGlossary(t *testing.T) {
+	dir := t.TempDir()
+	glossaryFile := writeFile(t, dir, "glossary.yml", `source_language: en
+entries:
+  - term: workspace
+    translations: {de: Arbeitsbereich}
+`)
+	testApp(t, []appTest{
+		{name: "glossary", args: []string{"glossary", "-glossary", glossaryFile, "-project", "1"}, code: exitError,
+			stdout: []string{"workspace", "Arbeitsbereich"}, stderr: []string{"1 glossary violations"}},
+		{name: "glossary json", args: []string{"glossary", "-json", "-glossary", glossaryFile, "-project", "1"}, code: exitError,
+			stdout: []string{`"entry": "workspace"`}},
+		{name: "glossary file", args: []string{"glossary"}, code: exitUsage, stderr: []string{"-glossary is required"}},
+	})
+}
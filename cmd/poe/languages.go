@@ -0,0 +1,90 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/blacksails/poeditor"
+)
+
+func runLanguagesList(a *app, args []string) error {
+	fs := a.flags("languages list")
+	o := a.apiFlags(fs, true)
+	out := a.outputFlags(fs)
+	if err := parse(fs, args, 0, 0); err != nil {
+		return err
+	}
+	p, err := o.projectClient()
+	if err != nil {
+		return err
+	}
+	ls, err := p.ListLanguages()
+	if err != nil {
+		return err
+	}
+	views := make([]languageView, len(ls))
+	rows := make([][]string, len(ls))
+	for i, l := range ls {
+		views[i] = newLanguageView(l)
+		rows[i] = []string{l.Code, l.Name, strconv.Itoa(l.Translations),
+			fmt.Sprintf("%.1f%%", l.Percentage), formatTime(l.Updated)}
+	}
+	return out.print(views, []string{"CODE", "NAME", "TRANSLATIONS", "COMPLETE", "UPDATED"}, rows)
+}
+
+func runLanguagesAdd(a *app, args []string) error {
+	fs := a.flags("languages add")
+	o := a.apiFlags(fs, true)
+	if err := parse(fs, args, 1, 1); err != nil {
+		return err
+	}
+	p, err := o.projectClient()
+	if err != nil {
+		return err
+	}
+	return p.AddLanguage(fs.Arg(0))
+}
+
+func runLanguagesDelete(a *app, args []string) error {
+	fs := a.flags("languages delete")
+	o := a.apiFlags(fs, true)
+	if err := parse(fs, args, 1, 1); err != nil {
+		return err
+	}
+	p, err := o.projectClient()
+	if err != nil {
+		return err
+	}
+	l := poeditor.Language{Project: p, Code: fs.Arg(0)}
+	return l.Delete()
+}
+
+type languageView struct {
+	Code         string     `json:"code"`
+	Name         string     `json:"name"`
+	Translations int        `json:"translations"`
+	Percentage   float32    `json:"percentage"`
+	Updated      *time.Time `json:"updated,omitempty"`
+}
+
+func newLanguageView(l poeditor.Language) languageView {
+	v := languageView{
+		Code:         l.Code,
+		Name:         l.Name,
+		Translations: l.Translations,
+		Percentage:   l.Percentage,
+	}
+	if !l.Updated.IsZero() {
+		v.Updated = &l.Updated
+	}
+	return v
+}
+
+// formatTime formats a time for tables, the zero time is left empty
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Format("2006-01-02 15:04")
+}
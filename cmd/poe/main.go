@@ -0,0 +1,407 @@
+// Command poe is a command line interface to POEditor built on the poeditor
+// package.
+//
+// Usage:
+//
+//	poe <command> [flags] [arguments]
+//
+// Run poe help for a list of commands. Commands talking to the API read the
+// API token from the -token flag, the POEDITOR_API_TOKEN environment variable
+// or the api_token field of the poe/config.json file in the user config
+// directory, in that order. The POEDITOR_API_URL environment variable
+// overrides the API endpoint.
+//
+// The exit code is 2 for usage errors, 3 for errors returned by the POEditor
+// API and 1 for any other error.
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/blacksails/poeditor"
+)
+
+// Exit codes
+const (
+	exitOK    = 0
+	exitError = 1
+	exitUsage = 2
+	exitAPI   = 3
+)
+
+// errUsage is returned by commands when they are invoked incorrectly
+var errUsage = errors.New("usage error")
+
+type command struct {
+	usage   string
+	summary string
+	run     func(app *app, args []string) error
+}
+
+// commands are keyed by their name, which is one or two words
+var commands = map[string]command{
+	"check": {
+		usage:   "check [-format text|json|junit|sarif] [-o file] [-config file] [-project id] [-languages a,b] [-min-percentage n] [-forbid filters] [-tags a,b]",
+		summary: "check translations against rules, failing when a rule is broken",
+		run:     runCheck,
+	},
+	"convert": {
+		usage:   "convert [-from format] [-to format] [-language code] [-strict] <src> <dst>",
+		summary: "convert a file between formats",
+		run:     runConvert,
+	},
+	"duplicates": {
+		usage:   "duplicates [-json] -project id [-source code] [-keep term [-context c] [-yes]]",
+		summary: "find terms with the same source text, or consolidate them into the -keep term",
+		run:     runDuplicates,
+	},
+	"generate": {
+		usage:   "generate [-package name] [-o file] ([-type name] (-from file [-format format] | -project id [-language code]) | -xtext -project id [-languages a,b] [-fallback code] [-var name])",
+		summary: "generate typed Go accessors for the terms, or a golang.org/x/text catalog",
+		run:     runGenerate,
+	},
+	"glossary": {
+		usage:   "glossary [-json] -glossary file [-project id] [-comment]",
+		summary: "check the translations of one or every project against a glossary",
+		run:     runGlossary,
+	},
+	"pseudo": {
+		usage:   "pseudo -project id [-source code] [-expansion f] [-no-accents] [-no-brackets] (-push code | [-format format] [-o file])",
+		summary: "generate accented, expanded and bracketed pseudo translations for UI testing",
+		run:     runPseudo,
+	},
+	"pull": {
+		usage:   "pull [-config file] [-project id] [-languages a,b] [-dry-run]",
+		summary: "export the files of the repo config",
+		run:     runPull,
+	},
+	"push": {
+		usage:   "push [-json] [-config file] [-project id] [-languages a,b] [-terms-only] [-interval d] [-dry-run]",
+		summary: "upload the source terms and translations of the repo config",
+		run:     runPush,
+	},
+	"qa": {
+		usage:   "qa [-json] -project id -language code [-source code] [-severity error|warning|info] [-comment]",
+		summary: "check translations for broken placeholders, markup and formatting",
+		run:     runQA,
+	},
+	"status": {
+		usage:   "status [-json] [-config file] [-project id] [-languages a,b]",
+		summary: "show translation progress and drift between the repo config files and the project",
+		run:     runStatus,
+	},
+	"tm build": {
+		usage:   "tm build [-o file]",
+		summary: "build a translation memory from the translations of all projects",
+		run:     runTMBuild,
+	},
+	"tm suggest": {
+		usage:   "tm suggest [-json] -memory file -project id -language code [-source code] [-min score] [-prefill]",
+		summary: "suggest translation memory matches for untranslated terms, or write them with -prefill",
+		run:     runTMSuggest,
+	},
+	"usage": {
+		usage:   "usage [-json] -project id [-funcs specs] [-tests] [-tag-obsolete [-tag name]] [dir]",
+		summary: "report terms unused in the Go code and terms missing in the project",
+		run:     runUsage,
+	},
+	"watch": {
+		usage:   "watch [-config file] [-project id] [-poll d] [-debounce d] [-interval d] [-fuzzy-trigger] [-delete [-yes]]",
+		summary: "push changes to the source language files of the repo config as they happen",
+		run:     runWatch,
+	},
+	"projects list": {
+		usage:   "projects list [-json]",
+		summary: "list projects",
+		run:     runProjectsList,
+	},
+	"projects view": {
+		usage:   "projects view [-json] <id>",
+		summary: "show a project",
+		run:     runProjectsView,
+	},
+	"projects add": {
+		usage:   "projects add [-json] [-description text] <name>",
+		summary: "create a project",
+		run:     runProjectsAdd,
+	},
+	"projects update": {
+		usage:   "projects update [-json] [-name name] [-description text] [-reference-language code] <id>",
+		summary: "update a project",
+		run:     runProjectsUpdate,
+	},
+	"projects delete": {
+		usage:   "projects delete <id>",
+		summary: "delete a project",
+		run:     runProjectsDelete,
+	},
+	"languages list": {
+		usage:   "languages list [-json] -project id",
+		summary: "list the languages of a project",
+		run:     runLanguagesList,
+	},
+	"languages add": {
+		usage:   "languages add -project id <code>",
+		summary: "add a language to a project",
+		run:     runLanguagesAdd,
+	},
+	"languages delete": {
+		usage:   "languages delete -project id <code>",
+		summary: "delete a language from a project",
+		run:     runLanguagesDelete,
+	},
+	"terms list": {
+		usage:   "terms list [-json] -project id [-language code]",
+		summary: "list terms, optionally with translations",
+		run:     runTermsList,
+	},
+	"terms add": {
+		usage:   "terms add [-json] -project id [-context c] [-plural p] [-reference r] [-comment c] [-tags a,b] <term> | -file terms.json",
+		summary: "add terms",
+		run:     runTermsAdd,
+	},
+	"terms update": {
+		usage:   "terms update [-json] -project id [-context c] [-new-term t] [-new-context c] [-plural p] [-reference r] [-comment c] [-tags a,b] [-fuzzy-trigger] <term> | -file terms.json",
+		summary: "update terms",
+		run:     runTermsUpdate,
+	},
+	"terms delete": {
+		usage:   "terms delete [-json] -project id [-context c] <term>...",
+		summary: "delete terms",
+		run:     runTermsDelete,
+	},
+	"terms comment": {
+		usage:   "terms comment [-json] -project id [-context c] <term> <comment>",
+		summary: "comment on a term",
+		run:     runTermsComment,
+	},
+	"contributors list": {
+		usage:   "contributors list [-json] [-project id [-language code]]",
+		summary: "list contributors",
+		run:     runContributorsList,
+	},
+	"contributors add": {
+		usage:   "contributors add -project id [-language code] <name> <email>",
+		summary: "add a project admin, or a language contributor with -language",
+		run:     runContributorsAdd,
+	},
+	"contributors remove": {
+		usage:   "contributors remove -project id [-language code] <email>",
+		summary: "remove a project admin, or a language contributor with -language",
+		run:     runContributorsRemove,
+	},
+	"upload": {
+		usage:   "upload [-json] -project id [-updating mode] [-language code] [-overwrite] [-sync-terms] [-tags a,b] [-read-from-source] [-fuzzy-trigger] <file>",
+		summary: "validate and upload a file",
+		run:     runUpload,
+	},
+	"export": {
+		usage:   "export -project id -language code [-format format] [-filters a,b] [-tags a,b] [-o file]",
+		summary: "export a language",
+		run:     runExport,
+	},
+}
+
+type app struct {
+	stdin  io.Reader
+	stdout io.Writer
+	stderr io.Writer
+	getenv func(string) string
+}
+
+func main() {
+	a := &app{stdin: os.Stdin, stdout: os.Stdout, stderr: os.Stderr, getenv: os.Getenv}
+	os.Exit(a.run(os.Args[1:]))
+}
+
+func (a *app) run(args []string) int {
+	if len(args) == 0 || args[0] == "help" || args[0] == "-h" || args[0] == "-help" {
+		a.usage()
+		if len(args) == 0 {
+			return exitUsage
+		}
+		return exitOK
+	}
+	name := args[0]
+	cmd, ok := commands[name]
+	if !ok && len(args) > 1 {
+		name = args[0] + " " + args[1]
+		cmd, ok = commands[name]
+	}
+	if !ok {
+		fmt.Fprintf(a.stderr, "poe: unknown command %q\n", args[0])
+		a.usage()
+		return exitUsage
+	}
+	err := cmd.run(a, args[len(strings.Fields(name)):])
+	var apiErr poeditor.Error
+	switch {
+	case err == nil:
+		return exitOK
+	case err == flag.ErrHelp:
+		return exitOK
+	case errors.Is(err, errUsage):
+		if err != errUsage {
+			fmt.Fprintf(a.stderr, "poe %s: %s\n", name, err)
+		}
+		fmt.Fprintf(a.stderr, "usage: poe %s\n", cmd.usage)
+		return exitUsage
+	case errors.As(err, &apiErr):
+		fmt.Fprintf(a.stderr, "poe %s: %s\n", name, err)
+		return exitAPI
+	default:
+		fmt.Fprintf(a.stderr, "poe %s: %s\n", name, err)
+		return exitError
+	}
+}
+
+func (a *app) usage() {
+	fmt.Fprintln(a.stderr, "usage: poe <command> [flags] [arguments]")
+	fmt.Fprintln(a.stderr, "\ncommands:")
+	names := make([]string, 0, len(commands))
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fmt.Fprintf(a.stderr, "  %-20s %s\n", name, commands[name].summary)
+	}
+}
+
+// flags returns a flag set for a command which reports parse errors as usage
+// errors
+func (a *app) flags(name string) *flag.FlagSet {
+	fs := flag.NewFlagSet("poe "+name, flag.ContinueOnError)
+	fs.SetOutput(a.stderr)
+	return fs
+}
+
+// parse parses the flags of a command, and checks the number of positional
+// arguments is between min and max. A negative max means no limit.
+func parse(fs *flag.FlagSet, args []string, min, max int) error {
+	if err := fs.Parse(args); err != nil {
+		if err == flag.ErrHelp {
+			return err
+		}
+		return errUsage
+	}
+	if fs.NArg() < min || (max >= 0 && fs.NArg() > max) {
+		return fmt.Errorf("%w: expected %s arguments", errUsage, argCount(min, max))
+	}
+	return nil
+}
+
+func argCount(min, max int) string {
+	switch {
+	case min == max:
+		return fmt.Sprint(min)
+	case max < 0:
+		return fmt.Sprintf("at least %d", min)
+	}
+	return fmt.Sprintf("%d to %d", min, max)
+}
+
+// api holds the flags shared by commands talking to the API
+type api struct {
+	app     *app
+	token   *string
+	project *int
+}
+
+// apiFlags registers the -token flag, and the -project flag if withProject is
+// set
+func (a *app) apiFlags(fs *flag.FlagSet, withProject bool) *api {
+	o := &api{app: a}
+	o.token = fs.String("token", "", "POEditor API token")
+	if withProject {
+		id, _ := strconv.Atoi(a.getenv("POEDITOR_PROJECT_ID"))
+		o.project = fs.Int("project", id, "project id, defaults to $POEDITOR_PROJECT_ID")
+	}
+	return o
+}
+
+// client returns a client using the first API token found in the -token flag,
+// the environment and the user config file, and the endpoint of
+// $POEDITOR_API_URL if set
+func (o *api) client() (*poeditor.POEditor, error) {
+	token := *o.token
+	if token == "" {
+		token = o.app.getenv("POEDITOR_API_TOKEN")
+	}
+	if token == "" {
+		token = userConfig().APIToken
+	}
+	if token == "" {
+		return nil, fmt.Errorf("%w: no API token, use -token, $POEDITOR_API_TOKEN or %s", errUsage, userConfigPath())
+	}
+	endpoint := o.app.getenv("POEDITOR_API_URL")
+	if endpoint == "" {
+		endpoint = poeditor.DefaultEndpoint
+	}
+	return poeditor.NewWithEndpoint(token, endpoint), nil
+}
+
+// projectClient returns the project given by the -project flag
+func (o *api) projectClient() (*poeditor.Project, error) {
+	if *o.project == 0 {
+		return nil, fmt.Errorf("%w: -project is required", errUsage)
+	}
+	poe, err := o.client()
+	if err != nil {
+		return nil, err
+	}
+	return poe.Project(*o.project), nil
+}
+
+type config struct {
+	APIToken string `json:"api_token"`
+}
+
+func userConfigPath() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "poe", "config.json")
+}
+
+// userConfig reads the user config file. A missing file yields an empty
+// config.
+func userConfig() config {
+	var c config
+	b, err := os.ReadFile(userConfigPath())
+	if err != nil {
+		return c
+	}
+	json.Unmarshal(b, &c)
+	return c
+}
+
+// splitList splits a comma separated flag value
+func splitList(s string) []string {
+	var list []string
+	for _, v := range strings.Split(s, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			list = append(list, v)
+		}
+	}
+	return list
+}
+
+// projectID parses a project id argument
+func projectID(s string) (int, error) {
+	id, err := strconv.Atoi(s)
+	if err != nil {
+		return 0, fmt.Errorf("%w: invalid project id %q", errUsage, s)
+	}
+	return id, nil
+}
@@ -0,0 +1,242 @@
+package main
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/blacksails/poeditor"
+)
+
+// projectAPI serves project 1, with English as reference language and a
+// German translation holding an untranslated term, an empty plural, an
+// unproofread and a fuzzy translation. Russian has only the plural
+// translated. Other projects do not exist.
+func projectAPI(t *testing.T) *fakeAPI {
+	term := func(term, plural string, content interface{}, fuzzy, proofread int) poeditor.TermTranslated {
+		var tt poeditor.TermTranslated
+		tt.Term.Term = term
+		tt.Plural = plural
+		tt.Translation = poeditor.Translation{Content: content, Fuzzy: fuzzy, Proofread: proofread}
+		return tt
+	}
+	translations := map[string][]poeditor.TermTranslated{
+		"en": {
+			term("welcome", "", "Welcome", 0, 1),
+			term("files", "files", poeditor.Plural{One: "%d file", Other: "%d files"}, 0, 1),
+			term("save", "", "Save", 0, 1),
+			term("store", "", "Save", 0, 1),
+			term("workspace", "", "Open workspace", 0, 1),
+		},
+		"de": {
+			term("welcome", "", "", 0, 0),
+			term("files", "files", poeditor.Plural{}, 0, 0),
+			term("save", "", "Speichern", 0, 0),
+			term("store", "", "Sichern", 1, 0),
+			term("workspace", "", "Workspace öffnen", 0, 1),
+		},
+		"ru": {
+			term("files", "files", poeditor.Plural{One: "%d файл", Few: "%d файла", Many: "%d файлов"}, 0, 0),
+		},
+	}
+	project := map[string]interface{}{"id": 1, "name": "Web", "reference_language": "en", "terms": 5, "created": ""}
+	count := func(form map[string]string) interface{} {
+		return map[string]interface{}{"translations": poeditor.CountResult{Parsed: 1, Updated: 1}}
+	}
+	export := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("msgid \"save\"\nmsgstr \"Speichern\"\n"))
+	}))
+	t.Cleanup(export.Close)
+	ok := func(map[string]string) interface{} { return nil }
+	return newFakeAPI(t, map[string]func(map[string]string) interface{}{
+		"/projects/list": func(form map[string]string) interface{} {
+			return map[string]interface{}{"projects": []interface{}{project}}
+		},
+		"/projects/view": func(form map[string]string) interface{} {
+			if form["id"] != "1" {
+				return apiError("Project not found")
+			}
+			return map[string]interface{}{"project": project}
+		},
+		"/languages/list": func(form map[string]string) interface{} {
+			return map[string]interface{}{"languages": []map[string]interface{}{
+				{"code": "en", "name": "English", "translations": 5, "percentage": 100, "updated": ""},
+				{"code": "de", "name": "German", "translations": 3, "percentage": 60, "updated": ""},
+			}}
+		},
+		"/terms/list": func(form map[string]string) interface{} {
+			ts, ok := translations[form["language"]]
+			if !ok {
+				ts = translations["en"]
+			}
+			return map[string]interface{}{"terms": ts}
+		},
+		"/languages/update": count,
+		"/languages/add":    ok,
+		"/terms/add": func(form map[string]string) interface{} {
+			return map[string]interface{}{"terms": poeditor.CountResult{Parsed: 1, Added: 1}}
+		},
+		"/contributors/list": func(form map[string]string) interface{} {
+			return map[string]interface{}{"contributors": []interface{}{map[string]interface{}{
+				"name": "Ana", "email": "ana@example.com",
+				"permissions": []interface{}{map[string]interface{}{"project": map[string]string{"id": "1", "name": "Web"}, "type": "administrator"}},
+			}}}
+		},
+		"/contributors/add": ok,
+		"/projects/upload": func(form map[string]string) interface{} {
+			return poeditor.UploadResult{Terms: poeditor.CountResult{Parsed: 1, Added: 1}}
+		},
+		"/projects/export": func(form map[string]string) interface{} {
+			if form["language"] != "de" {
+				return apiError("Language not found")
+			}
+			return map[string]string{"url": export.URL}
+		},
+	})
+}
+
+// runApp runs the app with the arguments and returns its exit code and
+// output. The environment points the app to the API, with a token.
+func runApp(t *testing.T, api *fakeAPI, env map[string]string, stdin string, args ...string) (int, string, string) {
+	t.Helper()
+	// Keep the config file of the user out of the tests
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Setenv("HOME", t.TempDir())
+	getenv := func(key string) string {
+		if v, ok := env[key]; ok {
+			return v
+		}
+		switch key {
+		case "POEDITOR_API_TOKEN":
+			return "token"
+		case "POEDITOR_API_URL":
+			return api.URL
+		}
+		return ""
+	}
+	var stdout, stderr bytes.Buffer
+	a := &app{stdin: strings.NewReader(stdin), stdout: &stdout, stderr: &stderr, getenv: getenv}
+	code := a.run(args)
+	return code, stdout.String(), stderr.String()
+}
+
+// writeFile writes a file to dir and returns its path
+func writeFile(t *testing.T, dir, name, content string) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+// appTest is a run of the app against projectAPI. The output must contain
+// every string of stdout and stderr.
+type appTest struct {
+	name   string
+	args   []string
+	env    map[string]string
+	stdin  string
+	code   int
+	stdout []string
+	stderr []string
+}
+
+func testApp(t *testing.T, tests []appTest) {
+	t.Helper()
+	for _, test := range tests {
+		api := projectAPI(t)
+		code, stdout, stderr := runApp(t, api, test.env, test.stdin, test.args...)
+		if code != test.code {
+			t.Errorf("%s: expected exit code %d, got %d\nstdout: %s\nstderr: %s", test.name, test.code, code, stdout, stderr)
+			continue
+		}
+		for _, want := range test.stdout {
+			if !strings.Contains(stdout, want) {
+				t.Errorf("%s: expected stdout to contain %q\nGot\n%s", test.name, want, stdout)
+			}
+		}
+		for _, want := range test.stderr {
+			if !strings.Contains(stderr, want) {
+				t.Errorf("%s: expected stderr to contain %q\nGot\n%s", test.name, want, stderr)
+			}
+		}
+	}
+}
+
+func TestApp(t *testing.T) {
+	dir := t.TempDir()
+	source := writeFile(t, dir, "en.json", `{"greeting": "Hello"}`)
+	invalid := writeFile(t, dir, "invalid.json", `{"greeting": }`)
+	exported := filepath.Join(dir, "exported.po")
+
+	testApp(t, []appTest{
+		// Usage
+		{name: "no command", code: exitUsage, stderr: []string{"usage: poe <command>"}},
+		{name: "help", args: []string{"help"}, code: exitOK, stderr: []string{"projects list"}},
+		{name: "unknown command", args: []string{"nope"}, code: exitUsage, stderr: []string{`unknown command "nope"`}},
+		{name: "unknown flag", args: []string{"projects", "list", "-nope"}, code: exitUsage,
+			stderr: []string{"usage: poe projects list [-json]"}},
+		{name: "missing argument", args: []string{"projects", "view"}, code: exitUsage,
+			stderr: []string{"expected 1 arguments"}},
+		{name: "invalid project id", args: []string{"projects", "view", "abc"}, code: exitUsage,
+			stderr: []string{`invalid project id "abc"`}},
+		{name: "no token", args: []string{"projects", "list"}, env: map[string]string{"POEDITOR_API_TOKEN": ""},
+			code: exitUsage, stderr: []string{"no API token"}},
+
+		// Output
+		{name: "table", args: []string{"projects", "list"}, code: exitOK,
+			stdout: []string{"ID  NAME  REFERENCE  TERMS  CREATED\n1   Web   en         5"}},
+		{name: "json", args: []string{"projects", "view", "-json", "1"}, code: exitOK,
+			stdout: []string{`"name": "Web"`, `"reference_language": "en"`}},
+		{name: "api error", args: []string{"projects", "view", "2"}, code: exitAPI,
+			stderr: []string{"poe projects view: ", "Project not found"}},
+
+		{name: "terms list", args: []string{"terms", "list", "-project", "1"}, code: exitOK,
+			stdout: []string{"TERM       CONTEXT  TAGS  COMMENT\nwelcome"}},
+		// every plural form is shown
+		{name: "terms translations", args: []string{"terms", "list", "-project", "1", "-language", "ru"}, code: exitOK,
+			stdout: []string{"one: %d файл | few: %d файла | many: %d файлов"}},
+		{name: "terms add", args: []string{"terms", "add", "-project", "1", "-json", "greeting"}, code: exitOK,
+			stdout: []string{`"added": 1`}},
+		{name: "terms add term", args: []string{"terms", "add", "-project", "1"}, code: exitUsage,
+			stderr: []string{"expected either a term or -file"}},
+
+		{name: "languages list", args: []string{"languages", "list", "-project", "1", "-json"}, code: exitOK,
+			stdout: []string{`"code": "de"`, `"percentage": 60`}},
+		{name: "languages add", args: []string{"languages", "add", "-project", "1", "fr"}, code: exitOK},
+		{name: "languages project", args: []string{"languages", "list"}, code: exitUsage,
+			stderr: []string{"-project is required"}},
+
+		{name: "contributors list", args: []string{"contributors", "list", "-project", "1"}, code: exitOK,
+			stdout: []string{"Ana   ana@example.com  Web:administrator"}},
+		{name: "contributors add", args: []string{"contributors", "add", "-project", "1", "-language", "de", "Ana", "ana@example.com"},
+			code: exitOK},
+		{name: "contributors language", args: []string{"contributors", "list", "-language", "de"}, code: exitUsage,
+			stderr: []string{"-language requires -project"}},
+
+		{name: "upload", args: []string{"upload", "-project", "1", "-json", source}, code: exitOK,
+			stdout: []string{`"added": 1`}},
+		{name: "upload invalid", args: []string{"upload", "-project", "1", invalid}, code: exitError,
+			stderr: []string{"invalid.json:line 1: error:", "nothing was uploaded"}},
+		{name: "upload language", args: []string{"upload", "-project", "1", "-updating", "translations", source},
+			code: exitUsage, stderr: []string{"language"}},
+
+		{name: "export", args: []string{"export", "-project", "1", "-language", "de"}, code: exitOK,
+			stdout: []string{`msgstr "Speichern"`}},
+		{name: "export language", args: []string{"export", "-project", "1"}, code: exitUsage,
+			stderr: []string{"-language is required"}},
+		{name: "export api error", args: []string{"export", "-project", "1", "-language", "xx", "-o", exported},
+			code: exitAPI, stderr: []string{"Language not found"}},
+	})
+	if _, err := os.Stat(exported); !os.IsNotExist(err) {
+		t.Errorf("Expected a failed export to leave the output untouched, got %v", err)
+	}
+}
@@ -0,0 +1,57 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/blacksails/poeditor"
+)
+
+// printer writes command results either as JSON or as a table
+type printer struct {
+	app    *app
+	asJSON *bool
+}
+
+// outputFlags registers the -json flag
+func (a *app) outputFlags(fs *flag.FlagSet) *printer {
+	return &printer{app: a, asJSON: fs.Bool("json", false, "print JSON instead of a table")}
+}
+
+// print writes v as JSON, or the header and rows as a table
+func (p *printer) print(v interface{}, header []string, rows [][]string) error {
+	if *p.asJSON {
+		enc := json.NewEncoder(p.app.stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(v)
+	}
+	tw := tabwriter.NewWriter(p.app.stdout, 0, 4, 2, ' ', 0)
+	if header != nil {
+		fmt.Fprintln(tw, strings.Join(header, "\t"))
+	}
+	for _, row := range rows {
+		for i, cell := range row {
+			// Keep multi line values on a single table row
+			row[i] = strings.NewReplacer("\n", `\n`, "\t", " ").Replace(cell)
+		}
+		fmt.Fprintln(tw, strings.Join(row, "\t"))
+	}
+	return tw.Flush()
+}
+
+// printCount prints the counts returned from the term and upload endpoints
+func (p *printer) printCount(v interface{}, counts map[string]poeditor.CountResult) error {
+	var rows [][]string
+	for _, kind := range []string{"terms", "translations"} {
+		c, ok := counts[kind]
+		if !ok {
+			continue
+		}
+		rows = append(rows, []string{kind, fmt.Sprint(c.Parsed), fmt.Sprint(c.Added),
+			fmt.Sprint(c.Updated), fmt.Sprint(c.Deleted)})
+	}
+	return p.print(v, []string{"", "PARSED", "ADDED", "UPDATED", "DELETED"}, rows)
+}
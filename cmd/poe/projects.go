@@ -0,0 +1,157 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/blacksails/poeditor"
+)
+
+func runProjectsList(a *app, args []string) error {
+	fs := a.flags("projects list")
+	o := a.apiFlags(fs, false)
+	out := a.outputFlags(fs)
+	if err := parse(fs, args, 0, 0); err != nil {
+		return err
+	}
+	poe, err := o.client()
+	if err != nil {
+		return err
+	}
+	ps, err := poe.ListProjects()
+	if err != nil {
+		return err
+	}
+	return printProjects(out, ps, false)
+}
+
+func runProjectsView(a *app, args []string) error {
+	fs := a.flags("projects view")
+	o := a.apiFlags(fs, false)
+	out := a.outputFlags(fs)
+	if err := parse(fs, args, 1, 1); err != nil {
+		return err
+	}
+	id, err := projectID(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	poe, err := o.client()
+	if err != nil {
+		return err
+	}
+	p, err := poe.ViewProject(id)
+	if err != nil {
+		return err
+	}
+	return printProjects(out, []*poeditor.Project{p}, true)
+}
+
+func runProjectsAdd(a *app, args []string) error {
+	fs := a.flags("projects add")
+	o := a.apiFlags(fs, false)
+	out := a.outputFlags(fs)
+	description := fs.String("description", "", "project description")
+	if err := parse(fs, args, 1, 1); err != nil {
+		return err
+	}
+	poe, err := o.client()
+	if err != nil {
+		return err
+	}
+	p, err := poe.AddProject(fs.Arg(0), *description)
+	if err != nil {
+		return err
+	}
+	return printProjects(out, []*poeditor.Project{p}, true)
+}
+
+func runProjectsUpdate(a *app, args []string) error {
+	fs := a.flags("projects update")
+	o := a.apiFlags(fs, false)
+	out := a.outputFlags(fs)
+	props := make(map[string]string)
+	setProp := func(field string) func(string) error {
+		return func(v string) error {
+			props[field] = v
+			return nil
+		}
+	}
+	fs.Func("name", "new project name", setProp("name"))
+	fs.Func("description", "new project description", setProp("description"))
+	fs.Func("reference-language", "new reference language code", setProp("reference_language"))
+	if err := parse(fs, args, 1, 1); err != nil {
+		return err
+	}
+	if len(props) == 0 {
+		return fmt.Errorf("%w: nothing to update", errUsage)
+	}
+	id, err := projectID(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	poe, err := o.client()
+	if err != nil {
+		return err
+	}
+	p, err := poe.Project(id).Update(props)
+	if err != nil {
+		return err
+	}
+	return printProjects(out, []*poeditor.Project{p}, true)
+}
+
+func runProjectsDelete(a *app, args []string) error {
+	fs := a.flags("projects delete")
+	o := a.apiFlags(fs, false)
+	if err := parse(fs, args, 1, 1); err != nil {
+		return err
+	}
+	id, err := projectID(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	poe, err := o.client()
+	if err != nil {
+		return err
+	}
+	return poe.Project(id).Delete()
+}
+
+// printProjects prints projects, or a single project as a JSON object when
+// single is set
+func printProjects(out *printer, ps []*poeditor.Project, single bool) error {
+	rows := make([][]string, len(ps))
+	views := make([]projectView, len(ps))
+	for i, p := range ps {
+		rows[i] = []string{strconv.Itoa(p.ID), p.Name, p.ReferenceLanguage,
+			strconv.Itoa(p.Terms), p.Created.Format("2006-01-02")}
+		views[i] = projectView{
+			ID:                p.ID,
+			Name:              p.Name,
+			Description:       p.Description,
+			Public:            p.Public == 1,
+			Open:              p.Open == 1,
+			ReferenceLanguage: p.ReferenceLanguage,
+			Terms:             p.Terms,
+			Created:           p.Created,
+		}
+	}
+	var v interface{} = views
+	if single && len(views) == 1 {
+		v = views[0]
+	}
+	return out.print(v, []string{"ID", "NAME", "REFERENCE", "TERMS", "CREATED"}, rows)
+}
+
+type projectView struct {
+	ID                int       `json:"id"`
+	Name              string    `json:"name"`
+	Description       string    `json:"description"`
+	Public            bool      `json:"public"`
+	Open              bool      `json:"open"`
+	ReferenceLanguage string    `json:"reference_language"`
+	Terms             int       `json:"terms"`
+	Created           time.Time `json:"created"`
+}
@@ -0,0 +1,70 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/blacksails/poeditor"
+	"github.com/blacksails/poeditor/pseudo"
+)
+
+func runPseudo(a *app, args []string) error {
+	fs := a.flags("pseudo")
+	o := a.apiFlags(fs, true)
+	source := fs.String("source", "", "source language code, defaults to the reference language of the project")
+	expansion := fs.Float64("expansion", pseudo.DefaultExpansion, "length added to translations as a fraction of their length, 0 to disable")
+	noAccents := fs.Bool("no-accents", false, "keep letters unchanged")
+	noBrackets := fs.Bool("no-brackets", false, "leave out the brackets around translations")
+	push := fs.String("push", "", "language code to write the pseudo translations to, e.g. en-XA, instead of a file")
+	format := fs.String("format", "", "file format, defaults to the format matching the -o file extension or po for stdout")
+	output := fs.String("o", "-", "output file, - for stdout")
+	if err := parse(fs, args, 0, 0); err != nil {
+		return err
+	}
+	loc := pseudo.Localizer{Expansion: *expansion, NoAccents: *noAccents, NoBrackets: *noBrackets}
+	if *expansion == 0 {
+		loc.Expansion = -1
+	}
+	var err error
+	if *push == "" {
+		if *output == "-" && *format == "" {
+			*format = poeditor.FileFormatPO
+		}
+		if *format, err = formatFor(*format, *output); err != nil {
+			return err
+		}
+	}
+	p, err := o.projectClient()
+	if err != nil {
+		return err
+	}
+	if *source == "" {
+		view, err := p.POEditor.ViewProject(p.ID)
+		if err != nil {
+			return err
+		}
+		if *source = view.ReferenceLanguage; *source == "" {
+			return fmt.Errorf("%w: the project has no reference language, use -source", errUsage)
+		}
+	}
+	if *push != "" {
+		c, err := loc.Push(p, *source, *push)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(a.stderr, "added %d and updated %d translations of %s\n", c.Added, c.Updated, *push)
+		return nil
+	}
+	// Encode into memory first, so a failed export leaves the output untouched
+	var buf bytes.Buffer
+	if err := loc.Export(p, *source, &buf, *format); err != nil {
+		return err
+	}
+	if *output == "-" {
+		_, err = io.Copy(a.stdout, &buf)
+		return err
+	}
+	return os.WriteFile(*output, buf.Bytes(), 0644)
+}
@@ -0,0 +1,13 @@
+package main
+
+import "testing"
+
+func TestPseudo(t *testing.T) {
+	testApp(t, []appTest{
+		{name: "pseudo", args: []string{"pseudo", "-project", "1", "-format", "key_value_json", "-expansion", "0"},
+			code: exitOK, stdout: []string{`"welcome": "[Ŵéļçöɱé]"`}},
+		{name: "pseudo push", args: []string{"pseudo", "-project", "1", "-push", "de"},
+			code: exitOK, stderr: []string{"added 0 and updated 1 translations of de"}},
+		{name: "pseudo project", args: []string{"pseudo"}, code: exitUsage, stderr: []string{"-project is required"}},
+	})
+}
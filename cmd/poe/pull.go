@@ -0,0 +1,154 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/blacksails/poeditor"
+)
+
+// uploadInterval is the default time to wait between uploads, POEditor
+// rejects uploads made more often than every 20 seconds
+const uploadInterval = 20 * time.Second
+
+func runPull(a *app, args []string) error {
+	fs := a.flags("pull")
+	r := a.repoFlags(fs)
+	dryRun := fs.Bool("dry-run", false, "print the files which would be written")
+	if err := parse(fs, args, 0, 0); err != nil {
+		return err
+	}
+	c, p, err := r.load()
+	if err != nil {
+		return err
+	}
+	for _, f := range c.Files {
+		for _, code := range c.Languages {
+			path := f.path(c.dir, code)
+			if *dryRun {
+				fmt.Fprintf(a.stdout, "%s %s\n", code, path)
+				continue
+			}
+			var buf bytes.Buffer
+			l := poeditor.Language{Project: p, Code: code}
+			if err := l.Export(f.Format, f.Filters, f.Tags, &buf); err != nil {
+				return fmt.Errorf("exporting %s to %s: %w", code, path, err)
+			}
+			if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+				return err
+			}
+			if err := os.WriteFile(path, buf.Bytes(), 0644); err != nil {
+				return err
+			}
+			fmt.Fprintf(a.stdout, "%s %s\n", code, path)
+		}
+	}
+	return nil
+}
+
+// pushResult is printed for every uploaded file
+type pushResult struct {
+	Language string                `json:"language"`
+	Path     string                `json:"path"`
+	Updating string                `json:"updating"`
+	Result   poeditor.UploadResult `json:"result"`
+}
+
+func runPush(a *app, args []string) error {
+	fs := a.flags("push")
+	r := a.repoFlags(fs)
+	out := a.outputFlags(fs)
+	dryRun := fs.Bool("dry-run", false, "print the files which would be uploaded")
+	termsOnly := fs.Bool("terms-only", false, "only upload the source language files")
+	interval := fs.Duration("interval", uploadInterval, "time to wait between uploads")
+	if err := parse(fs, args, 0, 0); err != nil {
+		return err
+	}
+	c, p, err := r.load()
+	if err != nil {
+		return err
+	}
+	source, err := r.sourceLanguage(c, p)
+	if err != nil {
+		return err
+	}
+	var (
+		results []pushResult
+		rows    [][]string
+	)
+	for _, f := range c.Files {
+		for _, code := range pushLanguages(source, c.Languages, *r.languages != "", *termsOnly) {
+			path := f.path(c.dir, code)
+			// The source file carries the terms, the other files only carry
+			// translations and never delete terms
+			options := poeditor.UploadOptions{
+				Updating:       poeditor.UploadTranslations,
+				Language:       poeditor.Language{Project: p, Code: code},
+				Overwrite:      f.Upload.Overwrite,
+				Tags:           f.Upload.Tags,
+				ReadFromSource: f.Upload.ReadFromSource,
+				FuzzyTrigger:   f.Upload.FuzzyTrigger,
+			}
+			if code == source {
+				options.Updating = poeditor.UploadTermsTranslations
+				options.SyncTerms = f.Upload.SyncTerms
+			}
+			file, err := os.Open(path)
+			if os.IsNotExist(err) && code != source {
+				fmt.Fprintf(a.stderr, "warning: skipping missing %s\n", path)
+				continue
+			}
+			if err != nil {
+				return err
+			}
+			res := pushResult{Language: code, Path: path, Updating: options.Updating}
+			if !*dryRun {
+				if len(results) > 0 {
+					time.Sleep(*interval)
+				}
+				res.Result, err = p.UploadFile(path, file, options)
+			}
+			file.Close()
+			if err != nil {
+				return fmt.Errorf("uploading %s: %w", path, err)
+			}
+			results = append(results, res)
+			rows = append(rows, []string{code, path, options.Updating,
+				fmt.Sprint(res.Result.Terms.Added), fmt.Sprint(res.Result.Terms.Deleted),
+				fmt.Sprint(res.Result.Translations.Added), fmt.Sprint(res.Result.Translations.Updated)})
+		}
+	}
+	return out.print(results, []string{"LANGUAGE", "PATH", "UPDATING", "TERMS ADDED",
+		"TERMS DELETED", "TRANSLATIONS ADDED", "TRANSLATIONS UPDATED"}, rows)
+}
+
+// pushLanguages returns the languages to push, starting with the source
+// language. The source language is left out when the languages are given
+// explicitly without it.
+func pushLanguages(source string, codes []string, explicit, termsOnly bool) []string {
+	var langs []string
+	if !explicit || contains(codes, source) {
+		langs = append(langs, source)
+	}
+	if termsOnly {
+		return langs
+	}
+	for _, code := range codes {
+		if code != source {
+			langs = append(langs, code)
+		}
+	}
+	return langs
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
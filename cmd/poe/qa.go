@@ -0,0 +1,71 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/blacksails/poeditor"
+	"github.com/blacksails/poeditor/qa"
+)
+
+func runQA(a *app, args []string) error {
+	fs := a.flags("qa")
+	o := a.apiFlags(fs, true)
+	out := a.outputFlags(fs)
+	language := fs.String("language", "", "language code to check")
+	source := fs.String("source", "", "source language code, defaults to the reference language of the project")
+	severity := fs.String("severity", qa.Warning, "least severity reported by the exit code and -comment: error, warning or info")
+	comment := fs.Bool("comment", false, "comment on the terms with findings of at least -severity")
+	if err := parse(fs, args, 0, 0); err != nil {
+		return err
+	}
+	if *language == "" {
+		return fmt.Errorf("%w: -language is required", errUsage)
+	}
+	switch *severity {
+	case qa.Error, qa.Warning, qa.Info:
+	default:
+		return fmt.Errorf("%w: unknown severity %q", errUsage, *severity)
+	}
+	p, err := o.projectClient()
+	if err != nil {
+		return err
+	}
+	if *source == "" {
+		view, err := p.POEditor.ViewProject(p.ID)
+		if err != nil {
+			return err
+		}
+		if *source = view.ReferenceLanguage; *source == "" {
+			return fmt.Errorf("%w: the project has no reference language, use -source", errUsage)
+		}
+	}
+	findings, err := qa.RunLanguage(poeditor.Language{Project: p, Code: *language}, *source)
+	if err != nil {
+		return err
+	}
+	if *comment {
+		c, err := qa.AddComments(p, *language, findings, *severity)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(a.stderr, "commented on %d terms\n", c.WithAddedComment)
+	}
+	rows := make([][]string, len(findings))
+	failed := 0
+	for i, f := range findings {
+		rows[i] = []string{f.Severity, termName(f.TermBase), f.Form, f.Check, f.Message}
+		if qa.AtLeast(f.Severity, *severity) {
+			failed++
+		}
+	}
+	if findings == nil {
+		findings = []qa.Finding{}
+	}
+	if err := out.print(findings, []string{"SEVERITY", "TERM", "FORM", "CHECK", "MESSAGE"}, rows); err != nil {
+		return err
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d findings of at least %s severity", failed, *severity)
+	}
+	return nil
+}
@@ -0,0 +1,223 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/blacksails/poeditor"
+	"github.com/blacksails/poeditor/internal/yaml"
+)
+
+// repoConfigNames are the names of the repo config file, looked up in the
+// working directory and its parents
+var repoConfigNames = []string{".poeditor.yml", ".poeditor.yaml", ".poeditor.json"}
+
+// langPlaceholder is replaced by the language code in file paths
+const langPlaceholder = "{lang}"
+
+// repoConfig describes how the files of a repository map to a POEditor
+// project:
+//
+//	project_id: 1234
+//	source_language: en
+//	languages: [en, de, fr]
+//	files:
+//	  - path: locales/{lang}.json
+//	    format: key_value_json
+//	    filters: [translated]
+//	    upload:
+//	      overwrite: true
+//	      tags: [web]
+//	checks:
+//	  - name: complete
+//	    min_percentage: 95
+//	  - name: release
+//	    tags: [release-3.2]
+//	    forbid: [fuzzy, untranslated]
+type repoConfig struct {
+	ProjectID int `json:"project_id"`
+	// SourceLanguage is the language pushed as terms, it defaults to the
+	// reference language of the project
+	SourceLanguage string       `json:"source_language"`
+	Languages      []string     `json:"languages"`
+	Files          []fileConfig `json:"files"`
+	// Checks are the rules evaluated by poe check. Rules without languages
+	// apply to the languages above.
+	Checks []poeditor.Rule `json:"checks"`
+
+	// dir is the directory of the config file, which paths are relative to
+	dir string
+}
+
+// fileConfig maps a set of files to the project
+type fileConfig struct {
+	// Path is the file path, where {lang} is replaced by the language code
+	Path string `json:"path"`
+	// Format is one of the FileFormat constants and defaults to the format
+	// matching the file extension
+	Format string `json:"format"`
+	// Tags and Filters select the terms exported by pull
+	Tags    []string `json:"tags"`
+	Filters []string `json:"filters"`
+	// Upload holds the options used by push
+	Upload uploadConfig `json:"upload"`
+}
+
+// uploadConfig holds the poeditor.UploadOptions fields which are not set by
+// push itself
+type uploadConfig struct {
+	Overwrite      bool     `json:"overwrite"`
+	SyncTerms      bool     `json:"sync_terms"`
+	Tags           []string `json:"tags"`
+	ReadFromSource bool     `json:"read_from_source"`
+	FuzzyTrigger   bool     `json:"fuzzy_trigger"`
+}
+
+// path returns the path of the file for the language
+func (f fileConfig) path(dir, code string) string {
+	return filepath.Join(dir, filepath.FromSlash(strings.Replace(f.Path, langPlaceholder, code, -1)))
+}
+
+// loadRepoConfig reads the config file, which is YAML or JSON depending on
+// its extension
+func loadRepoConfig(name string) (*repoConfig, error) {
+	b, err := os.ReadFile(name)
+	if err != nil {
+		return nil, err
+	}
+	var c repoConfig
+	if filepath.Ext(name) == ".json" {
+		err = json.Unmarshal(b, &c)
+	} else {
+		err = yaml.Unmarshal(b, &c)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", name, err)
+	}
+	c.dir = filepath.Dir(name)
+	if err := c.validate(); err != nil {
+		return nil, fmt.Errorf("%s: %w", name, err)
+	}
+	return &c, nil
+}
+
+func (c *repoConfig) validate() error {
+	if len(c.Languages) == 0 {
+		return errors.New("no languages")
+	}
+	if len(c.Files) == 0 {
+		return errors.New("no files")
+	}
+	for i := range c.Files {
+		f := &c.Files[i]
+		if f.Path == "" {
+			return fmt.Errorf("file %d has no path", i+1)
+		}
+		if len(c.Languages) > 1 && !strings.Contains(f.Path, langPlaceholder) {
+			return fmt.Errorf("%s: path must contain %s when there are several languages", f.Path, langPlaceholder)
+		}
+		if f.Format == "" {
+			f.Format = poeditor.FormatFromFilename(f.Path)
+		}
+		if f.Format == "" {
+			return fmt.Errorf("%s: cannot determine format, set format", f.Path)
+		}
+	}
+	for i := range c.Checks {
+		if c.Checks[i].Name == "" {
+			c.Checks[i].Name = fmt.Sprintf("check %d", i+1)
+		}
+	}
+	return nil
+}
+
+// findRepoConfig looks for a config file in dir and its parents
+func findRepoConfig(dir string) (string, error) {
+	for {
+		for _, name := range repoConfigNames {
+			path := filepath.Join(dir, name)
+			if _, err := os.Stat(path); err == nil {
+				return path, nil
+			}
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", fmt.Errorf("%w: no %s found, use -config", errUsage, repoConfigNames[0])
+		}
+		dir = parent
+	}
+}
+
+// repo holds the flags of commands working on the files of a repository
+type repo struct {
+	api       *api
+	config    *string
+	languages *string
+}
+
+// repoFlags registers the -config and -languages flags along with the API
+// flags. The -project flag overrides the project of the config file.
+func (a *app) repoFlags(fs *flag.FlagSet) *repo {
+	return &repo{
+		api:       a.apiFlags(fs, true),
+		config:    fs.String("config", "", "config file, defaults to the nearest "+repoConfigNames[0]),
+		languages: fs.String("languages", "", "comma separated language codes, defaults to the languages of the config"),
+	}
+}
+
+// load reads the config file and returns it along with the project
+func (r *repo) load() (*repoConfig, *poeditor.Project, error) {
+	name := *r.config
+	if name == "" {
+		wd, err := os.Getwd()
+		if err != nil {
+			return nil, nil, err
+		}
+		if name, err = findRepoConfig(wd); err != nil {
+			return nil, nil, err
+		}
+	}
+	c, err := loadRepoConfig(name)
+	if err != nil {
+		return nil, nil, err
+	}
+	if *r.api.project == 0 {
+		*r.api.project = c.ProjectID
+	}
+	if *r.api.project == 0 {
+		return nil, nil, fmt.Errorf("%s: no project_id", name)
+	}
+	if codes := splitList(*r.languages); codes != nil {
+		c.Languages = codes
+	}
+	p, err := r.api.projectClient()
+	if err != nil {
+		return nil, nil, err
+	}
+	return c, p, nil
+}
+
+// sourceLanguage returns the source language of the config, or the reference
+// language of the project
+func (r *repo) sourceLanguage(c *repoConfig, p *poeditor.Project) (string, error) {
+	if c.SourceLanguage != "" {
+		return c.SourceLanguage, nil
+	}
+	poe, err := r.api.client()
+	if err != nil {
+		return "", err
+	}
+	view, err := poe.ViewProject(p.ID)
+	if err != nil {
+		return "", err
+	}
+	if view.ReferenceLanguage == "" {
+		return "", fmt.Errorf("project %d has no reference language, set source_language", p.ID)
+	}
+	return view.ReferenceLanguage, nil
+}
@@ -0,0 +1,192 @@
+package main
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/blacksails/poeditor"
+)
+
+type junitSuites struct {
+	XMLName  xml.Name     `xml:"testsuites"`
+	Tests    int          `xml:"tests,attr"`
+	Failures int          `xml:"failures,attr"`
+	Suites   []junitSuite `xml:"testsuite"`
+}
+
+type junitSuite struct {
+	Name     string      `xml:"name,attr"`
+	Tests    int         `xml:"tests,attr"`
+	Failures int         `xml:"failures,attr"`
+	Cases    []junitCase `xml:"testcase"`
+}
+
+type junitCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Failure   *junitFailure `xml:"failure"`
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Text    string `xml:",chardata"`
+}
+
+// writeCheckJUnit writes a JUnit XML report with a test suite per rule and a
+// test case per language
+func writeCheckJUnit(w io.Writer, report poeditor.CheckReport) error {
+	suites := junitSuites{}
+	index := make(map[string]int)
+	for _, res := range report.Results {
+		i, ok := index[res.Rule.Name]
+		if !ok {
+			i = len(suites.Suites)
+			index[res.Rule.Name] = i
+			suites.Suites = append(suites.Suites, junitSuite{Name: res.Rule.Name})
+		}
+		s := &suites.Suites[i]
+		c := junitCase{Name: res.Language, ClassName: "poeditor." + res.Rule.Name}
+		if !res.Passed {
+			c.Failure = &junitFailure{Message: res.Message(), Text: violationsText(res)}
+			s.Failures++
+			suites.Failures++
+		}
+		s.Cases = append(s.Cases, c)
+		s.Tests++
+		suites.Tests++
+	}
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(suites); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+func violationsText(res poeditor.CheckResult) string {
+	var b strings.Builder
+	for _, v := range res.Violations {
+		fmt.Fprintf(&b, "%s is %s\n", termName(v.TermBase), v.Filter)
+	}
+	return b.String()
+}
+
+type sarifLog struct {
+	Version string     `json:"version"`
+	Schema  string     `json:"$schema"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name  string      `json:"name"`
+	Rules []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID               string       `json:"id"`
+	ShortDescription sarifMessage `json:"shortDescription"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifResult struct {
+	RuleID    string          `json:"ruleId"`
+	Level     string          `json:"level"`
+	Message   sarifMessage    `json:"message"`
+	Locations []sarifLocation `json:"locations,omitempty"`
+}
+
+type sarifLocation struct {
+	LogicalLocations []sarifLogicalLocation `json:"logicalLocations"`
+}
+
+type sarifLogicalLocation struct {
+	Name               string `json:"name"`
+	FullyQualifiedName string `json:"fullyQualifiedName"`
+	Kind               string `json:"kind"`
+}
+
+// writeCheckSARIF writes a SARIF 2.1.0 log with a result per failed language
+// and per violating term
+func writeCheckSARIF(w io.Writer, report poeditor.CheckReport) error {
+	run := sarifRun{
+		Tool:    sarifTool{Driver: sarifDriver{Name: "poe", Rules: []sarifRule{}}},
+		Results: []sarifResult{},
+	}
+	seen := make(map[string]bool)
+	for _, res := range report.Results {
+		if !seen[res.Rule.Name] {
+			seen[res.Rule.Name] = true
+			run.Tool.Driver.Rules = append(run.Tool.Driver.Rules, sarifRule{
+				ID:               res.Rule.Name,
+				ShortDescription: sarifMessage{Text: ruleDescription(res.Rule)},
+			})
+		}
+		if res.Passed {
+			continue
+		}
+		if res.Percentage < res.Rule.MinPercentage {
+			run.Results = append(run.Results, sarifResult{
+				RuleID:  res.Rule.Name,
+				Level:   "error",
+				Message: sarifMessage{Text: fmt.Sprintf("%s: %.1f%% translated, %.1f%% required", res.Language, res.Percentage, res.Rule.MinPercentage)},
+				Locations: []sarifLocation{{LogicalLocations: []sarifLogicalLocation{{
+					Name: res.Language, FullyQualifiedName: res.Language, Kind: "module",
+				}}}},
+			})
+		}
+		for _, v := range res.Violations {
+			run.Results = append(run.Results, sarifResult{
+				RuleID:  res.Rule.Name,
+				Level:   "error",
+				Message: sarifMessage{Text: fmt.Sprintf("%s: %s is %s", res.Language, termName(v.TermBase), v.Filter)},
+				Locations: []sarifLocation{{LogicalLocations: []sarifLogicalLocation{{
+					Name:               v.Term,
+					FullyQualifiedName: res.Language + "/" + termName(v.TermBase),
+					Kind:               "member",
+				}}}},
+			})
+		}
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(sarifLog{
+		Version: "2.1.0",
+		Schema:  "https://json.schemastore.org/sarif-2.1.0.json",
+		Runs:    []sarifRun{run},
+	})
+}
+
+// ruleDescription describes a rule in words
+func ruleDescription(r poeditor.Rule) string {
+	var parts []string
+	if r.MinPercentage > 0 {
+		parts = append(parts, fmt.Sprintf("at least %.1f%% translated", r.MinPercentage))
+	}
+	if len(r.Forbid) > 0 {
+		parts = append(parts, "no "+strings.Join(r.Forbid, " or ")+" terms")
+	}
+	desc := strings.Join(parts, " and ")
+	if len(r.Tags) > 0 {
+		desc += " in tags " + strings.Join(r.Tags, ", ")
+	}
+	return desc
+}
@@ -0,0 +1,71 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/blacksails/poeditor"
+)
+
+var update = flag.Bool("update", false, "update the golden files in testdata")
+
+var testCheckReport = poeditor.CheckReport{Results: []poeditor.CheckResult{
+	{
+		Rule:     poeditor.Rule{Name: "complete", MinPercentage: 95},
+		Language: "de", Terms: 4, Percentage: 100, Passed: true,
+	},
+	{
+		Rule:     poeditor.Rule{Name: "complete", MinPercentage: 95},
+		Language: "fr", Terms: 4, Percentage: 75,
+	},
+	{
+		Rule:     poeditor.Rule{Name: "reviewed", Tags: []string{"ui"}, Forbid: []string{poeditor.FilterFuzzy, poeditor.FilterUntranslated}},
+		Language: "fr", Terms: 2, Percentage: 50,
+		Violations: []poeditor.CheckViolation{
+			{TermBase: poeditor.TermBase{Term: "save", Context: "toolbar"}, Filter: poeditor.FilterFuzzy},
+			{TermBase: poeditor.TermBase{Term: "<b>&quit"}, Filter: poeditor.FilterUntranslated},
+		},
+	},
+}}
+
+func TestCheckReports(t *testing.T) {
+	tests := []struct {
+		golden string
+		write  func(io.Writer, poeditor.CheckReport) error
+	}{
+		{"check.junit.xml", writeCheckJUnit},
+		{"check.sarif.json", writeCheckSARIF},
+		{"check.txt", writeCheckText},
+	}
+	for _, test := range tests {
+		var buf bytes.Buffer
+		if err := test.write(&buf, testCheckReport); err != nil {
+			t.Fatalf("%s: unexpected error: %s", test.golden, err)
+		}
+		golden(t, test.golden, buf.Bytes())
+	}
+}
+
+// golden compares output with a file in testdata, or writes the file with
+// -update
+func golden(t *testing.T, name string, got []byte) {
+	t.Helper()
+	path := filepath.Join("testdata", name)
+	if *update {
+		if err := os.WriteFile(path, got, 0644); err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		return
+	}
+	expected, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if !bytes.Equal(got, expected) {
+		t.Errorf("%s differs\nExpected:\n%s\nGot:\n%s", name, expected, got)
+	}
+}
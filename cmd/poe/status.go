@@ -0,0 +1,189 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/blacksails/poeditor"
+)
+
+// status is the report printed by poe status
+type status struct {
+	Languages []languageStatus `json:"languages"`
+	Files     []fileStatus     `json:"files"`
+}
+
+type languageStatus struct {
+	languageView
+	Untranslated int `json:"untranslated"`
+	Fuzzy        int `json:"fuzzy"`
+	Unproofread  int `json:"unproofread"`
+}
+
+// fileStatus holds the drift between a source language file and the project
+type fileStatus struct {
+	Path string `json:"path"`
+	// LocalOnly holds the terms of the file missing in POEditor
+	LocalOnly []poeditor.TermBase `json:"local_only"`
+	// RemoteOnly holds the terms in POEditor missing in the file. When the
+	// file has tags, only terms with one of them are considered.
+	RemoteOnly []poeditor.TermBase `json:"remote_only"`
+}
+
+func runStatus(a *app, args []string) error {
+	fs := a.flags("status")
+	r := a.repoFlags(fs)
+	out := a.outputFlags(fs)
+	if err := parse(fs, args, 0, 0); err != nil {
+		return err
+	}
+	c, p, err := r.load()
+	if err != nil {
+		return err
+	}
+	source, err := r.sourceLanguage(c, p)
+	if err != nil {
+		return err
+	}
+	ls, err := p.ListLanguages()
+	if err != nil {
+		return err
+	}
+	var st status
+	for _, code := range c.Languages {
+		l, ok := findLanguage(ls, code)
+		if !ok {
+			fmt.Fprintf(a.stderr, "warning: language %s is not in the project\n", code)
+			continue
+		}
+		s, err := languageStatusOf(l)
+		if err != nil {
+			return err
+		}
+		st.Languages = append(st.Languages, s)
+	}
+	terms, err := p.ListTerms()
+	if err != nil {
+		return err
+	}
+	for _, f := range c.Files {
+		path := f.path(c.dir, source)
+		local, err := decodeFile(path, f.Format)
+		if err == poeditor.ErrFormatUnsupported {
+			fmt.Fprintf(a.stderr, "warning: cannot compare %s, reading %s is not supported\n", path, f.Format)
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		st.Files = append(st.Files, fileDrift(path, local, terms, f.Tags))
+	}
+	if *out.asJSON {
+		return out.print(st, nil, nil)
+	}
+	rows := make([][]string, len(st.Languages))
+	for i, l := range st.Languages {
+		var updated string
+		if l.Updated != nil {
+			updated = formatTime(*l.Updated)
+		}
+		rows[i] = []string{l.Code, fmt.Sprintf("%.1f%%", l.Percentage), strconv.Itoa(l.Untranslated),
+			strconv.Itoa(l.Fuzzy), strconv.Itoa(l.Unproofread), updated}
+	}
+	if err := out.print(st, []string{"LANGUAGE", "COMPLETE", "UNTRANSLATED", "FUZZY", "UNPROOFREAD", "UPDATED"}, rows); err != nil {
+		return err
+	}
+	for _, f := range st.Files {
+		fmt.Fprintf(a.stdout, "\n%s: %d terms missing in POEditor, %d terms missing locally\n",
+			f.Path, len(f.LocalOnly), len(f.RemoteOnly))
+		for _, t := range f.LocalOnly {
+			fmt.Fprintf(a.stdout, "  + %s\n", termName(t))
+		}
+		for _, t := range f.RemoteOnly {
+			fmt.Fprintf(a.stdout, "  - %s\n", termName(t))
+		}
+	}
+	return nil
+}
+
+func findLanguage(ls []poeditor.Language, code string) (poeditor.Language, bool) {
+	for _, l := range ls {
+		if strings.EqualFold(l.Code, code) {
+			return l, true
+		}
+	}
+	return poeditor.Language{}, false
+}
+
+// languageStatusOf counts the untranslated, fuzzy and unproofread terms of
+// the language
+func languageStatusOf(l poeditor.Language) (languageStatus, error) {
+	s := languageStatus{languageView: newLanguageView(l)}
+	terms, err := l.ListTerms()
+	if err != nil {
+		return s, err
+	}
+	for _, t := range terms {
+		switch {
+		case poeditor.EmptyContent(t.Translation.Content):
+			s.Untranslated++
+		case t.Translation.Fuzzy == 1:
+			s.Fuzzy++
+		case t.Translation.Proofread == 0:
+			s.Unproofread++
+		}
+	}
+	return s, nil
+}
+
+// decodeFile reads the terms of a local file
+func decodeFile(path, format string) ([]poeditor.TermTranslated, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return poeditor.Decode(f, format)
+}
+
+// fileDrift compares the terms of a local file with the terms of the project
+func fileDrift(path string, local []poeditor.TermTranslated, remote []poeditor.Term, tags []string) fileStatus {
+	localTerms := make([]poeditor.Term, len(local))
+	for i, t := range local {
+		localTerms[i] = t.Term
+	}
+	var tagged []poeditor.Term
+	for _, t := range remote {
+		if len(tags) == 0 || hasAnyTag(t.Tags, tags) {
+			tagged = append(tagged, t)
+		}
+	}
+	s := fileStatus{Path: path, LocalOnly: []poeditor.TermBase{}, RemoteOnly: []poeditor.TermBase{}}
+	// A local term is present in POEditor whatever its tags
+	for _, t := range poeditor.DiffTerms(remote, localTerms).Added {
+		s.LocalOnly = append(s.LocalOnly, t.TermBase)
+	}
+	for _, t := range poeditor.DiffTerms(tagged, localTerms).Removed {
+		s.RemoteOnly = append(s.RemoteOnly, t.TermBase)
+	}
+	return s
+}
+
+func hasAnyTag(tags, want []string) bool {
+	for _, t := range tags {
+		if contains(want, t) {
+			return true
+		}
+	}
+	return false
+}
+
+// termName formats a term along with its context
+func termName(t poeditor.TermBase) string {
+	if t.Context == "" {
+		return t.Term
+	}
+	return fmt.Sprintf("%s (%s)", t.Term, t.Context)
+}
@@ -0,0 +1,34 @@
+package main
+
+import "testing"
+
+func TestStatus(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "locales/en.json", `{"welcome": "Welcome", "local": "Local"}`)
+	config := writeFile(t, dir, ".poeditor.yml", `project_id: 1
+source_language: en
+languages: [de]
+files:
+  - path: locales/{lang}.json
+    format: key_value_json
+`)
+	// Without a source language the reference language of the project is
+	// used
+	reference := writeFile(t, dir, "reference.yml", `project_id: 1
+languages: [de]
+files:
+  - path: locales/{lang}.json
+    format: key_value_json
+`)
+	testApp(t, []appTest{
+		// empty plurals count as untranslated
+		{name: "status", args: []string{"status", "-config", config}, code: exitOK,
+			stdout: []string{"de        60.0%     2             1      1", "+ local", "- files", "- workspace"}},
+		{name: "status json", args: []string{"status", "-json", "-config", config}, code: exitOK,
+			stdout: []string{`"untranslated": 2`, `"fuzzy": 1`, `"unproofread": 1`}},
+		{name: "status reference", args: []string{"status", "-config", reference}, code: exitOK,
+			stdout: []string{"+ local"}},
+		{name: "status project", args: []string{"status", "-config", reference, "-project", "2"}, code: exitAPI,
+			stderr: []string{"Project not found"}},
+	})
+}
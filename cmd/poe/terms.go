@@ -0,0 +1,232 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/blacksails/poeditor"
+)
+
+func runTermsList(a *app, args []string) error {
+	fs := a.flags("terms list")
+	o := a.apiFlags(fs, true)
+	out := a.outputFlags(fs)
+	language := fs.String("language", "", "include translations for the language code")
+	if err := parse(fs, args, 0, 0); err != nil {
+		return err
+	}
+	p, err := o.projectClient()
+	if err != nil {
+		return err
+	}
+	if *language == "" {
+		terms, err := p.ListTerms()
+		if err != nil {
+			return err
+		}
+		rows := make([][]string, len(terms))
+		for i, t := range terms {
+			rows[i] = []string{t.Term, t.Context, strings.Join(t.Tags, ","), t.Comment}
+		}
+		return out.print(terms, []string{"TERM", "CONTEXT", "TAGS", "COMMENT"}, rows)
+	}
+	l := poeditor.Language{Project: p, Code: *language}
+	terms, err := l.ListTerms()
+	if err != nil {
+		return err
+	}
+	rows := make([][]string, len(terms))
+	for i, t := range terms {
+		rows[i] = []string{t.Term.Term, t.Context, translationText(t.Translation), translationState(t.Translation)}
+	}
+	return out.print(terms, []string{"TERM", "CONTEXT", "TRANSLATION", "STATE"}, rows)
+}
+
+// termFlags registers the flags describing a single term
+type termFlags struct {
+	context, plural, reference, comment, tags *string
+	file                                      *string
+}
+
+func newTermFlags(fs *flag.FlagSet) *termFlags {
+	return &termFlags{
+		context:   fs.String("context", "", "term context"),
+		plural:    fs.String("plural", "", "plural form of the term"),
+		reference: fs.String("reference", "", "term reference"),
+		comment:   fs.String("comment", "", "term comment"),
+		tags:      fs.String("tags", "", "comma separated term tags"),
+		file:      fs.String("file", "", "read a JSON array of terms from the file instead, - for stdin"),
+	}
+}
+
+func (f *termFlags) term(term string) poeditor.Term {
+	t := poeditor.Term{
+		Plural:    *f.plural,
+		Reference: *f.reference,
+		Comment:   *f.comment,
+		Tags:      splitList(*f.tags),
+	}
+	t.Term = term
+	t.Context = *f.context
+	return t
+}
+
+// parse parses the flags, and checks a term argument is given unless terms
+// are read from -file
+func (f *termFlags) parse(fs *flag.FlagSet, args []string) error {
+	if err := parse(fs, args, 0, 1); err != nil {
+		return err
+	}
+	if (*f.file == "") == (fs.NArg() == 0) {
+		return fmt.Errorf("%w: expected either a term or -file", errUsage)
+	}
+	return nil
+}
+
+// readJSONFile decodes a JSON file, or stdin if name is -
+func (a *app) readJSONFile(name string, v interface{}) error {
+	if name == "-" {
+		return json.NewDecoder(a.stdin).Decode(v)
+	}
+	f, err := os.Open(name)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	return json.NewDecoder(f).Decode(v)
+}
+
+func runTermsAdd(a *app, args []string) error {
+	fs := a.flags("terms add")
+	o := a.apiFlags(fs, true)
+	out := a.outputFlags(fs)
+	tf := newTermFlags(fs)
+	if err := tf.parse(fs, args); err != nil {
+		return err
+	}
+	var terms []poeditor.Term
+	if *tf.file != "" {
+		if err := a.readJSONFile(*tf.file, &terms); err != nil {
+			return err
+		}
+	} else {
+		terms = []poeditor.Term{tf.term(fs.Arg(0))}
+	}
+	p, err := o.projectClient()
+	if err != nil {
+		return err
+	}
+	res, err := p.AddTerms(terms)
+	if err != nil {
+		return err
+	}
+	return out.printCount(res, map[string]poeditor.CountResult{"terms": res})
+}
+
+func runTermsUpdate(a *app, args []string) error {
+	fs := a.flags("terms update")
+	o := a.apiFlags(fs, true)
+	out := a.outputFlags(fs)
+	tf := newTermFlags(fs)
+	newTerm := fs.String("new-term", "", "new term text")
+	newContext := fs.String("new-context", "", "new term context")
+	fuzzyTrigger := fs.Bool("fuzzy-trigger", false, "mark translations of the updated terms fuzzy")
+	if err := tf.parse(fs, args); err != nil {
+		return err
+	}
+	var terms []poeditor.TermUpdate
+	if *tf.file != "" {
+		if err := a.readJSONFile(*tf.file, &terms); err != nil {
+			return err
+		}
+	} else {
+		terms = []poeditor.TermUpdate{{
+			Term:       tf.term(fs.Arg(0)),
+			NewTerm:    *newTerm,
+			NewContext: *newContext,
+		}}
+	}
+	p, err := o.projectClient()
+	if err != nil {
+		return err
+	}
+	res, err := p.UpdateTerms(terms, *fuzzyTrigger)
+	if err != nil {
+		return err
+	}
+	return out.printCount(res, map[string]poeditor.CountResult{"terms": res})
+}
+
+func runTermsDelete(a *app, args []string) error {
+	fs := a.flags("terms delete")
+	o := a.apiFlags(fs, true)
+	out := a.outputFlags(fs)
+	context := fs.String("context", "", "context of the terms")
+	if err := parse(fs, args, 1, -1); err != nil {
+		return err
+	}
+	terms := make([]poeditor.TermBase, fs.NArg())
+	for i, t := range fs.Args() {
+		terms[i] = poeditor.TermBase{Term: t, Context: *context}
+	}
+	p, err := o.projectClient()
+	if err != nil {
+		return err
+	}
+	res, err := p.DeleteTerms(terms)
+	if err != nil {
+		return err
+	}
+	return out.printCount(res, map[string]poeditor.CountResult{"terms": res})
+}
+
+func runTermsComment(a *app, args []string) error {
+	fs := a.flags("terms comment")
+	o := a.apiFlags(fs, true)
+	out := a.outputFlags(fs)
+	context := fs.String("context", "", "context of the term")
+	if err := parse(fs, args, 2, 2); err != nil {
+		return err
+	}
+	p, err := o.projectClient()
+	if err != nil {
+		return err
+	}
+	res, err := p.AddComments([]poeditor.TermComment{{
+		TermBase: poeditor.TermBase{Term: fs.Arg(0), Context: *context},
+		Comment:  fs.Arg(1),
+	}})
+	if err != nil {
+		return err
+	}
+	return out.printCount(res, map[string]poeditor.CountResult{"terms": res})
+}
+
+// translationText returns the content of a translation for display
+func translationText(t poeditor.Translation) string {
+	switch c := t.Content.(type) {
+	case string:
+		return c
+	case poeditor.Plural:
+		forms := make([]string, 0, 6)
+		for _, f := range c.Forms() {
+			forms = append(forms, f.Category+": "+f.Text)
+		}
+		return strings.Join(forms, " | ")
+	}
+	return ""
+}
+
+func translationState(t poeditor.Translation) string {
+	var states []string
+	if t.Fuzzy == 1 {
+		states = append(states, "fuzzy")
+	}
+	if t.Proofread == 1 {
+		states = append(states, "proofread")
+	}
+	return strings.Join(states, ",")
+}
@@ -0,0 +1,109 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+
+	"github.com/blacksails/poeditor"
+	"github.com/blacksails/poeditor/tm"
+)
+
+func runTMBuild(a *app, args []string) error {
+	fs := a.flags("tm build")
+	o := a.apiFlags(fs, false)
+	output := fs.String("o", "-", "output file, - for stdout")
+	if err := parse(fs, args, 0, 0); err != nil {
+		return err
+	}
+	poe, err := o.client()
+	if err != nil {
+		return err
+	}
+	m, err := tm.Build(poe)
+	if err != nil {
+		return err
+	}
+	var buf bytes.Buffer
+	if err := m.Save(&buf); err != nil {
+		return err
+	}
+	fmt.Fprintf(a.stderr, "remembered %d translations\n", len(m.Entries()))
+	if *output == "-" {
+		_, err = io.Copy(a.stdout, &buf)
+		return err
+	}
+	return os.WriteFile(*output, buf.Bytes(), 0644)
+}
+
+func runTMSuggest(a *app, args []string) error {
+	fs := a.flags("tm suggest")
+	o := a.apiFlags(fs, true)
+	out := a.outputFlags(fs)
+	memory := fs.String("memory", "", "translation memory file written by tm build")
+	language := fs.String("language", "", "language code to suggest translations for")
+	source := fs.String("source", "", "source language code, defaults to the reference language of the project")
+	min := fs.Float64("min", 0.75, "least score of suggestions, from 0 to 1 for exact matches only")
+	prefill := fs.Bool("prefill", false, "write the suggestions to the language, marking fuzzy matches fuzzy")
+	if err := parse(fs, args, 0, 0); err != nil {
+		return err
+	}
+	if *memory == "" {
+		return fmt.Errorf("%w: -memory is required", errUsage)
+	}
+	if *language == "" {
+		return fmt.Errorf("%w: -language is required", errUsage)
+	}
+	if *min < 0 || *min > 1 {
+		return fmt.Errorf("%w: -min must be between 0 and 1", errUsage)
+	}
+	f, err := os.Open(*memory)
+	if err != nil {
+		return err
+	}
+	m, err := tm.Load(f)
+	f.Close()
+	if err != nil {
+		return err
+	}
+	p, err := o.projectClient()
+	if err != nil {
+		return err
+	}
+	if *source == "" {
+		view, err := p.POEditor.ViewProject(p.ID)
+		if err != nil {
+			return err
+		}
+		if *source = view.ReferenceLanguage; *source == "" {
+			return fmt.Errorf("%w: the project has no reference language, use -source", errUsage)
+		}
+	}
+	l := poeditor.Language{Project: p, Code: *language}
+	var suggestions []tm.Suggestion
+	if *prefill {
+		var c poeditor.CountResult
+		if suggestions, c, err = m.Prefill(l, *source, *min); err != nil {
+			return err
+		}
+		fmt.Fprintf(a.stderr, "added %d and updated %d translations\n", c.Added, c.Updated)
+	} else if suggestions, err = m.SuggestLanguage(l, *source, *min); err != nil {
+		return err
+	}
+	rows := make([][]string, len(suggestions))
+	for i, s := range suggestions {
+		rows[i] = []string{
+			termName(s.TermBase),
+			strconv.FormatFloat(s.Match.Score, 'f', 2, 64),
+			s.Match.Source,
+			s.Match.Translation,
+			strconv.Itoa(s.Match.Project),
+		}
+	}
+	if suggestions == nil {
+		suggestions = []tm.Suggestion{}
+	}
+	return out.print(suggestions, []string{"TERM", "SCORE", "MATCHED SOURCE", "TRANSLATION", "PROJECT"}, rows)
+}
@@ -0,0 +1,24 @@
+package main
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestTM(t *testing.T) {
+	dir := t.TempDir()
+	memory := writeFile(t, dir, "memory.json", `[{"source_language": "en", "language": "de", "source": "Welcome",
+"translation": "Willkommen", "project": 9, "term": "welcome"}]`)
+	testApp(t, []appTest{
+		{name: "tm build", args: []string{"tm", "build"}, code: exitOK,
+			stdout: []string{`"translation": "Speichern"`}, stderr: []string{"remembered 2 translations"}},
+		{name: "tm suggest", args: []string{"tm", "suggest", "-json", "-memory", memory, "-project", "1", "-language", "de"},
+			code: exitOK, stdout: []string{`"term": "welcome"`, `"translation": "Willkommen"`}},
+		{name: "tm prefill", args: []string{"tm", "suggest", "-memory", memory, "-project", "1", "-language", "de", "-prefill"},
+			code: exitOK, stdout: []string{"welcome  1.00"}, stderr: []string{"added 0 and updated 1 translations"}},
+		{name: "tm memory", args: []string{"tm", "suggest", "-memory", filepath.Join(dir, "missing.json"), "-project", "1", "-language", "de"},
+			code: exitError, stderr: []string{"missing.json"}},
+		{name: "tm min", args: []string{"tm", "suggest", "-memory", memory, "-language", "de", "-min", "2"},
+			code: exitUsage, stderr: []string{"-min must be between 0 and 1"}},
+	})
+}
@@ -0,0 +1,92 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/blacksails/poeditor"
+)
+
+func runUpload(a *app, args []string) error {
+	fs := a.flags("upload")
+	o := a.apiFlags(fs, true)
+	out := a.outputFlags(fs)
+	updating := fs.String("updating", poeditor.UploadTerms, "what to update: terms, translations or terms_translations")
+	language := fs.String("language", "", "language code of the translations")
+	overwrite := fs.Bool("overwrite", false, "overwrite existing translations")
+	syncTerms := fs.Bool("sync-terms", false, "delete terms missing from the file")
+	tags := fs.String("tags", "", "comma separated tags for the uploaded terms")
+	readFromSource := fs.Bool("read-from-source", false, "read translations from the source tags of xliff files")
+	fuzzyTrigger := fs.Bool("fuzzy-trigger", false, "mark translations of changed terms fuzzy")
+	if err := parse(fs, args, 1, 1); err != nil {
+		return err
+	}
+	p, err := o.projectClient()
+	if err != nil {
+		return err
+	}
+	f, err := os.Open(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	res, err := p.UploadFile(fs.Arg(0), f, poeditor.UploadOptions{
+		Updating:       *updating,
+		Language:       poeditor.Language{Project: p, Code: *language},
+		Overwrite:      *overwrite,
+		SyncTerms:      *syncTerms,
+		Tags:           splitList(*tags),
+		ReadFromSource: *readFromSource,
+		FuzzyTrigger:   *fuzzyTrigger,
+	})
+	var diags *poeditor.DiagnosticsError
+	switch {
+	case errors.As(err, &diags):
+		for _, d := range diags.Diagnostics {
+			fmt.Fprintf(a.stderr, "%s:%s\n", diags.Name, d)
+		}
+		return fmt.Errorf("%s failed validation, nothing was uploaded", diags.Name)
+	case errors.Is(err, poeditor.ErrorUploadUpdating), errors.Is(err, poeditor.ErrorUploadLanguage):
+		return fmt.Errorf("%w: %s", errUsage, err)
+	case err != nil:
+		return err
+	}
+	return out.printCount(res, map[string]poeditor.CountResult{
+		"terms":        res.Terms,
+		"translations": res.Translations,
+	})
+}
+
+func runExport(a *app, args []string) error {
+	fs := a.flags("export")
+	o := a.apiFlags(fs, true)
+	language := fs.String("language", "", "language code to export")
+	format := fs.String("format", poeditor.FileFormatPO, "file format, see the poeditor FileFormat constants")
+	filters := fs.String("filters", "", "comma separated filters, such as translated or not_fuzzy")
+	tags := fs.String("tags", "", "comma separated tags to export")
+	output := fs.String("o", "-", "output file, - for stdout")
+	if err := parse(fs, args, 0, 0); err != nil {
+		return err
+	}
+	if *language == "" {
+		return fmt.Errorf("%w: -language is required", errUsage)
+	}
+	p, err := o.projectClient()
+	if err != nil {
+		return err
+	}
+	// Export into memory first, so a failed export leaves the output untouched
+	var buf bytes.Buffer
+	l := poeditor.Language{Project: p, Code: *language}
+	if err := l.Export(*format, splitList(*filters), splitList(*tags), &buf); err != nil {
+		return err
+	}
+	if *output == "-" {
+		_, err = io.Copy(a.stdout, &buf)
+		return err
+	}
+	return os.WriteFile(*output, buf.Bytes(), 0644)
+}
@@ -0,0 +1,81 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/blacksails/poeditor"
+	"github.com/blacksails/poeditor/extract"
+)
+
+// usageReport is printed by poe usage
+type usageReport struct {
+	Unused    []poeditor.Term `json:"unused"`
+	Undefined []poeditor.Term `json:"undefined"`
+}
+
+func runUsage(a *app, args []string) error {
+	fs := a.flags("usage")
+	o := a.apiFlags(fs, true)
+	out := a.outputFlags(fs)
+	defaults := make([]string, len(extract.DefaultFuncs))
+	for i, fn := range extract.DefaultFuncs {
+		defaults[i] = fn.String()
+	}
+	funcs := fs.String("funcs", strings.Join(defaults, ", "), "translation functions, with the arguments named term, plural, context or _")
+	tests := fs.Bool("tests", false, "include _test.go files")
+	tagObsolete := fs.Bool("tag-obsolete", false, "tag unused terms in POEditor")
+	tag := fs.String("tag", extract.ObsoleteTag, "tag used by -tag-obsolete")
+	if err := parse(fs, args, 0, 1); err != nil {
+		return err
+	}
+	dir := "."
+	if fs.NArg() == 1 {
+		dir = fs.Arg(0)
+	}
+	e := extract.Extractor{Tests: *tests}
+	var err error
+	if e.Funcs, err = extract.ParseFuncs(*funcs); err != nil {
+		return fmt.Errorf("%w: %s", errUsage, err)
+	}
+	p, err := o.projectClient()
+	if err != nil {
+		return err
+	}
+	res, err := e.Dir(dir)
+	if err != nil {
+		return err
+	}
+	for _, w := range res.Warnings {
+		fmt.Fprintf(a.stderr, "warning: %s\n", w)
+	}
+	terms, err := p.ListTerms()
+	if err != nil {
+		return err
+	}
+	u := extract.CompareUsage(res.Terms, terms)
+	if *tagObsolete {
+		c, err := u.TagObsolete(p, *tag)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(a.stderr, "tagged %d terms %s\n", c.Updated, *tag)
+	}
+	var rows [][]string
+	for _, t := range u.Unused {
+		rows = append(rows, []string{"unused", termName(t.TermBase), strings.Join(t.Tags, ",")})
+	}
+	for _, t := range u.Undefined {
+		rows = append(rows, []string{"undefined", termName(t.TermBase), t.Reference})
+	}
+	return out.print(usageReport{Unused: nonNil(u.Unused), Undefined: nonNil(u.Undefined)},
+		[]string{"STATE", "TERM", "TAGS/REFERENCE"}, rows)
+}
+
+// nonNil makes empty lists print as [] in JSON
+func nonNil(terms []poeditor.Term) []poeditor.Term {
+	if terms == nil {
+		return []poeditor.Term{}
+	}
+	return terms
+}
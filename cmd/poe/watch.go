@@ -0,0 +1,286 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/blacksails/poeditor"
+)
+
+func runWatch(a *app, args []string) error {
+	fs := a.flags("watch")
+	r := a.repoFlags(fs)
+	poll := fs.Duration("poll", time.Second, "how often the files are checked for changes")
+	debounce := fs.Duration("debounce", 500*time.Millisecond, "how long the files must be unchanged before pushing")
+	interval := fs.Duration("interval", uploadInterval, "minimum time between pushes")
+	fuzzyTrigger := fs.Bool("fuzzy-trigger", false, "mark translations of updated terms fuzzy")
+	del := fs.Bool("delete", false, "offer to delete terms removed from the files")
+	yes := fs.Bool("yes", false, "delete without asking, requires -delete")
+	if err := parse(fs, args, 0, 0); err != nil {
+		return err
+	}
+	c, p, err := r.load()
+	if err != nil {
+		return err
+	}
+	source, err := r.sourceLanguage(c, p)
+	if err != nil {
+		return err
+	}
+	w := &watcher{
+		app:          a,
+		project:      p,
+		source:       source,
+		fuzzyTrigger: *fuzzyTrigger,
+		delete:       *del,
+		yes:          *yes,
+		stdin:        bufio.NewReader(a.stdin),
+		stamps:       make(map[string]fileStamp),
+	}
+	for _, f := range c.Files {
+		if !contains(poeditor.Formats(), f.Format) {
+			fmt.Fprintf(a.stderr, "warning: cannot watch %s, reading %s is not supported\n", f.Path, f.Format)
+			continue
+		}
+		w.files = append(w.files, watchedFile{path: f.path(c.dir, source), format: f.Format})
+	}
+	if len(w.files) == 0 {
+		return fmt.Errorf("no files to watch")
+	}
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	return w.run(ctx, *poll, *debounce, *interval)
+}
+
+type watchedFile struct {
+	path   string
+	format string
+}
+
+// fileStamp identifies a version of a file without reading it
+type fileStamp struct {
+	modTime time.Time
+	size    int64
+}
+
+// watcher pushes changes to the source language files of the repo config.
+// The last pushed state is kept in memory, so only changes made while
+// watching are pushed.
+type watcher struct {
+	app          *app
+	project      *poeditor.Project
+	source       string
+	files        []watchedFile
+	fuzzyTrigger bool
+	delete       bool
+	yes          bool
+	stdin        *bufio.Reader
+
+	stamps map[string]fileStamp
+	state  []poeditor.TermTranslated
+}
+
+func (w *watcher) run(ctx context.Context, poll, debounce, interval time.Duration) error {
+	w.changed()
+	state, err := w.read()
+	if err != nil {
+		return err
+	}
+	w.state = state
+	fmt.Fprintf(w.app.stderr, "watching %d files, %d terms\n", len(w.files), len(state))
+	s := schedule{debounce: debounce, interval: interval}
+	ticker := time.NewTicker(poll)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case now := <-ticker.C:
+			if w.changed() {
+				s.changed(now)
+			}
+			if !s.due(now) {
+				continue
+			}
+			pushed, err := w.push()
+			if err != nil {
+				fmt.Fprintf(w.app.stderr, "error: %s\n", err)
+			}
+			s.pushed(now, pushed, err)
+		}
+	}
+}
+
+// schedule decides when changes are pushed: once the files are unchanged for
+// the debounce time, and no sooner than the interval after the last push
+type schedule struct {
+	debounce   time.Duration
+	interval   time.Duration
+	lastChange time.Time
+	lastPush   time.Time
+	dirty      bool
+}
+
+// changed records a change of the files
+func (s *schedule) changed(now time.Time) {
+	s.lastChange, s.dirty = now, true
+}
+
+// due reports whether the changes should be pushed
+func (s *schedule) due(now time.Time) bool {
+	return s.dirty && now.Sub(s.lastChange) >= s.debounce && now.Sub(s.lastPush) >= s.interval
+}
+
+// pushed records the result of a push. The state is left as is on errors, so
+// the changes are retried after the interval.
+func (s *schedule) pushed(now time.Time, pushed bool, err error) {
+	s.dirty = err != nil
+	if pushed || err != nil {
+		s.lastPush = now
+	}
+}
+
+// changed stats the files and reports whether any of them changed since the
+// last call
+func (w *watcher) changed() bool {
+	changed := false
+	for _, f := range w.files {
+		var stamp fileStamp
+		if fi, err := os.Stat(f.path); err == nil {
+			stamp = fileStamp{modTime: fi.ModTime(), size: fi.Size()}
+		}
+		if w.stamps[f.path] != stamp {
+			w.stamps[f.path] = stamp
+			changed = true
+		}
+	}
+	return changed
+}
+
+// read decodes the terms of all files. Missing files have no terms.
+func (w *watcher) read() ([]poeditor.TermTranslated, error) {
+	var terms []poeditor.TermTranslated
+	for _, f := range w.files {
+		ts, err := decodeFile(f.path, f.format)
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", f.path, err)
+		}
+		terms = append(terms, ts...)
+	}
+	return terms, nil
+}
+
+// push sends the difference between the files and the last pushed state. It
+// reports whether anything was sent.
+func (w *watcher) push() (bool, error) {
+	terms, err := w.read()
+	if err != nil {
+		return false, err
+	}
+	diff := poeditor.DiffTerms(termsOf(w.state), termsOf(terms))
+	translations := changedTranslations(w.state, terms)
+	if diff.Empty() && len(translations) == 0 {
+		return false, nil
+	}
+	if len(diff.Added) > 0 {
+		res, err := w.project.AddTerms(diff.Added)
+		if err != nil {
+			return true, err
+		}
+		fmt.Fprintf(w.app.stderr, "added %d terms\n", res.Added)
+	}
+	if len(diff.Changed) > 0 {
+		updates := make([]poeditor.TermUpdate, len(diff.Changed))
+		for i, t := range diff.Changed {
+			updates[i] = poeditor.TermUpdate{Term: t}
+		}
+		res, err := w.project.UpdateTerms(updates, w.fuzzyTrigger)
+		if err != nil {
+			return true, err
+		}
+		fmt.Fprintf(w.app.stderr, "updated %d terms\n", res.Updated)
+	}
+	if len(translations) > 0 {
+		l := poeditor.Language{Project: w.project, Code: w.source}
+		res, err := l.Update(translations)
+		if err != nil {
+			return true, err
+		}
+		fmt.Fprintf(w.app.stderr, "updated %d %s translations\n", res.Updated+res.Added, w.source)
+	}
+	if len(diff.Removed) > 0 {
+		if err := w.remove(diff.Removed); err != nil {
+			return true, err
+		}
+	}
+	w.state = terms
+	return true, nil
+}
+
+// remove deletes terms removed from the files, but only with -delete and
+// after confirmation
+func (w *watcher) remove(terms []poeditor.Term) error {
+	names := make([]string, len(terms))
+	bases := make([]poeditor.TermBase, len(terms))
+	for i, t := range terms {
+		names[i] = termName(t.TermBase)
+		bases[i] = t.TermBase
+	}
+	fmt.Fprintf(w.app.stderr, "removed from the files: %s\n", strings.Join(names, ", "))
+	if !w.delete {
+		return nil
+	}
+	if !w.yes {
+		fmt.Fprintf(w.app.stderr, "delete %d terms from POEditor? [y/N] ", len(terms))
+		answer, _ := w.stdin.ReadString('\n')
+		if a := strings.ToLower(strings.TrimSpace(answer)); a != "y" && a != "yes" {
+			return nil
+		}
+	}
+	res, err := w.project.DeleteTerms(bases)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(w.app.stderr, "deleted %d terms\n", res.Deleted)
+	return nil
+}
+
+func termsOf(ts []poeditor.TermTranslated) []poeditor.Term {
+	terms := make([]poeditor.Term, len(ts))
+	for i, t := range ts {
+		terms[i] = t.Term
+	}
+	return terms
+}
+
+// changedTranslations returns the source language translations which are new
+// or differ from the old state
+func changedTranslations(old, new []poeditor.TermTranslated) []poeditor.TermTranslation {
+	olds := make(map[poeditor.TermBase]interface{}, len(old))
+	for _, t := range old {
+		olds[t.TermBase] = t.Translation.Content
+	}
+	var changed []poeditor.TermTranslation
+	for _, t := range new {
+		if poeditor.EmptyContent(t.Translation.Content) {
+			continue
+		}
+		if c, ok := olds[t.TermBase]; ok && reflect.DeepEqual(c, t.Translation.Content) {
+			continue
+		}
+		changed = append(changed, poeditor.TermTranslation{
+			TermBase:    t.TermBase,
+			Translation: poeditor.Translation{Content: t.Translation.Content},
+		})
+	}
+	return changed
+}
@@ -0,0 +1,175 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/blacksails/poeditor"
+)
+
+func TestSchedule(t *testing.T) {
+	start := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	at := func(ms int) time.Time { return start.Add(time.Duration(ms) * time.Millisecond) }
+	type event struct {
+		at      int
+		change  bool
+		due     bool
+		failure bool
+	}
+	tests := []struct {
+		name   string
+		events []event
+	}{
+		{"nothing changed", []event{{at: 1000}, {at: 2000}}},
+		{"debounced", []event{
+			{at: 0, change: true},
+			{at: 300},
+			{at: 500, due: true},
+		}},
+		{"changes keep debouncing", []event{
+			{at: 0, change: true},
+			{at: 400, change: true},
+			{at: 800},
+			{at: 900, due: true},
+		}},
+		{"interval between pushes", []event{
+			{at: 0, change: true},
+			{at: 500, due: true},
+			{at: 600, change: true},
+			{at: 1200},
+			{at: 3000},
+			{at: 3500, due: true},
+		}},
+		{"failures are retried after the interval", []event{
+			{at: 0, change: true},
+			{at: 500, due: true, failure: true},
+			{at: 1000},
+			{at: 3500, due: true},
+			{at: 4000},
+		}},
+	}
+	for _, test := range tests {
+		s := schedule{debounce: 500 * time.Millisecond, interval: 3 * time.Second}
+		for _, e := range test.events {
+			if e.change {
+				s.changed(at(e.at))
+			}
+			if due := s.due(at(e.at)); due != e.due {
+				t.Errorf("%s: at %dms expected due %t, got %t", test.name, e.at, e.due, due)
+			}
+			if e.due {
+				var err error
+				if e.failure {
+					err = errors.New("push failed")
+				}
+				s.pushed(at(e.at), true, err)
+			}
+		}
+	}
+}
+
+func TestWatcherRemove(t *testing.T) {
+	removed := []poeditor.Term{{TermBase: poeditor.TermBase{Term: "old"}}}
+	tests := []struct {
+		name    string
+		delete  bool
+		yes     bool
+		answer  string
+		deleted bool
+	}{
+		{name: "without -delete", answer: "y\n"},
+		{name: "declined", delete: true, answer: "n\n"},
+		{name: "no answer", delete: true},
+		{name: "confirmed", delete: true, answer: "yes\n", deleted: true},
+		{name: "with -yes", delete: true, yes: true, deleted: true},
+	}
+	for _, test := range tests {
+		api := newFakeAPI(t, map[string]func(map[string]string) interface{}{
+			"/terms/delete": func(form map[string]string) interface{} {
+				if form["data"] != `[{"term":"old"}]` {
+					t.Errorf("%s: unexpected data %s", test.name, form["data"])
+				}
+				return map[string]interface{}{"terms": poeditor.CountResult{Parsed: 1, Deleted: 1}}
+			},
+		})
+		var stderr bytes.Buffer
+		w := &watcher{
+			app:     &app{stderr: &stderr},
+			project: poeditor.NewWithEndpoint("token", api.URL).Project(1),
+			delete:  test.delete,
+			yes:     test.yes,
+			stdin:   bufio.NewReader(strings.NewReader(test.answer)),
+		}
+		if err := w.remove(removed); err != nil {
+			t.Fatalf("%s: unexpected error: %s", test.name, err)
+		}
+		if deleted := len(api.called()) > 0; deleted != test.deleted {
+			t.Errorf("%s: expected deleted %t, got %t", test.name, test.deleted, deleted)
+		}
+		asked := strings.Contains(stderr.String(), "[y/N]")
+		if asked != (test.delete && !test.yes) {
+			t.Errorf("%s: unexpected prompt in %q", test.name, stderr.String())
+		}
+		if !strings.Contains(stderr.String(), "removed from the files: old") {
+			t.Errorf("%s: expected the removed terms to be listed, got %q", test.name, stderr.String())
+		}
+	}
+}
+
+func TestChangedTranslations(t *testing.T) {
+	term := func(name string, content interface{}) poeditor.TermTranslated {
+		var t poeditor.TermTranslated
+		t.Term.Term = name
+		t.Translation.Content = content
+		return t
+	}
+	old := []poeditor.TermTranslated{
+		term("same", "Same"),
+		term("changed", "Old"),
+		term("plural", poeditor.Plural{One: "file", Other: "files"}),
+	}
+	new := []poeditor.TermTranslated{
+		term("same", "Same"),
+		term("changed", "New"),
+		term("plural", poeditor.Plural{One: "file", Other: "files"}),
+		term("added", "Added"),
+		term("empty", ""),
+		term("empty plural", poeditor.Plural{}),
+	}
+	expected := []poeditor.TermTranslation{
+		{TermBase: poeditor.TermBase{Term: "changed"}, Translation: poeditor.Translation{Content: "New"}},
+		{TermBase: poeditor.TermBase{Term: "added"}, Translation: poeditor.Translation{Content: "Added"}},
+	}
+	if got := changedTranslations(old, new); !reflect.DeepEqual(got, expected) {
+		t.Errorf("\nExpected %+v \nGot      %+v", expected, got)
+	}
+}
+
+func TestWatch(t *testing.T) {
+	dir := t.TempDir()
+	config := writeFile(t, dir, ".poeditor.yml", `project_id: 1
+source_language: en
+languages: [de]
+files:
+  - path: locales/{lang}.json
+    format: key_value_json
+`)
+	unwatchable := writeFile(t, dir, "unwatchable.yml", `project_id: 1
+source_language: en
+languages: [en]
+files:
+  - path: strings.xlsx
+    format: xlsx
+`)
+	testApp(t, []appTest{
+		{name: "watch flags", args: []string{"watch", "-config", config, "-nope"}, code: exitUsage,
+			stderr: []string{"usage: poe watch"}},
+		{name: "watch unsupported", args: []string{"watch", "-config", unwatchable}, code: exitError,
+			stderr: []string{"cannot watch strings.xlsx", "no files to watch"}},
+	})
+}
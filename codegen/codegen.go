@@ -0,0 +1,243 @@
+// Package codegen generates Go code from the terms of a project.
+//
+// Accessors generates a type with one method per term, so that code refers to
+// terms by name and removing a term from the project breaks the build instead
+// of showing the raw key at runtime:
+//
+//	//go:generate poe generate -from locales/en.json -package messages -o messages.go
+//
+//	m := messages.New(catalog.Localizer("de"))
+//	m.Welcome()
+//	m.UnreadMessages(n)
+//
+// Methods take one parameter per printf verb of the message, typed after the
+// verb, and terms with a plural form take the count first. Method names are
+// made from the context and the term alone. Long terms, terms without ASCII
+// words and terms making the same name as another term get a hash of their
+// key appended, so adding a term never renames the method of another term
+// unless their names collide.
+//
+// XTextCatalog generates a golang.org/x/text/message catalog holding the
+// translations of every language, for programs printing with message.Printer.
+package codegen
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"go/token"
+	"hash/fnv"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/blacksails/poeditor"
+	"github.com/blacksails/poeditor/internal/placeholder"
+)
+
+// maxNameWords limits the length of method names made from long terms
+const maxNameWords = 6
+
+// Accessors describes the generated accessors
+type Accessors struct {
+	// Package is the name of the generated package
+	Package string
+	// Type is the name of the generated type, Messages by default. Its
+	// constructor is New followed by the type name, or New for Messages.
+	Type string
+	// Command is mentioned in the generated header, e.g. poe generate
+	Command string
+}
+
+// Generate returns the gofmt'ed source of the accessors of the terms. The
+// placeholders of a term are read from its translation when it has one, so
+// the terms of the source language give the best result.
+func (a Accessors) Generate(terms []poeditor.TermTranslated) ([]byte, error) {
+	if !token.IsIdentifier(a.Package) {
+		return nil, fmt.Errorf("codegen: invalid package name %q", a.Package)
+	}
+	typ := a.Type
+	if typ == "" {
+		typ = "Messages"
+	}
+	if !token.IsExported(typ) || !token.IsIdentifier(typ) {
+		return nil, fmt.Errorf("codegen: invalid type name %q", typ)
+	}
+	ctor := "New" + typ
+	if typ == "Messages" {
+		ctor = "New"
+	}
+	command := a.Command
+	if command == "" {
+		command = "codegen"
+	}
+
+	terms = append([]poeditor.TermTranslated(nil), terms...)
+	sort.SliceStable(terms, func(i, j int) bool {
+		if terms[i].Context != terms[j].Context {
+			return terms[i].Context < terms[j].Context
+		}
+		return terms[i].Term.Term < terms[j].Term.Term
+	})
+
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "// Code generated by %s; DO NOT EDIT.\n\n", command)
+	fmt.Fprintf(&b, "package %s\n\n", a.Package)
+	fmt.Fprintf(&b, "import %q\n\n", "github.com/blacksails/poeditor/localize")
+	fmt.Fprintf(&b, "// %s looks up the terms of the project in a localizer\n", typ)
+	fmt.Fprintf(&b, "type %s struct {\n\tl *localize.Localizer\n}\n\n", typ)
+	fmt.Fprintf(&b, "// %s returns the accessors of a localizer\n", ctor)
+	fmt.Fprintf(&b, "func %s(l *localize.Localizer) %s {\n\treturn %s{l: l}\n}\n", ctor, typ, typ)
+
+	// Count the terms making each name first, so colliding terms all get
+	// their hash regardless of their order
+	var deduped []poeditor.TermTranslated
+	seen := make(map[poeditor.TermBase]bool)
+	names := make(map[string]int)
+	for _, t := range terms {
+		if seen[t.TermBase] {
+			continue
+		}
+		seen[t.TermBase] = true
+		deduped = append(deduped, t)
+		names[methodName(t.TermBase)]++
+	}
+	written := make(map[string]poeditor.TermBase)
+	for _, t := range deduped {
+		name := methodName(t.TermBase)
+		if names[name] > 1 {
+			name += keyHash(t.TermBase)
+		}
+		if other, ok := written[name]; ok {
+			return nil, fmt.Errorf("codegen: %q and %q both make the method name %s", other.Term, t.Term.Term, name)
+		}
+		written[name] = t.TermBase
+		writeMethod(&b, typ, name, t)
+	}
+	src, err := format.Source(b.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("codegen: %w", err)
+	}
+	return src, nil
+}
+
+// writeMethod writes the accessor of a term
+func writeMethod(b *bytes.Buffer, typ, name string, t poeditor.TermTranslated) {
+	plural := t.Plural != ""
+	if p, ok := t.Translation.Content.(poeditor.Plural); ok && p != (poeditor.Plural{}) {
+		plural = true
+	}
+	msg := message(t)
+	args := placeholder.Args(msg)
+
+	var params, values []string
+	for i, typ := range args {
+		v := "arg" + strconv.Itoa(i+1)
+		params = append(params, v+" "+typ)
+		values = append(values, v)
+	}
+	lookup := "m.l"
+	if t.Context != "" {
+		lookup += ".Context(" + strconv.Quote(t.Context) + ")"
+	}
+	key := strconv.Quote(t.Term.Term)
+
+	b.WriteString("\n")
+	if plural {
+		fmt.Fprintf(b, "// %s returns the plural form of %s for count.\n", name, strconv.Quote(t.Term.Term))
+	} else {
+		fmt.Fprintf(b, "// %s returns %s.\n", name, strconv.Quote(t.Term.Term))
+	}
+	if t.Context != "" {
+		fmt.Fprintf(b, "// Context: %s.\n", strconv.Quote(t.Context))
+	}
+	if c := strings.TrimSpace(t.Comment); c != "" {
+		b.WriteString("//\n")
+		for _, line := range strings.Split(c, "\n") {
+			b.WriteString(strings.TrimRight("// "+line, " \r") + "\n")
+		}
+	}
+
+	var call string
+	switch {
+	case !plural:
+		call = lookup + ".T(" + strings.Join(append([]string{key}, values...), ", ") + ")"
+	case len(args) > 0 && (args[0] == "int" || args[0] == "float64"):
+		// The first verb prints the count
+		params[0] = "count " + args[0]
+		values[0] = "count"
+		if len(values) == 1 {
+			values = nil
+		}
+		call = lookup + ".N(" + strings.Join(append([]string{key, "count"}, values...), ", ") + ")"
+	default:
+		params = append([]string{"count int"}, params...)
+		call = lookup + ".N(" + strings.Join(append([]string{key, "count"}, values...), ", ") + ")"
+	}
+	fmt.Fprintf(b, "func (m %s) %s(%s) string {\n\treturn %s\n}\n", typ, name, strings.Join(params, ", "), call)
+}
+
+// message returns the text whose placeholders become parameters: the
+// translation when there is one, or else the term or its plural form
+func message(t poeditor.TermTranslated) string {
+	switch c := t.Translation.Content.(type) {
+	case string:
+		if c != "" {
+			return c
+		}
+	case poeditor.Plural:
+		if c.Other != "" {
+			return c.Other
+		}
+	}
+	if t.Plural != "" {
+		return t.Plural
+	}
+	return t.Term.Term
+}
+
+// methodName turns a term into an exported identifier, prefixed with its
+// context: "menu" and "Open file" become MenuOpenFile. Placeholders are left
+// out. Names of long terms and terms without words end in the key hash, as
+// they are not made from the whole term.
+func methodName(t poeditor.TermBase) string {
+	words := append(nameWords(t.Context), nameWords(t.Term)...)
+	hash := len(words) == 0
+	if len(words) > maxNameWords {
+		words = words[:maxNameWords]
+		hash = true
+	}
+	name := strings.Join(words, "")
+	if name == "" || !unicode.IsLetter(rune(name[0])) {
+		name = "Term" + name
+	}
+	if hash {
+		name += keyHash(t)
+	}
+	return name
+}
+
+// keyHash returns a hash of the context and term, distinguishing method
+// names made from different terms
+func keyHash(t poeditor.TermBase) string {
+	h := fnv.New32a()
+	h.Write([]byte(t.Context + "\x04" + t.Term))
+	return fmt.Sprintf("%08x", h.Sum32())
+}
+
+// nameWords returns the ASCII words of s, each starting with an upper case
+// letter
+func nameWords(s string) []string {
+	ps := placeholder.Parse(s)
+	for i := len(ps) - 1; i >= 0; i-- {
+		s = s[:ps[i].Start] + " " + s[ps[i].End:]
+	}
+	words := strings.FieldsFunc(s, func(r rune) bool {
+		return !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9')
+	})
+	for i, w := range words {
+		words[i] = strings.ToUpper(w[:1]) + w[1:]
+	}
+	return words
+}
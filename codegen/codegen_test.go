@@ -0,0 +1,220 @@
+package codegen_test
+
+import (
+	"fmt"
+	"go/ast"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"strings"
+	"testing"
+
+	"github.com/blacksails/poeditor"
+	"github.com/blacksails/poeditor/codegen"
+)
+
+func TestAccessorsGenerate(t *testing.T) {
+	term := func(term, context, plural, comment string, content interface{}) poeditor.TermTranslated {
+		var t poeditor.TermTranslated
+		t.Term.Term = term
+		t.Context = context
+		t.Plural = plural
+		t.Comment = comment
+		t.Translation.Content = content
+		return t
+	}
+	terms := []poeditor.TermTranslated{
+		term("welcome", "", "", "Shown on the start page", "Welcome, %s!"),
+		term("%d new messages", "", "%d new messages", "", poeditor.Plural{One: "%d new message", Other: "%d new messages"}),
+		term("files", "", "files", "", poeditor.Plural{One: "%[2]s has a file", Other: "%[2]s has %[1]d files"}),
+		term("Open", "menu", "", "", ""),
+		term("Open", "", "", "", ""),
+		term("open", "", "", "", ""),
+	}
+	src, err := codegen.Accessors{Package: "messages", Command: "poe generate"}.Generate(terms)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		"// Code generated by poe generate; DO NOT EDIT.\n\npackage messages\n",
+		"func New(l *localize.Localizer) Messages {",
+		"// NewMessages returns the plural form of \"%d new messages\" for count.\nfunc (m Messages) NewMessages(count int) string {\n\treturn m.l.N(\"%d new messages\", count)\n}",
+		"func (m Messages) Files(count int, arg2 string) string {\n\treturn m.l.N(\"files\", count, count, arg2)\n}",
+		"// Context: \"menu\".\nfunc (m Messages) MenuOpen() string {\n\treturn m.l.Context(\"menu\").T(\"Open\")\n}",
+		// Open and open make the same name, so both get their key hash
+		"func (m Messages) Opencd14fe1f() string {\n\treturn m.l.T(\"Open\")\n}",
+		"func (m Messages) Open663566ff() string {\n\treturn m.l.T(\"open\")\n}",
+		"// Welcome returns \"welcome\".\n//\n// Shown on the start page\nfunc (m Messages) Welcome(arg1 string) string {\n\treturn m.l.T(\"welcome\", arg1)\n}",
+	} {
+		if !strings.Contains(string(src), want) {
+			t.Errorf("Expected source to contain\n%s\nGot\n%s", want, src)
+		}
+	}
+	if _, err := (codegen.Accessors{Package: "my-messages"}).Generate(terms); err == nil {
+		t.Error("Expected an error for an invalid package name")
+	}
+}
+
+func TestAccessorsNames(t *testing.T) {
+	long := "This is a very long sentence about files"
+	tests := []struct {
+		name     string
+		terms    []string
+		expected []string
+	}{
+		{"plain", []string{"save"}, []string{"Save()"}},
+		// Adding a term does not rename the others
+		{"added", []string{"save", "Open"}, []string{"Save()", "Open()"}},
+		{"long", []string{long, long + " and folders"}, []string{"ThisIsAVeryLongSentence72eb459f()", "ThisIsAVeryLongSentence8725fff3()"}},
+		{"no words", []string{"%d"}, []string{"Term656cbad2("}},
+	}
+	for _, test := range tests {
+		terms := make([]poeditor.TermTranslated, len(test.terms))
+		for i, term := range test.terms {
+			terms[i] = newTerm(term, "", "")
+		}
+		src, err := codegen.Accessors{Package: "messages"}.Generate(terms)
+		if err != nil {
+			t.Errorf("%s: Unexpected error: %s", test.name, err)
+			continue
+		}
+		for _, want := range test.expected {
+			if !strings.Contains(string(src), "func (m Messages) "+want) {
+				t.Errorf("%s: Expected a method %s\nGot\n%s", test.name, want, src)
+			}
+		}
+	}
+}
+
+func TestXTextCatalogGenerate(t *testing.T) {
+	translations := map[string][]poeditor.TermTranslated{
+		"pt_BR": {
+			newTerm("welcome", "", "Bem-vindo"),
+			newTerm("Open", "menu", "Abrir"),
+			newTerm("files", "", poeditor.Plural{One: "%[2]s tem %[1]d arquivo", Other: "%[2]s tem %[1]d arquivos"}),
+			newTerm("missing", "", ""),
+		},
+		"fr": {newTerm("missing", "", "")},
+	}
+	src, err := codegen.XTextCatalog{Package: "translations", Fallback: "en"}.Generate(translations)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		"\t\"golang.org/x/text/feature/plural\"\n",
+		"b := catalog.NewBuilder(catalog.Fallback(language.MustParse(\"en\")))",
+		"langPtBR := language.MustParse(\"pt-BR\")",
+		"b.SetString(langPtBR, \"menu\\x04Open\", \"Abrir\")",
+		"b.Set(langPtBR, \"files\", plural.Selectf(1, \"%d\",\n\t\t\"one\", \"%[2]s tem %[1]d arquivo\",\n\t\t\"other\", \"%[2]s tem %[1]d arquivos\",\n\t))",
+		"message.DefaultCatalog = b",
+	} {
+		if !strings.Contains(string(src), want) {
+			t.Errorf("Expected source to contain\n%s\nGot\n%s", want, src)
+		}
+	}
+	for _, unwanted := range []string{"missing", "langFr"} {
+		if strings.Contains(string(src), unwanted) {
+			t.Errorf("Expected source not to contain %s\nGot\n%s", unwanted, src)
+		}
+	}
+	if got := codegen.MessageKey(poeditor.TermBase{Term: "Open", Context: "menu"}); got != "menu\x04Open" {
+		t.Errorf("Expected key %q, got %q", "menu\x04Open", got)
+	}
+}
+
+func TestXTextCatalogGenerateCompiles(t *testing.T) {
+	translations := map[string][]poeditor.TermTranslated{
+		"pt-BR":  {newTerm("welcome", "", "Bem-vindo")},
+		"pt_BR":  {newTerm("bye", "", "Tchau")},
+		"pt-BR2": {newTerm("files", "", poeditor.Plural{One: "%d arquivo", Other: "%d arquivos"})},
+		"fr":     {newTerm("welcome", "", "Bienvenue")},
+	}
+	for _, x := range []codegen.XTextCatalog{
+		{Package: "translations", Fallback: "en"},
+		{Package: "translations", Var: "Catalog"},
+	} {
+		src, err := x.Generate(translations)
+		if err != nil {
+			t.Fatal(err)
+		}
+		typeCheck(t, src)
+		for _, want := range []string{"langPtBR := ", "langPtBR2 := ", "langPtBR3 := "} {
+			if !strings.Contains(string(src), want) {
+				t.Errorf("Expected source to contain %s\nGot\n%s", want, src)
+			}
+		}
+	}
+}
+
+// xtextStubs declare the parts of golang.org/x/text used by generated
+// catalogs, so they can be type checked without the module
+var xtextStubs = map[string]string{
+	"golang.org/x/text/language": `package language
+type Tag struct{}
+func MustParse(s string) Tag { return Tag{} }`,
+	"golang.org/x/text/message/catalog": `package catalog
+import "golang.org/x/text/language"
+type Catalog interface{ Languages() []language.Tag }
+type Message interface{}
+type Option func()
+func Fallback(tag language.Tag) Option { return nil }
+type Builder struct{}
+func NewBuilder(opts ...Option) *Builder { return nil }
+func (b *Builder) Languages() []language.Tag { return nil }
+func (b *Builder) Set(tag language.Tag, key string, msg ...Message) error { return nil }
+func (b *Builder) SetString(tag language.Tag, key string, msg string) error { return nil }`,
+	"golang.org/x/text/message": `package message
+import "golang.org/x/text/message/catalog"
+var DefaultCatalog catalog.Catalog`,
+	"golang.org/x/text/feature/plural": `package plural
+import "golang.org/x/text/message/catalog"
+func Selectf(arg int, format string, cases ...interface{}) catalog.Message { return nil }`,
+}
+
+// stubImporter imports xtextStubs, and other packages from the standard
+// library
+type stubImporter map[string]*types.Package
+
+func (imp stubImporter) Import(path string) (*types.Package, error) {
+	if pkg, ok := imp[path]; ok {
+		return pkg, nil
+	}
+	src, ok := xtextStubs[path]
+	if !ok {
+		return importer.Default().Import(path)
+	}
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, path+".go", src, 0)
+	if err != nil {
+		return nil, err
+	}
+	pkg, err := (&types.Config{Importer: imp}).Check(path, fset, []*ast.File{f}, nil)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	imp[path] = pkg
+	return pkg, nil
+}
+
+// typeCheck parses and type checks generated source
+func typeCheck(t *testing.T, src []byte) {
+	t.Helper()
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, "catalog.go", src, 0)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s\n%s", err, src)
+	}
+	conf := types.Config{Importer: stubImporter{}}
+	if _, err := conf.Check("translations", fset, []*ast.File{f}, nil); err != nil {
+		t.Errorf("Unexpected error: %s\n%s", err, src)
+	}
+}
+
+func newTerm(term, context string, content interface{}) poeditor.TermTranslated {
+	var t poeditor.TermTranslated
+	t.Term.Term = term
+	t.Context = context
+	t.Translation.Content = content
+	return t
+}
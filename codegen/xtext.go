@@ -0,0 +1,180 @@
+package codegen
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"go/token"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/blacksails/poeditor"
+	"github.com/blacksails/poeditor/internal/placeholder"
+)
+
+// ContextSeparator separates the context from the term in the message keys
+// of terms with a context, as in gettext MO files
+const ContextSeparator = "\x04"
+
+// MessageKey returns the key of a term in generated x/text catalogs: the term,
+// prefixed with its context and ContextSeparator when it has one
+func MessageKey(t poeditor.TermBase) string {
+	if t.Context == "" {
+		return t.Term
+	}
+	return t.Context + ContextSeparator + t.Term
+}
+
+// XTextCatalog describes a golang.org/x/text/message/catalog built from the
+// translations of several languages, like the catalog.go file written by
+// gotext. Plural translations become plural.Selectf messages, selecting on
+// the argument of their first numeric printf verb.
+type XTextCatalog struct {
+	// Package is the name of the generated package
+	Package string
+	// Var is the name of the generated catalog variable. The catalog is
+	// installed as message.DefaultCatalog when it is empty.
+	Var string
+	// Fallback is the language used for messages missing in the requested
+	// language, usually the reference language of the project
+	Fallback string
+	// Command is mentioned in the generated header, e.g. poe generate
+	Command string
+}
+
+// Generate returns the gofmt'ed source of the catalog of the translations,
+// which are keyed by language code. Empty translations are left out.
+func (x XTextCatalog) Generate(translations map[string][]poeditor.TermTranslated) ([]byte, error) {
+	if !token.IsIdentifier(x.Package) {
+		return nil, fmt.Errorf("codegen: invalid package name %q", x.Package)
+	}
+	if x.Var != "" && !token.IsIdentifier(x.Var) {
+		return nil, fmt.Errorf("codegen: invalid variable name %q", x.Var)
+	}
+	command := x.Command
+	if command == "" {
+		command = "codegen"
+	}
+	langs := make([]string, 0, len(translations))
+	for lang := range translations {
+		langs = append(langs, lang)
+	}
+	sort.Strings(langs)
+
+	// Codes such as pt-BR and pt_BR share a variable name, which is
+	// numbered to keep the names unique
+	vars := make(map[string]string, len(langs))
+	used := make(map[string]bool, len(langs))
+	for _, lang := range langs {
+		v := langVar(lang)
+		for i := 2; used[v]; i++ {
+			v = langVar(lang) + strconv.Itoa(i)
+		}
+		used[v] = true
+		vars[lang] = v
+	}
+
+	var body bytes.Buffer
+	usesPlural := false
+	if x.Fallback != "" {
+		fmt.Fprintf(&body, "b := catalog.NewBuilder(catalog.Fallback(language.MustParse(%q)))\n", tag(x.Fallback))
+	} else {
+		body.WriteString("b := catalog.NewBuilder()\n")
+	}
+	for _, lang := range langs {
+		terms := append([]poeditor.TermTranslated(nil), translations[lang]...)
+		sort.SliceStable(terms, func(i, j int) bool {
+			return MessageKey(terms[i].TermBase) < MessageKey(terms[j].TermBase)
+		})
+		var msgs bytes.Buffer
+		for _, t := range terms {
+			if writeMessage(&msgs, vars[lang], t) {
+				usesPlural = true
+			}
+		}
+		if msgs.Len() == 0 {
+			continue
+		}
+		fmt.Fprintf(&body, "\n%s := language.MustParse(%q)\n", vars[lang], tag(lang))
+		msgs.WriteTo(&body)
+	}
+	if x.Var == "" {
+		body.WriteString("message.DefaultCatalog = b\n")
+	} else {
+		fmt.Fprintf(&body, "%s = b\n", x.Var)
+	}
+
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "// Code generated by %s; DO NOT EDIT.\n\n", command)
+	fmt.Fprintf(&b, "package %s\n\n", x.Package)
+	b.WriteString("import (\n")
+	if usesPlural {
+		b.WriteString("\t\"golang.org/x/text/feature/plural\"\n")
+	}
+	b.WriteString("\t\"golang.org/x/text/language\"\n")
+	if x.Var == "" {
+		b.WriteString("\t\"golang.org/x/text/message\"\n")
+	}
+	b.WriteString("\t\"golang.org/x/text/message/catalog\"\n")
+	b.WriteString(")\n\n")
+	if x.Var != "" {
+		fmt.Fprintf(&b, "// %s holds the translations of the project\n", x.Var)
+		fmt.Fprintf(&b, "var %s catalog.Catalog\n\n", x.Var)
+	}
+	fmt.Fprintf(&b, "func init() {\n%s}\n", body.Bytes())
+	src, err := format.Source(b.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("codegen: %w", err)
+	}
+	return src, nil
+}
+
+// writeMessage writes the statement adding a translation to the builder, if
+// it is not empty, and reports whether it is a plural message
+func writeMessage(b *bytes.Buffer, lang string, t poeditor.TermTranslated) bool {
+	key := strconv.Quote(MessageKey(t.TermBase))
+	switch c := t.Translation.Content.(type) {
+	case string:
+		if c != "" {
+			fmt.Fprintf(b, "b.SetString(%s, %s, %s)\n", lang, key, strconv.Quote(c))
+		}
+	case poeditor.Plural:
+		if c == (poeditor.Plural{}) {
+			return false
+		}
+		arg, verb := 1, "%d"
+		for _, p := range placeholder.Parse(c.Other) {
+			if t := placeholder.GoType(p.Verb); p.Kind == placeholder.Printf && (t == "int" || t == "float64") {
+				arg, verb = p.Index, p.Text
+				if i := strings.IndexByte(verb, ']'); i >= 0 {
+					// Selectf formats the argument on its own
+					verb = "%" + verb[i+1:]
+				}
+				break
+			}
+		}
+		fmt.Fprintf(b, "b.Set(%s, %s, plural.Selectf(%d, %q,\n", lang, key, arg, verb)
+		for _, form := range c.Forms() {
+			fmt.Fprintf(b, "%q, %s,\n", form.Category, strconv.Quote(form.Text))
+		}
+		b.WriteString("))\n")
+		return true
+	}
+	return false
+}
+
+// tag turns language codes such as pt_BR into BCP 47 tags
+func tag(lang string) string {
+	return strings.Replace(lang, "_", "-", -1)
+}
+
+// langVar returns the name of the variable holding the tag of a language
+func langVar(lang string) string {
+	var v strings.Builder
+	v.WriteString("lang")
+	for _, w := range nameWords(lang) {
+		v.WriteString(w)
+	}
+	return v.String()
+}
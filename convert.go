@@ -0,0 +1,333 @@
+package poeditor
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// ErrFormatUnsupported is returned when there is no local codec for a file
+// format
+var ErrFormatUnsupported = errors.New("unsupported file format")
+
+// The kinds of data which a file format may be unable to represent
+const (
+	// LossPlural means only the other form of a plural is kept
+	LossPlural = "plural"
+	// LossContext means the term context is dropped
+	LossContext = "context"
+	// LossComment means the term comment is dropped
+	LossComment = "comment"
+	// LossTags means the term tags are dropped
+	LossTags = "tags"
+	// LossReference means the term reference is dropped
+	LossReference = "reference"
+	// LossFuzzy means the fuzzy and proofread states are dropped
+	LossFuzzy = "fuzzy"
+	// LossExactValue means an ICU plural message with exact value selectors
+	// such as =0, or an offset, is kept as text in a format which does not
+	// interpret ICU messages
+	LossExactValue = "exact value"
+	// LossPluralForm means plural forms which the language of the target
+	// file does not use are dropped
+	LossPluralForm = "plural form"
+)
+
+// ConvertLoss is data of a single term which cannot be represented in the
+// target format of a conversion
+type ConvertLoss struct {
+	TermBase
+	Loss string
+}
+
+func (l ConvertLoss) String() string {
+	if l.Context != "" {
+		return fmt.Sprintf("%s (%s): %s lost", l.Term, l.Context, l.Loss)
+	}
+	return fmt.Sprintf("%s: %s lost", l.Term, l.Loss)
+}
+
+// ConvertReport is returned from Convert
+type ConvertReport struct {
+	Terms  int
+	Losses []ConvertLoss
+}
+
+type codec struct {
+	read  func(io.Reader) ([]TermTranslated, error)
+	write func(io.Writer, []TermTranslated) error
+	// The data the format can represent
+	plurals, contexts, comments, tags, references, fuzzy bool
+	// icu is set for formats storing plurals as ICU messages
+	icu bool
+	// readLanguage and writeLanguage are set for formats which store the
+	// language of a file. readLanguage returns the language of the file, or
+	// the given one if the file has none.
+	readLanguage  func(r io.Reader, language string) ([]TermTranslated, string, error)
+	writeLanguage func(w io.Writer, language string, terms []TermTranslated) error
+	// categories returns the plural categories the format can write for a
+	// language, or nil if it can write all of them
+	categories func(language string) []string
+}
+
+var codecs = map[string]codec{
+	FileFormatPO: {
+		read: ReadPO, write: WritePO,
+		plurals: true, contexts: true, comments: true, references: true, fuzzy: true,
+		readLanguage: readPO, writeLanguage: WritePOLanguage, categories: poWriteCategories,
+	},
+	FileFormatKeyValueJSON: {
+		read: ReadKeyValueJSON, write: WriteKeyValueJSON,
+		plurals: true, contexts: true,
+	},
+	FileFormatJSON: {
+		read: ReadJSON, write: WriteJSON,
+		plurals: true, contexts: true, comments: true, tags: true, references: true, fuzzy: true,
+	},
+	FileFormatAndroidStrings: {
+		read: ReadAndroidStrings, write: WriteAndroidStrings,
+		plurals: true, comments: true,
+	},
+	FileFormatAppleStrings: {
+		read: ReadAppleStrings, write: WriteAppleStrings,
+		comments: true,
+	},
+	FileFormatXLIFF: {
+		read: ReadXLIFF, write: WriteXLIFF,
+		contexts: true, comments: true, fuzzy: true,
+	},
+	FileFormatARB: {
+		read: readARBTerms, write: writeARBTerms,
+		plurals: true, contexts: true, comments: true, icu: true,
+		readLanguage: readARBLanguage, writeLanguage: writeARBLanguage,
+	},
+	FileFormatProperties: {
+		read: ReadProperties, write: WriteProperties,
+		plurals: true, comments: true, icu: true,
+	},
+	FileFormatRESX: {
+		read: ReadRESX, write: WriteRESX,
+		plurals: true, comments: true, icu: true,
+	},
+	FileFormatRESW: {
+		read: ReadRESX, write: WriteRESX,
+		plurals: true, comments: true, icu: true,
+	},
+	FileFormatCSV: {
+		read: readCSVTerms, write: writeCSVTerms,
+		plurals: true, contexts: true, comments: true, tags: true, references: true, icu: true,
+	},
+}
+
+var formatExtensions = map[string]string{
+	".po":         FileFormatPO,
+	".pot":        FileFormatPO,
+	".json":       FileFormatKeyValueJSON,
+	".xml":        FileFormatAndroidStrings,
+	".strings":    FileFormatAppleStrings,
+	".xliff":      FileFormatXLIFF,
+	".xlf":        FileFormatXLIFF,
+	".arb":        FileFormatARB,
+	".properties": FileFormatProperties,
+	".resx":       FileFormatRESX,
+	".resw":       FileFormatRESW,
+	".csv":        FileFormatCSV,
+}
+
+// Formats returns the file formats which can be decoded and encoded locally
+func Formats() []string {
+	fs := make([]string, 0, len(codecs))
+	for f := range codecs {
+		fs = append(fs, f)
+	}
+	sort.Strings(fs)
+	return fs
+}
+
+// FormatFromFilename returns the file format matching the extension of a file
+// name, or an empty string if the extension is unknown. JSON files are assumed
+// to be key value JSON.
+func FormatFromFilename(name string) string {
+	return formatExtensions[strings.ToLower(filepath.Ext(name))]
+}
+
+// Decode reads terms and translations in the given file format. For
+// available file formats, see Formats.
+func Decode(r io.Reader, format string) ([]TermTranslated, error) {
+	terms, _, err := decode(r, format, "")
+	return terms, err
+}
+
+// decode reads terms in the given file format, and returns the language of
+// the file or the given language if the file has none
+func decode(r io.Reader, format, language string) ([]TermTranslated, string, error) {
+	c, ok := codecs[format]
+	if !ok {
+		return nil, "", ErrFormatUnsupported
+	}
+	if c.readLanguage != nil {
+		return c.readLanguage(r, language)
+	}
+	terms, err := c.read(r)
+	return terms, language, err
+}
+
+// Encode writes terms and translations in the given file format. For
+// available file formats, see Formats.
+func Encode(w io.Writer, format string, terms []TermTranslated) error {
+	c, ok := codecs[format]
+	if !ok {
+		return ErrFormatUnsupported
+	}
+	return c.write(w, terms)
+}
+
+// Convert converts a file from one format to another. The report lists the
+// data which could not be represented in the target format.
+func Convert(src io.Reader, srcFormat string, dst io.Writer, dstFormat string) (ConvertReport, error) {
+	return ConvertLanguage(src, srcFormat, dst, dstFormat, "")
+}
+
+// ConvertLanguage converts a file of a language from one format to another
+// like Convert. The language of the source file, such as the Language header
+// of a .po file or the @@locale of an .arb file, takes precedence over the
+// given language. Plural forms which the language does not use in the target
+// format are dropped and reported as lost.
+func ConvertLanguage(src io.Reader, srcFormat string, dst io.Writer, dstFormat, language string) (ConvertReport, error) {
+	var report ConvertReport
+	terms, language, err := decode(src, srcFormat, language)
+	if err != nil {
+		return report, err
+	}
+	c, ok := codecs[dstFormat]
+	if !ok {
+		return report, ErrFormatUnsupported
+	}
+	report.Terms = len(terms)
+	report.Losses = c.losses(terms)
+	if c.categories != nil {
+		var lost []ConvertLoss
+		terms, lost = dropPluralForms(terms, c.categories(language))
+		report.Losses = append(report.Losses, lost...)
+	}
+	if c.writeLanguage != nil {
+		return report, c.writeLanguage(dst, language, terms)
+	}
+	return report, c.write(dst, terms)
+}
+
+// dropPluralForms returns a copy of the terms without the plural forms of
+// other categories than the given ones, and the terms which lost forms
+func dropPluralForms(terms []TermTranslated, categories []string) ([]TermTranslated, []ConvertLoss) {
+	var losses []ConvertLoss
+	kept := make([]TermTranslated, len(terms))
+	for i, t := range terms {
+		kept[i] = t
+		p, ok := t.Translation.Content.(Plural)
+		if !ok {
+			continue
+		}
+		fields := pluralFields(&p)
+		lost := false
+		for _, f := range p.Forms() {
+			if !containsString(categories, f.Category) {
+				*fields[f.Category] = ""
+				lost = true
+			}
+		}
+		if lost {
+			kept[i].Translation.Content = p
+			losses = append(losses, ConvertLoss{TermBase: t.TermBase, Loss: LossPluralForm})
+		}
+	}
+	return kept, losses
+}
+
+func (c codec) losses(terms []TermTranslated) []ConvertLoss {
+	var losses []ConvertLoss
+	for _, t := range terms {
+		add := func(lost bool, loss string) {
+			if lost {
+				losses = append(losses, ConvertLoss{TermBase: t.TermBase, Loss: loss})
+			}
+		}
+		_, plural := t.Translation.Content.(Plural)
+		add(plural && !c.plurals, LossPlural)
+		s, _ := t.Translation.Content.(string)
+		add(!c.icu && exactICUPlural(s), LossExactValue)
+		add(t.Context != "" && !c.contexts, LossContext)
+		add(t.Comment != "" && !c.comments, LossComment)
+		add(len(t.Tags) > 0 && !c.tags, LossTags)
+		add(t.Reference != "" && !c.references, LossReference)
+		add((t.Translation.Fuzzy == 1 || t.Translation.Proofread == 1) && !c.fuzzy, LossFuzzy)
+	}
+	return losses
+}
+
+func readARBTerms(r io.Reader) ([]TermTranslated, error) {
+	terms, _, err := readARBLanguage(r, "")
+	return terms, err
+}
+
+func writeARBTerms(w io.Writer, terms []TermTranslated) error {
+	return writeARBLanguage(w, "", terms)
+}
+
+// readARBLanguage reads the terms of an .arb file, and returns its @@locale
+// or the given language
+func readARBLanguage(r io.Reader, language string) ([]TermTranslated, string, error) {
+	f, err := ReadARB(r)
+	if err != nil {
+		return nil, "", err
+	}
+	terms := make([]TermTranslated, len(f.Messages))
+	for i, m := range f.Messages {
+		terms[i] = m.TermTranslated
+	}
+	if f.Locale != "" {
+		language = f.Locale
+	}
+	return terms, language, nil
+}
+
+func writeARBLanguage(w io.Writer, language string, terms []TermTranslated) error {
+	// The locale may come from a file using underscores, like pt_BR
+	f := NewARBFile(strings.Replace(language, "_", "-", -1), terms)
+	return WriteARB(w, f)
+}
+
+// readCSVTerms reads a spreadsheet with a single translation column
+func readCSVTerms(r io.Reader) ([]TermTranslated, error) {
+	s, err := ReadSheetCSV(r)
+	if err != nil {
+		return nil, err
+	}
+	terms := make([]TermTranslated, len(s.Rows))
+	for i, row := range s.Rows {
+		var value string
+		if len(s.Languages) > 0 {
+			value = row.Translations[s.Languages[0]]
+		}
+		terms[i] = flatTerm(row.Term.Term, value)
+		if row.Plural == "" {
+			terms[i].Translation.Content = value
+		}
+		terms[i].Term = row.Term
+	}
+	return terms, nil
+}
+
+func writeCSVTerms(w io.Writer, terms []TermTranslated) error {
+	s := Sheet{Languages: []string{"translation"}, Rows: make([]SheetRow, len(terms))}
+	for i, t := range terms {
+		value, err := flatContent(t.Translation.Content)
+		if err != nil {
+			return err
+		}
+		s.Rows[i] = SheetRow{Term: t.Term, Translations: map[string]string{"translation": value}}
+	}
+	return WriteSheetCSV(w, s)
+}
@@ -0,0 +1,184 @@
+package poeditor_test
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+
+	"github.com/blacksails/poeditor"
+)
+
+func TestConvertRoundTrip(t *testing.T) {
+	terms := []poeditor.TermTranslated{
+		newTermTranslated("greeting", "Hello, \"world\"\n<b>bold</b> & more", "Shown on the start page"),
+		newTermTranslated("escapes", " leading space and \\ backslash: é😀", ""),
+		newTermTranslated("items", poeditor.Plural{One: "%d item", Other: "%d items"}, ""),
+	}
+	var src bytes.Buffer
+	if err := poeditor.Encode(&src, poeditor.FileFormatJSON, terms); err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	for _, format := range poeditor.Formats() {
+		var buf bytes.Buffer
+		report, err := poeditor.Convert(bytes.NewReader(src.Bytes()), poeditor.FileFormatJSON, &buf, format)
+		if err != nil {
+			t.Errorf("%s: Unexpected error: %s", format, err)
+			continue
+		}
+		encoded := buf.String()
+		got, err := poeditor.Decode(&buf, format)
+		if err != nil {
+			t.Errorf("%s: Unexpected error: %s\n%s", format, err, encoded)
+			continue
+		}
+		if len(got) != len(terms) {
+			t.Errorf("%s: Expected %d terms, got %d\n%s", format, len(terms), len(got), encoded)
+			continue
+		}
+		lost := make(map[string]bool)
+		for _, l := range report.Losses {
+			lost[l.Term+":"+l.Loss] = true
+		}
+		for i, term := range terms {
+			if got[i].Term.Term != term.Term.Term {
+				t.Errorf("%s: Expected term %q, got %q", format, term.Term.Term, got[i].Term.Term)
+			}
+			expected := term.Translation.Content
+			if p, ok := expected.(poeditor.Plural); ok && lost[term.Term.Term+":"+poeditor.LossPlural] {
+				expected = p.Other
+			}
+			if !reflect.DeepEqual(got[i].Translation.Content, expected) {
+				t.Errorf("%s: \nExpected %#v \nGot      %#v\n%s", format, expected, got[i].Translation.Content, encoded)
+			}
+			if term.Comment != "" && !lost[term.Term.Term+":"+poeditor.LossComment] && got[i].Comment != term.Comment {
+				t.Errorf("%s: Expected comment %q, got %q", format, term.Comment, got[i].Comment)
+			}
+		}
+	}
+}
+
+func TestConvertReportsLosses(t *testing.T) {
+	term := newTermTranslated("items", poeditor.Plural{One: "%d item", Other: "%d items"}, "A comment")
+	term.Context = "cart"
+	var src bytes.Buffer
+	if err := poeditor.Encode(&src, poeditor.FileFormatPO, []poeditor.TermTranslated{term}); err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	report, err := poeditor.Convert(&src, poeditor.FileFormatPO, &bytes.Buffer{}, poeditor.FileFormatAppleStrings)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	expected := []poeditor.ConvertLoss{
+		{TermBase: term.TermBase, Loss: poeditor.LossPlural},
+		{TermBase: term.TermBase, Loss: poeditor.LossContext},
+	}
+	if !reflect.DeepEqual(report.Losses, expected) {
+		t.Errorf("\nExpected %+v \nGot      %+v", expected, report.Losses)
+	}
+}
+
+func TestConvertReportsExactValues(t *testing.T) {
+	term := newTermTranslated("cart", "{count, plural, =0{Empty} =1{One item} other{# items}}", "")
+	tests := []struct {
+		format string
+		losses []poeditor.ConvertLoss
+	}{
+		{poeditor.FileFormatPO, []poeditor.ConvertLoss{{TermBase: term.TermBase, Loss: poeditor.LossExactValue}}},
+		{poeditor.FileFormatProperties, nil},
+	}
+	for _, test := range tests {
+		var src bytes.Buffer
+		if err := poeditor.Encode(&src, poeditor.FileFormatARB, []poeditor.TermTranslated{term}); err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		var dst bytes.Buffer
+		report, err := poeditor.Convert(&src, poeditor.FileFormatARB, &dst, test.format)
+		if err != nil {
+			t.Fatalf("%s: Unexpected error: %s", test.format, err)
+		}
+		if !reflect.DeepEqual(report.Losses, test.losses) {
+			t.Errorf("%s: \nExpected %+v \nGot      %+v", test.format, test.losses, report.Losses)
+		}
+		got, err := poeditor.Decode(&dst, test.format)
+		if err != nil {
+			t.Fatalf("%s: Unexpected error: %s", test.format, err)
+		}
+		if len(got) != 1 || got[0].Translation.Content != term.Translation.Content {
+			t.Errorf("%s: Expected the message to be kept as text, got %+v", test.format, got)
+		}
+	}
+}
+
+func TestConvertLanguage(t *testing.T) {
+	files := newTermTranslated("files", poeditor.Plural{One: "%d файл", Few: "%d файла", Many: "%d файлов", Other: "%d файла"}, "")
+	arb := new(bytes.Buffer)
+	if err := poeditor.WriteARB(arb, poeditor.NewARBFile("ru", []poeditor.TermTranslated{files})); err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	unlocalized := new(bytes.Buffer)
+	if err := poeditor.WriteARB(unlocalized, poeditor.NewARBFile("", []poeditor.TermTranslated{files})); err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	russian := poeditor.Plural{One: "%d файл", Few: "%d файла", Many: "%d файлов"}
+	tests := []struct {
+		name     string
+		src      []byte
+		language string
+		expected poeditor.Plural
+		losses   []poeditor.ConvertLoss
+	}{
+		{
+			// Russian does not use the other form for whole numbers
+			name: "arb locale", src: arb.Bytes(), expected: russian,
+			losses: []poeditor.ConvertLoss{{TermBase: files.TermBase, Loss: poeditor.LossPluralForm}},
+		},
+		{
+			name: "language option", src: unlocalized.Bytes(), language: "ru", expected: russian,
+			losses: []poeditor.ConvertLoss{{TermBase: files.TermBase, Loss: poeditor.LossPluralForm}},
+		},
+		{
+			name: "without language", src: unlocalized.Bytes(),
+			expected: poeditor.Plural{One: "%d файл", Other: "%d файла"},
+			losses:   []poeditor.ConvertLoss{{TermBase: files.TermBase, Loss: poeditor.LossPluralForm}},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var po bytes.Buffer
+			report, err := poeditor.ConvertLanguage(bytes.NewReader(test.src), poeditor.FileFormatARB, &po, poeditor.FileFormatPO, test.language)
+			if err != nil {
+				t.Fatalf("Unexpected error: %s", err)
+			}
+			if !reflect.DeepEqual(report.Losses, test.losses) {
+				t.Errorf("\nExpected %+v \nGot      %+v", test.losses, report.Losses)
+			}
+			// Converting the .po file again keeps its language and forms
+			var dst bytes.Buffer
+			report, err = poeditor.Convert(bytes.NewReader(po.Bytes()), poeditor.FileFormatPO, &dst, poeditor.FileFormatPO)
+			if err != nil {
+				t.Fatalf("Unexpected error: %s\n%s", err, po.String())
+			}
+			if len(report.Losses) != 0 {
+				t.Errorf("Expected no losses, got %+v", report.Losses)
+			}
+			got, err := poeditor.ReadPO(&dst)
+			if err != nil {
+				t.Fatalf("Unexpected error: %s", err)
+			}
+			if len(got) != 1 || !reflect.DeepEqual(got[0].Translation.Content, test.expected) {
+				t.Errorf("\nExpected %+v \nGot      %+v\n%s", test.expected, got, po.String())
+			}
+		})
+	}
+}
+
+func newTermTranslated(term string, content interface{}, comment string) poeditor.TermTranslated {
+	var t poeditor.TermTranslated
+	t.Term.Term = term
+	t.Comment = comment
+	t.Translation.Content = content
+	if _, ok := content.(poeditor.Plural); ok {
+		t.Plural = term
+	}
+	return t
+}
@@ -0,0 +1,67 @@
+package poeditor
+
+import "sort"
+
+// TermDiff is the difference between two lists of terms. Terms are matched
+// by their term and context.
+type TermDiff struct {
+	// Added holds the terms only found in the new list
+	Added []Term
+	// Removed holds the terms only found in the old list
+	Removed []Term
+	// Changed holds the terms of the new list whose plural, reference,
+	// comment or tags differ from the old list
+	Changed []Term
+}
+
+// Empty reports whether the lists are equal
+func (d TermDiff) Empty() bool {
+	return len(d.Added) == 0 && len(d.Removed) == 0 && len(d.Changed) == 0
+}
+
+// DiffTerms compares two lists of terms, such as the terms of a project and
+// the terms of a local file. The terms of the diff keep the order of the
+// lists.
+func DiffTerms(old, new []Term) TermDiff {
+	var d TermDiff
+	olds := make(map[TermBase]Term, len(old))
+	for _, t := range old {
+		olds[t.TermBase] = t
+	}
+	news := make(map[TermBase]bool, len(new))
+	for _, t := range new {
+		news[t.TermBase] = true
+		o, ok := olds[t.TermBase]
+		switch {
+		case !ok:
+			d.Added = append(d.Added, t)
+		case !sameTerm(o, t):
+			d.Changed = append(d.Changed, t)
+		}
+	}
+	for _, t := range old {
+		if !news[t.TermBase] {
+			d.Removed = append(d.Removed, t)
+		}
+	}
+	return d
+}
+
+// sameTerm compares the fields of terms which can be updated. The order of
+// tags does not matter.
+func sameTerm(a, b Term) bool {
+	if a.Plural != b.Plural || a.Reference != b.Reference || a.Comment != b.Comment ||
+		len(a.Tags) != len(b.Tags) {
+		return false
+	}
+	at := append([]string(nil), a.Tags...)
+	bt := append([]string(nil), b.Tags...)
+	sort.Strings(at)
+	sort.Strings(bt)
+	for i := range at {
+		if at[i] != bt[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,41 @@
+package poeditor_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/blacksails/poeditor"
+)
+
+func TestDiffTerms(t *testing.T) {
+	term := func(term, context, comment string, tags ...string) poeditor.Term {
+		t := poeditor.Term{Comment: comment, Tags: tags}
+		t.Term = term
+		t.Context = context
+		return t
+	}
+	old := []poeditor.Term{
+		term("save", "", ""),
+		term("open", "menu", "", "a", "b"),
+		term("close", "", ""),
+		term("quit", "", "old"),
+	}
+	new := []poeditor.Term{
+		term("open", "menu", "", "b", "a"),
+		term("open", "", ""),
+		term("save", "", ""),
+		term("quit", "", "new"),
+	}
+	want := poeditor.TermDiff{
+		Added:   []poeditor.Term{term("open", "", "")},
+		Removed: []poeditor.Term{term("close", "", "")},
+		Changed: []poeditor.Term{term("quit", "", "new")},
+	}
+	got := poeditor.DiffTerms(old, new)
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("\nExpected %+v\nGot      %+v", want, got)
+	}
+	if got.Empty() || !poeditor.DiffTerms(old, old).Empty() {
+		t.Errorf("Unexpected Empty result")
+	}
+}
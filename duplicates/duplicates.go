@@ -0,0 +1,294 @@
+// Package duplicates finds terms with the same source text under different
+// keys, shows whether their translations diverge, and consolidates them
+// into one term:
+//
+//	groups, err := duplicates.Analyze(p, "en")
+//	for _, g := range groups {
+//		fmt.Println(g.Kind, g.Source, len(g.Terms), g.Divergent)
+//	}
+//	merge, err := duplicates.Consolidate(p, groups[0], groups[0].Terms[0].TermBase)
+package duplicates
+
+import (
+	"errors"
+	"sort"
+	"strings"
+
+	"github.com/blacksails/poeditor"
+)
+
+// Kinds of groups
+const (
+	// Exact groups have identical source texts
+	Exact = "exact"
+	// Normalized groups have source texts which only differ in case,
+	// whitespace and trailing punctuation
+	Normalized = "normalized"
+)
+
+// ErrNotInGroup is returned from Group.Merge when the term to keep is not
+// part of the group
+var ErrNotInGroup = errors.New("duplicates: the kept term is not part of the group")
+
+// Member is a term of a group
+type Member struct {
+	poeditor.Term
+	// Source is the text of the term in the source language
+	Source interface{} `json:"source"`
+	// Translations are the translations of the term by language code.
+	// Untranslated languages are left out.
+	Translations map[string]interface{} `json:"translations"`
+}
+
+// Group is a set of terms with the same source text
+type Group struct {
+	Kind string `json:"kind"`
+	// Source is the normalized source text of the terms
+	Source string   `json:"source"`
+	Terms  []Member `json:"terms"`
+	// Divergent are the languages in which the terms have different
+	// translations
+	Divergent []string `json:"divergent,omitempty"`
+}
+
+// Normalize returns s in lower case, with whitespace collapsed and trailing
+// punctuation removed
+func Normalize(s string) string {
+	s = strings.ToLower(strings.Join(strings.Fields(s), " "))
+	return strings.TrimRight(s, ".:;,!?… ")
+}
+
+// text returns a translation content as a string, with plural forms joined
+func text(c interface{}) string {
+	switch c := c.(type) {
+	case string:
+		return c
+	case poeditor.Plural:
+		forms := make([]string, 0, 6)
+		for _, f := range c.Forms() {
+			forms = append(forms, f.Category+"\x01"+f.Text)
+		}
+		return strings.Join(forms, "\x00")
+	}
+	return ""
+}
+
+// Find groups the source terms by their normalized text, which is the term
+// itself for untranslated terms, and returns the groups of more than one
+// term sorted by source. Translations holds the terms of other languages by
+// language code.
+func Find(source []poeditor.TermTranslated, translations map[string][]poeditor.TermTranslated) []Group {
+	byTerm := make(map[poeditor.TermBase]map[string]interface{})
+	for code, terms := range translations {
+		for _, t := range terms {
+			if poeditor.EmptyContent(t.Translation.Content) {
+				continue
+			}
+			if byTerm[t.TermBase] == nil {
+				byTerm[t.TermBase] = make(map[string]interface{})
+			}
+			byTerm[t.TermBase][code] = t.Translation.Content
+		}
+	}
+	var (
+		keys   []string
+		groups = make(map[string]*Group)
+	)
+	for _, t := range source {
+		src := t.Translation.Content
+		if poeditor.EmptyContent(src) {
+			src = t.Term.Term
+			if t.Plural != "" {
+				src = poeditor.Plural{One: t.Term.Term, Other: t.Plural}
+			}
+		}
+		key := Normalize(text(src))
+		if key == "" {
+			continue
+		}
+		g, ok := groups[key]
+		if !ok {
+			g = &Group{Kind: Exact, Source: key}
+			groups[key] = g
+			keys = append(keys, key)
+		}
+		g.Terms = append(g.Terms, Member{Term: t.Term, Source: src, Translations: byTerm[t.TermBase]})
+	}
+	sort.Strings(keys)
+	var result []Group
+	for _, key := range keys {
+		g := groups[key]
+		if len(g.Terms) < 2 {
+			continue
+		}
+		for _, m := range g.Terms[1:] {
+			if text(m.Source) != text(g.Terms[0].Source) {
+				g.Kind = Normalized
+			}
+		}
+		g.Divergent = divergent(g.Terms)
+		result = append(result, *g)
+	}
+	return result
+}
+
+// divergent returns the sorted languages in which the members have
+// different translations
+func divergent(members []Member) []string {
+	seen := make(map[string]map[string]bool)
+	for _, m := range members {
+		for code, c := range m.Translations {
+			if seen[code] == nil {
+				seen[code] = make(map[string]bool)
+			}
+			seen[code][text(c)] = true
+		}
+	}
+	var codes []string
+	for code, texts := range seen {
+		if len(texts) > 1 {
+			codes = append(codes, code)
+		}
+	}
+	sort.Strings(codes)
+	return codes
+}
+
+// Analyze lists the terms of the source language of the project and the
+// translations of its other languages, and returns the groups of Find
+func Analyze(p *poeditor.Project, source string) ([]Group, error) {
+	src := poeditor.Language{Project: p, Code: source}
+	sourceTerms, err := src.ListTerms()
+	if err != nil {
+		return nil, err
+	}
+	languages, err := p.ListLanguages()
+	if err != nil {
+		return nil, err
+	}
+	translations := make(map[string][]poeditor.TermTranslated)
+	for _, l := range languages {
+		if l.Code == source {
+			continue
+		}
+		l.Project = p
+		if translations[l.Code], err = l.ListTerms(); err != nil {
+			return nil, err
+		}
+	}
+	return Find(sourceTerms, translations), nil
+}
+
+// Merge is the consolidation of a group into one of its terms
+type Merge struct {
+	// Update is the kept term with the tags and references of the other
+	// terms added
+	Update poeditor.TermUpdate
+	// Translations fill the languages in which the kept term is
+	// untranslated with the translation of another term, by language code
+	Translations map[string]poeditor.TermTranslation
+	// Delete are the other terms of the group
+	Delete []poeditor.TermBase
+}
+
+// Merge plans the consolidation of the group into the term to keep. Where
+// the other terms diverge, the translation of the first one is used.
+func (g Group) Merge(keep poeditor.TermBase) (Merge, error) {
+	var (
+		m    Merge
+		kept *Member
+	)
+	for i := range g.Terms {
+		if g.Terms[i].TermBase == keep {
+			kept = &g.Terms[i]
+		}
+	}
+	if kept == nil {
+		return m, ErrNotInGroup
+	}
+	m.Update = poeditor.TermUpdate{Term: kept.Term}
+	m.Update.Tags = append([]string(nil), kept.Tags...)
+	m.Translations = make(map[string]poeditor.TermTranslation)
+	references := splitReferences(kept.Reference)
+	for _, t := range g.Terms {
+		if t.TermBase == keep {
+			continue
+		}
+		m.Delete = append(m.Delete, t.TermBase)
+		for _, tag := range t.Tags {
+			if !contains(m.Update.Tags, tag) {
+				m.Update.Tags = append(m.Update.Tags, tag)
+			}
+		}
+		for _, r := range splitReferences(t.Reference) {
+			if !contains(references, r) {
+				references = append(references, r)
+			}
+		}
+		for code, c := range t.Translations {
+			if _, ok := kept.Translations[code]; ok {
+				continue
+			}
+			if _, ok := m.Translations[code]; ok {
+				continue
+			}
+			m.Translations[code] = poeditor.TermTranslation{
+				TermBase:    keep,
+				Translation: poeditor.Translation{Content: c},
+			}
+		}
+	}
+	m.Update.Reference = strings.Join(references, "\n")
+	return m, nil
+}
+
+func splitReferences(s string) []string {
+	var refs []string
+	for _, r := range strings.Split(s, "\n") {
+		if r = strings.TrimSpace(r); r != "" {
+			refs = append(refs, r)
+		}
+	}
+	return refs
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+// Consolidate merges the group into the term to keep: the kept term is
+// updated with Project.UpdateTerms, its untranslated languages are filled
+// with Language.Update and the other terms are deleted with
+// Project.DeleteTerms. Code using the keys of the deleted terms has to be
+// changed to the kept key.
+func Consolidate(p *poeditor.Project, g Group, keep poeditor.TermBase) (Merge, error) {
+	m, err := g.Merge(keep)
+	if err != nil {
+		return m, err
+	}
+	if _, err := p.UpdateTerms([]poeditor.TermUpdate{m.Update}, false); err != nil {
+		return m, err
+	}
+	codes := make([]string, 0, len(m.Translations))
+	for code := range m.Translations {
+		codes = append(codes, code)
+	}
+	sort.Strings(codes)
+	for _, code := range codes {
+		l := poeditor.Language{Project: p, Code: code}
+		if _, err := l.Update([]poeditor.TermTranslation{m.Translations[code]}); err != nil {
+			return m, err
+		}
+	}
+	if len(m.Delete) > 0 {
+		if _, err := p.DeleteTerms(m.Delete); err != nil {
+			return m, err
+		}
+	}
+	return m, nil
+}
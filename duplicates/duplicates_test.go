@@ -0,0 +1,97 @@
+package duplicates_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/blacksails/poeditor"
+	"github.com/blacksails/poeditor/duplicates"
+	"github.com/blacksails/poeditor/internal/termtest"
+)
+
+func TestNormalize(t *testing.T) {
+	normalizeTests := map[string]string{
+		"Save":              "save",
+		"  Save\tchanges. ": "save changes",
+		"Loading…":          "loading",
+		"Are you sure?!":    "are you sure",
+	}
+	for input, expected := range normalizeTests {
+		if s := duplicates.Normalize(input); s != expected {
+			t.Errorf("%q: Expected %q, got %q", input, expected, s)
+		}
+	}
+}
+
+func findGroups() []duplicates.Group {
+	source := []poeditor.TermTranslated{
+		termtest.Translated("dialog.save", "Save"),
+		termtest.Translated("toolbar.save", "Save"),
+		termtest.Translated("menu.save", "save."),
+		termtest.Translated("cancel", "Cancel"),
+		termtest.Translated("Cancel", ""),
+		termtest.Translated("quit", "Quit"),
+	}
+	source[0].Tags = []string{"dialog"}
+	source[1].Tags = []string{"toolbar"}
+	source[1].Reference = "toolbar.go:10"
+	translations := map[string][]poeditor.TermTranslated{
+		"de": {
+			termtest.Translated("dialog.save", "Speichern"),
+			termtest.Translated("toolbar.save", "Sichern"),
+			termtest.Translated("menu.save", ""),
+			termtest.Translated("cancel", "Abbrechen"),
+			termtest.Translated("Cancel", "Abbrechen"),
+		},
+		"fr": {
+			termtest.Translated("dialog.save", ""),
+			termtest.Translated("toolbar.save", "Enregistrer"),
+		},
+	}
+	return duplicates.Find(source, translations)
+}
+
+func TestFind(t *testing.T) {
+	groups := findGroups()
+	if len(groups) != 2 {
+		t.Fatalf("Expected 2 groups, got %+v", groups)
+	}
+	cancel, save := groups[0], groups[1]
+	if cancel.Kind != duplicates.Exact || cancel.Source != "cancel" || len(cancel.Terms) != 2 || cancel.Divergent != nil {
+		t.Errorf("Unexpected cancel group %+v", cancel)
+	}
+	if save.Kind != duplicates.Normalized || save.Source != "save" || len(save.Terms) != 3 {
+		t.Errorf("Unexpected save group %+v", save)
+	}
+	if expected := []string{"de"}; !reflect.DeepEqual(save.Divergent, expected) {
+		t.Errorf("Expected divergent languages %q, got %q", expected, save.Divergent)
+	}
+}
+
+func TestMerge(t *testing.T) {
+	save := findGroups()[1]
+	keep := poeditor.TermBase{Term: "dialog.save"}
+	m, err := save.Merge(keep)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if expected := []string{"dialog", "toolbar"}; !reflect.DeepEqual(m.Update.Tags, expected) {
+		t.Errorf("Expected tags %q, got %q", expected, m.Update.Tags)
+	}
+	if m.Update.Reference != "toolbar.go:10" || m.Update.Term.Term != "dialog.save" {
+		t.Errorf("Unexpected update %+v", m.Update)
+	}
+	expected := map[string]poeditor.TermTranslation{
+		"fr": {TermBase: keep, Translation: poeditor.Translation{Content: "Enregistrer"}},
+	}
+	if !reflect.DeepEqual(m.Translations, expected) {
+		t.Errorf("Expected translations %+v, got %+v", expected, m.Translations)
+	}
+	deleted := []poeditor.TermBase{{Term: "toolbar.save"}, {Term: "menu.save"}}
+	if !reflect.DeepEqual(m.Delete, deleted) {
+		t.Errorf("Expected %+v to be deleted, got %+v", deleted, m.Delete)
+	}
+	if _, err := save.Merge(poeditor.TermBase{Term: "quit"}); err != duplicates.ErrNotInGroup {
+		t.Errorf("Expected ErrNotInGroup, got %v", err)
+	}
+}
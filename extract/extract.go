@@ -0,0 +1,395 @@
+// Package extract finds translatable strings in Go source code.
+//
+// Calls to the configured translation functions are turned into terms, with
+// the reference set to the position of the call:
+//
+//	// TRANSLATORS: shown on the start page
+//	greeting := T("Hello")
+//	items := Tn("cart", "%d item", "%d items", n)
+//
+// The terms can be sent to POEditor with Project.Sync or written to a PO
+// template with poeditor.WritePO.
+package extract
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/blacksails/poeditor"
+)
+
+// Func describes a translation function. Argument positions are counted from
+// 1, and zero means the function has no such argument.
+type Func struct {
+	// Name is the name of the function or method, e.g. T. A qualified name
+	// such as i18n.T only matches calls through that package or variable.
+	Name string
+	// Term is the position of the term argument
+	Term int
+	// Plural is the position of the plural form argument
+	Plural int
+	// Context is the position of the context argument
+	Context int
+}
+
+// String formats the function the way ParseFuncs reads it
+func (fn Func) String() string {
+	n := fn.Term
+	if fn.Plural > n {
+		n = fn.Plural
+	}
+	if fn.Context > n {
+		n = fn.Context
+	}
+	args := make([]string, n)
+	for i := range args {
+		args[i] = "_"
+	}
+	for name, pos := range map[string]int{"term": fn.Term, "plural": fn.Plural, "context": fn.Context} {
+		if pos > 0 {
+			args[pos-1] = name
+		}
+	}
+	return fmt.Sprintf("%s(%s)", fn.Name, strings.Join(args, ", "))
+}
+
+// DefaultFuncs are used when an Extractor has no functions: T(term),
+// Tn(context, one, other, n) and Tc(context, term)
+var DefaultFuncs = []Func{
+	{Name: "T", Term: 1},
+	{Name: "Tn", Context: 1, Term: 2, Plural: 3},
+	{Name: "Tc", Context: 1, Term: 2},
+}
+
+// DefaultCommentPrefix marks comments meant for translators
+const DefaultCommentPrefix = "TRANSLATORS:"
+
+// Extractor extracts terms from Go source files
+type Extractor struct {
+	// Funcs are the translation functions, DefaultFuncs by default
+	Funcs []Func
+	// CommentPrefix marks the comments preceding a call which become the
+	// term comment, DefaultCommentPrefix by default
+	CommentPrefix string
+	// Tests includes _test.go files when extracting from directories
+	Tests bool
+}
+
+// Warning is a call to a translation function which could not be extracted,
+// because an argument is not a constant string
+type Warning struct {
+	Pos     token.Position
+	Message string
+}
+
+func (w Warning) String() string {
+	return fmt.Sprintf("%s: %s", w.Pos, w.Message)
+}
+
+// Result holds the extracted terms. A term used in several places is only
+// listed once, with the references of all calls separated by spaces.
+type Result struct {
+	Terms    []poeditor.Term
+	Warnings []Warning
+}
+
+// Translated returns the terms without translations, e.g. for
+// poeditor.WritePO
+func (r Result) Translated() []poeditor.TermTranslated {
+	ts := make([]poeditor.TermTranslated, len(r.Terms))
+	for i, t := range r.Terms {
+		ts[i].Term = t
+	}
+	return ts
+}
+
+// Dir extracts the terms of the Go files in dir and its subdirectories.
+// Hidden directories, vendor and testdata are skipped. References are
+// relative to dir.
+func (e *Extractor) Dir(dir string) (Result, error) {
+	var files []string
+	err := filepath.Walk(dir, func(path string, fi os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		name := fi.Name()
+		if fi.IsDir() {
+			if path != dir && (strings.HasPrefix(name, ".") || strings.HasPrefix(name, "_") ||
+				name == "vendor" || name == "testdata") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if strings.HasSuffix(name, ".go") && (e.Tests || !strings.HasSuffix(name, "_test.go")) {
+			files = append(files, path)
+		}
+		return nil
+	})
+	if err != nil {
+		return Result{}, err
+	}
+	return e.files(dir, files)
+}
+
+// Files extracts the terms of the given Go files. References are relative to
+// the working directory.
+func (e *Extractor) Files(names ...string) (Result, error) {
+	return e.files("", names)
+}
+
+func (e *Extractor) files(base string, names []string) (Result, error) {
+	fset := token.NewFileSet()
+	var c collector
+	for _, name := range names {
+		f, err := parser.ParseFile(fset, name, nil, parser.ParseComments)
+		if err != nil {
+			return Result{}, err
+		}
+		e.file(&c, fset, f, base)
+	}
+	return c.result(), nil
+}
+
+// File extracts the terms of a parsed file, which must have been parsed with
+// parser.ParseComments. References use the file names of fset.
+func (e *Extractor) File(fset *token.FileSet, f *ast.File) Result {
+	var c collector
+	e.file(&c, fset, f, "")
+	return c.result()
+}
+
+func (e *Extractor) file(c *collector, fset *token.FileSet, f *ast.File, base string) {
+	funcs := e.Funcs
+	if funcs == nil {
+		funcs = DefaultFuncs
+	}
+	prefix := e.CommentPrefix
+	if prefix == "" {
+		prefix = DefaultCommentPrefix
+	}
+	// Translator comments are found by the line they end on
+	comments := make(map[int]string)
+	for _, g := range f.Comments {
+		text := g.Text()
+		if i := strings.Index(text, prefix); i >= 0 {
+			comments[fset.Position(g.End()).Line] = strings.TrimSpace(text[i+len(prefix):])
+		}
+	}
+	ast.Inspect(f, func(n ast.Node) bool {
+		call, ok := n.(*ast.CallExpr)
+		if !ok {
+			return true
+		}
+		fn, ok := matchFunc(funcs, call.Fun)
+		if !ok {
+			return true
+		}
+		pos := fset.Position(call.Pos())
+		var (
+			t   poeditor.Term
+			err error
+		)
+		if t.Term, err = stringArg(call, fn.Term); err == nil {
+			t.Plural, err = stringArg(call, fn.Plural)
+		}
+		if err == nil {
+			t.Context, err = stringArg(call, fn.Context)
+		}
+		if err != nil {
+			c.warnings = append(c.warnings, Warning{Pos: pos, Message: fmt.Sprintf("%s: %s", fn.Name, err)})
+			return true
+		}
+		if t.Term == "" {
+			c.warnings = append(c.warnings, Warning{Pos: pos, Message: fmt.Sprintf("%s: empty term", fn.Name)})
+			return true
+		}
+		t.Comment = comments[pos.Line-1]
+		t.Reference = fmt.Sprintf("%s:%d", reference(base, pos.Filename), pos.Line)
+		c.add(t)
+		return true
+	})
+}
+
+// matchFunc finds the translation function called by fun
+func matchFunc(funcs []Func, fun ast.Expr) (Func, bool) {
+	var name, qual string
+	switch f := fun.(type) {
+	case *ast.Ident:
+		name = f.Name
+	case *ast.SelectorExpr:
+		name = f.Sel.Name
+		if x, ok := f.X.(*ast.Ident); ok {
+			qual = x.Name
+		}
+	default:
+		return Func{}, false
+	}
+	for _, fn := range funcs {
+		q, n := "", fn.Name
+		if i := strings.LastIndex(fn.Name, "."); i >= 0 {
+			q, n = fn.Name[:i], fn.Name[i+1:]
+		}
+		if n == name && (q == "" || q == qual) {
+			return fn, true
+		}
+	}
+	return Func{}, false
+}
+
+// stringArg returns the constant string value of an argument
+func stringArg(call *ast.CallExpr, pos int) (string, error) {
+	if pos == 0 {
+		return "", nil
+	}
+	if pos > len(call.Args) {
+		return "", fmt.Errorf("missing argument %d", pos)
+	}
+	s, ok := constString(call.Args[pos-1])
+	if !ok {
+		return "", fmt.Errorf("argument %d is not a constant string", pos)
+	}
+	return s, nil
+}
+
+// constString evaluates string literals and concatenations of them
+func constString(e ast.Expr) (string, bool) {
+	switch e := e.(type) {
+	case *ast.BasicLit:
+		if e.Kind != token.STRING {
+			return "", false
+		}
+		s, err := strconv.Unquote(e.Value)
+		return s, err == nil
+	case *ast.ParenExpr:
+		return constString(e.X)
+	case *ast.BinaryExpr:
+		if e.Op != token.ADD {
+			return "", false
+		}
+		x, ok := constString(e.X)
+		if !ok {
+			return "", false
+		}
+		y, ok := constString(e.Y)
+		return x + y, ok
+	}
+	return "", false
+}
+
+// reference returns the slash separated path of a file relative to base
+func reference(base, name string) string {
+	if base != "" {
+		if rel, err := filepath.Rel(base, name); err == nil {
+			name = rel
+		}
+	}
+	return filepath.ToSlash(name)
+}
+
+// collector merges the terms found in several places
+type collector struct {
+	terms    []poeditor.Term
+	index    map[poeditor.TermBase]int
+	warnings []Warning
+}
+
+func (c *collector) add(t poeditor.Term) {
+	if c.index == nil {
+		c.index = make(map[poeditor.TermBase]int)
+	}
+	i, ok := c.index[t.TermBase]
+	if !ok {
+		c.index[t.TermBase] = len(c.terms)
+		c.terms = append(c.terms, t)
+		return
+	}
+	m := &c.terms[i]
+	m.Reference += " " + t.Reference
+	if m.Plural == "" {
+		m.Plural = t.Plural
+	}
+	if t.Comment != "" && !strings.Contains(m.Comment, t.Comment) {
+		if m.Comment != "" {
+			m.Comment += "\n"
+		}
+		m.Comment += t.Comment
+	}
+}
+
+func (c *collector) result() Result {
+	return Result{Terms: c.terms, Warnings: c.warnings}
+}
+
+var funcSpec = regexp.MustCompile(`^\s*([\pL_][\pL\pN_]*(?:\.[\pL_][\pL\pN_]*)?)\(([^()]*)\)\s*$`)
+
+// ParseFuncs parses translation functions written like calls, where the
+// arguments are named term, plural and context, and _ marks other arguments:
+//
+//	T(term), i18n.Tn(context, term, plural, _)
+func ParseFuncs(s string) ([]Func, error) {
+	var funcs []Func
+	for _, spec := range splitFuncs(s) {
+		m := funcSpec.FindStringSubmatch(spec)
+		if m == nil {
+			return nil, fmt.Errorf("invalid translation function %q", spec)
+		}
+		fn := Func{Name: m[1]}
+		for i, arg := range strings.Split(m[2], ",") {
+			var pos *int
+			switch strings.TrimSpace(arg) {
+			case "term":
+				pos = &fn.Term
+			case "plural":
+				pos = &fn.Plural
+			case "context":
+				pos = &fn.Context
+			case "_":
+				continue
+			default:
+				return nil, fmt.Errorf("invalid argument %q of %s, expected term, plural, context or _", arg, fn.Name)
+			}
+			if *pos != 0 {
+				return nil, fmt.Errorf("%s has several %s arguments", fn.Name, strings.TrimSpace(arg))
+			}
+			*pos = i + 1
+		}
+		if fn.Term == 0 {
+			return nil, fmt.Errorf("%s has no term argument", fn.Name)
+		}
+		funcs = append(funcs, fn)
+	}
+	return funcs, nil
+}
+
+// splitFuncs splits a list of function specs on the commas between them
+func splitFuncs(s string) []string {
+	var (
+		specs []string
+		depth int
+		start int
+	)
+	for i, c := range s {
+		switch c {
+		case '(':
+			depth++
+		case ')':
+			depth--
+		case ',':
+			if depth == 0 {
+				specs = append(specs, s[start:i])
+				start = i + 1
+			}
+		}
+	}
+	if strings.TrimSpace(s[start:]) != "" {
+		specs = append(specs, s[start:])
+	}
+	return specs
+}
@@ -0,0 +1,77 @@
+package extract_test
+
+import (
+	"go/parser"
+	"go/token"
+	"reflect"
+	"testing"
+
+	"github.com/blacksails/poeditor"
+	"github.com/blacksails/poeditor/extract"
+)
+
+const src = `package main
+
+func main() {
+	// TRANSLATORS: shown on the start page
+	greeting := T("Hello")
+	items := i18n.Tn("cart", "%d item", "%d items", n)
+	fmt.Println(T("Hello"), T("Good" + "bye"), Tc("menu", "Open"))
+	T(key)
+	other.Tn("x")
+}
+`
+
+func TestExtractorFile(t *testing.T) {
+	term := func(term, context, plural, reference, comment string) poeditor.Term {
+		t := poeditor.Term{Plural: plural, Reference: reference, Comment: comment}
+		t.Term = term
+		t.Context = context
+		return t
+	}
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, "main.go", src, parser.ParseComments)
+	if err != nil {
+		t.Fatal(err)
+	}
+	e := extract.Extractor{Funcs: []extract.Func{
+		{Name: "T", Term: 1},
+		{Name: "i18n.Tn", Context: 1, Term: 2, Plural: 3},
+		{Name: "Tc", Context: 1, Term: 2},
+	}}
+	res := e.File(fset, f)
+	want := []poeditor.Term{
+		term("Hello", "", "", "main.go:5 main.go:7", "shown on the start page"),
+		term("%d item", "cart", "%d items", "main.go:6", ""),
+		term("Goodbye", "", "", "main.go:7", ""),
+		term("Open", "menu", "", "main.go:7", ""),
+	}
+	if !reflect.DeepEqual(res.Terms, want) {
+		t.Errorf("\nExpected %+v\nGot      %+v", want, res.Terms)
+	}
+	if len(res.Warnings) != 1 || res.Warnings[0].String() != "main.go:8:2: T: argument 1 is not a constant string" {
+		t.Errorf("Unexpected warnings %v", res.Warnings)
+	}
+}
+
+func TestParseFuncs(t *testing.T) {
+	funcs, err := extract.ParseFuncs("T(term), i18n.Tn(context, term, plural, _)")
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	want := []extract.Func{
+		{Name: "T", Term: 1},
+		{Name: "i18n.Tn", Context: 1, Term: 2, Plural: 3},
+	}
+	if !reflect.DeepEqual(funcs, want) {
+		t.Errorf("\nExpected %+v\nGot      %+v", want, funcs)
+	}
+	if s := funcs[1].String(); s != "i18n.Tn(context, term, plural)" {
+		t.Errorf("Unexpected String %q", s)
+	}
+	for _, spec := range []string{"T", "T(x)", "T(plural)", "T(term, term)"} {
+		if _, err := extract.ParseFuncs(spec); err == nil {
+			t.Errorf("%q: expected an error", spec)
+		}
+	}
+}
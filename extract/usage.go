@@ -0,0 +1,53 @@
+package extract
+
+import "github.com/blacksails/poeditor"
+
+// ObsoleteTag is the tag TagObsolete adds by default
+const ObsoleteTag = "obsolete"
+
+// Usage compares the terms used in code with the terms of a project
+type Usage struct {
+	// Unused holds the project terms no longer found in the code. These are
+	// candidates for deletion or for tagging as obsolete.
+	Unused []poeditor.Term
+	// Undefined holds the terms used in the code which are missing in the
+	// project
+	Undefined []poeditor.Term
+}
+
+// CompareUsage compares extracted terms with the terms of a project, as
+// returned from Project.ListTerms. Terms are matched by term and context.
+func CompareUsage(code, project []poeditor.Term) Usage {
+	d := poeditor.DiffTerms(project, code)
+	return Usage{Unused: d.Removed, Undefined: d.Added}
+}
+
+// TagObsolete adds the tag to the unused terms through Project.UpdateTerms
+// instead of deleting them. The tag defaults to ObsoleteTag. Terms which
+// already have the tag are left alone.
+func (u Usage) TagObsolete(p *poeditor.Project, tag string) (poeditor.CountResult, error) {
+	if tag == "" {
+		tag = ObsoleteTag
+	}
+	var updates []poeditor.TermUpdate
+	for _, t := range u.Unused {
+		if hasTag(t.Tags, tag) {
+			continue
+		}
+		t.Tags = append(append([]string(nil), t.Tags...), tag)
+		updates = append(updates, poeditor.TermUpdate{Term: t})
+	}
+	if len(updates) == 0 {
+		return poeditor.CountResult{}, nil
+	}
+	return p.UpdateTerms(updates, false)
+}
+
+func hasTag(tags []string, tag string) bool {
+	for _, t := range tags {
+		if t == tag {
+			return true
+		}
+	}
+	return false
+}
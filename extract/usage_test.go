@@ -0,0 +1,106 @@
+package extract_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/blacksails/poeditor"
+	"github.com/blacksails/poeditor/extract"
+)
+
+func newTerm(term, context string, tags ...string) poeditor.Term {
+	t := poeditor.Term{Tags: tags}
+	t.Term = term
+	t.Context = context
+	return t
+}
+
+func TestCompareUsage(t *testing.T) {
+	code := []poeditor.Term{
+		newTerm("Hello", ""),
+		newTerm("Open", "menu"),
+		newTerm("New", ""),
+	}
+	project := []poeditor.Term{
+		newTerm("Hello", "", "ui"),
+		newTerm("Open", "toolbar"),
+		newTerm("Bye", ""),
+	}
+	u := extract.CompareUsage(code, project)
+	unused := []poeditor.Term{newTerm("Open", "toolbar"), newTerm("Bye", "")}
+	undefined := []poeditor.Term{newTerm("Open", "menu"), newTerm("New", "")}
+	if !reflect.DeepEqual(u.Unused, unused) {
+		t.Errorf("\nExpected unused %+v \nGot             %+v", unused, u.Unused)
+	}
+	if !reflect.DeepEqual(u.Undefined, undefined) {
+		t.Errorf("\nExpected undefined %+v \nGot                %+v", undefined, u.Undefined)
+	}
+	if u := extract.CompareUsage(code, code); len(u.Unused) != 0 || len(u.Undefined) != 0 {
+		t.Errorf("Expected no differences, got %+v", u)
+	}
+}
+
+func TestUsageTagObsolete(t *testing.T) {
+	tests := []struct {
+		name     string
+		tag      string
+		unused   []poeditor.Term
+		expected []poeditor.TermUpdate
+	}{
+		{
+			name:   "default tag",
+			unused: []poeditor.Term{newTerm("Bye", "", "ui"), newTerm("Old", "", extract.ObsoleteTag)},
+			expected: []poeditor.TermUpdate{
+				{Term: newTerm("Bye", "", "ui", extract.ObsoleteTag)},
+			},
+		},
+		{
+			name:   "custom tag",
+			tag:    "unused",
+			unused: []poeditor.Term{newTerm("Bye", ""), newTerm("Old", "", extract.ObsoleteTag)},
+			expected: []poeditor.TermUpdate{
+				{Term: newTerm("Bye", "", "unused")},
+				{Term: newTerm("Old", "", extract.ObsoleteTag, "unused")},
+			},
+		},
+		{
+			name:   "already tagged",
+			unused: []poeditor.Term{newTerm("Old", "", extract.ObsoleteTag)},
+		},
+	}
+	for _, test := range tests {
+		var updates []poeditor.TermUpdate
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path != "/terms/update" {
+				t.Errorf("%s: unexpected request to %s", test.name, r.URL.Path)
+			}
+			if err := json.Unmarshal([]byte(r.FormValue("data")), &updates); err != nil {
+				t.Errorf("%s: unexpected error: %s", test.name, err)
+			}
+			if r.FormValue("fuzzy_trigger") == "1" {
+				t.Errorf("%s: expected no fuzzy trigger", test.name)
+			}
+			w.Write([]byte(`{"response": {"status": "success", "code": "200"}, "result": {"terms": {"parsed": 1, "updated": 1}}}`))
+		}))
+		p := poeditor.NewWithEndpoint("token", srv.URL).Project(1)
+		// Tagging must not change the tags of the usage
+		unused := append([]poeditor.Term(nil), test.unused...)
+		res, err := extract.Usage{Unused: unused}.TagObsolete(p, test.tag)
+		srv.Close()
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %s", test.name, err)
+		}
+		if !reflect.DeepEqual(updates, test.expected) {
+			t.Errorf("%s: \nExpected %+v \nGot      %+v", test.name, test.expected, updates)
+		}
+		if len(test.expected) > 0 && res.Updated != 1 {
+			t.Errorf("%s: expected the update count, got %+v", test.name, res)
+		}
+		if !reflect.DeepEqual(unused, test.unused) {
+			t.Errorf("%s: the unused terms were modified: %+v", test.name, unused)
+		}
+	}
+}
@@ -0,0 +1,237 @@
+// Package glossary enforces a glossary across the translations of projects:
+// source terms which must be translated in a prescribed way per language,
+// and terms such as product names which must not be translated at all.
+//
+// Glossaries are YAML or JSON files:
+//
+//	source_language: en
+//	entries:
+//	  - term: workspace
+//	    translations: {de: Arbeitsbereich, fr: espace de travail}
+//	  - term: POEditor
+//	    do_not_translate: true
+//
+// Check finds the violations in translations, CheckProject and CheckAll in
+// the languages of one or every project, and AddComments posts them as term
+// comments.
+package glossary
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/blacksails/poeditor"
+	"github.com/blacksails/poeditor/internal/cldr"
+	"github.com/blacksails/poeditor/internal/yaml"
+)
+
+// Entry is a glossary term
+type Entry struct {
+	// Term is the text of the entry in the source language. It matches
+	// whole words, ignoring case unless CaseSensitive is set.
+	Term string `json:"term"`
+	// Translations are the required translations of the term by language
+	// code. A translation for a language such as pt also applies to pt-br.
+	Translations map[string]string `json:"translations,omitempty"`
+	// DoNotTranslate requires translations to keep the term as a whole
+	// word, in the same case if CaseSensitive is set
+	DoNotTranslate bool `json:"do_not_translate,omitempty"`
+	CaseSensitive  bool `json:"case_sensitive,omitempty"`
+}
+
+// Glossary is a list of entries in a source language
+type Glossary struct {
+	SourceLanguage string  `json:"source_language"`
+	Entries        []Entry `json:"entries"`
+}
+
+// Violation is a translation which does not follow an entry of the glossary
+type Violation struct {
+	poeditor.TermBase
+	// Project is the id of the project of the term, if known
+	Project  int    `json:"project,omitempty"`
+	Language string `json:"language"`
+	// Entry is the glossary term which is violated
+	Entry   string `json:"entry"`
+	Message string `json:"message"`
+}
+
+func (v Violation) String() string {
+	name := v.Term
+	if v.Context != "" {
+		name += " (" + v.Context + ")"
+	}
+	return fmt.Sprintf("%s [%s]: %s", name, v.Language, v.Message)
+}
+
+// Load reads a glossary, which is YAML or JSON depending on the extension of
+// the file
+func Load(name string) (*Glossary, error) {
+	b, err := os.ReadFile(name)
+	if err != nil {
+		return nil, err
+	}
+	var g Glossary
+	if filepath.Ext(name) == ".json" {
+		err = json.Unmarshal(b, &g)
+	} else {
+		err = yaml.Unmarshal(b, &g)
+	}
+	if err == nil {
+		err = g.Validate()
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", name, err)
+	}
+	return &g, nil
+}
+
+// Validate reports glossaries without source language and entries without
+// term or rule
+func (g *Glossary) Validate() error {
+	if g.SourceLanguage == "" {
+		return errors.New("glossary: source_language is required")
+	}
+	for i, e := range g.Entries {
+		if strings.TrimSpace(e.Term) == "" {
+			return fmt.Errorf("glossary: entry %d has no term", i+1)
+		}
+		if !e.DoNotTranslate && len(e.Translations) == 0 {
+			return fmt.Errorf("glossary: entry %s has neither translations nor do_not_translate", e.Term)
+		}
+	}
+	return nil
+}
+
+// translation returns the required translation of the entry in a language,
+// falling back to the language without region
+func (e Entry) translation(language string) (string, bool) {
+	language = strings.ToLower(strings.Replace(language, "_", "-", -1))
+	for code, t := range e.Translations {
+		if strings.ToLower(code) == language {
+			return t, true
+		}
+	}
+	if i := strings.IndexByte(language, '-'); i >= 0 {
+		return e.translation(language[:i])
+	}
+	return "", false
+}
+
+// Check checks the translations of a language against the source terms,
+// which are matched by TermBase. Source terms without translation are
+// checked by the term itself. Plural forms are checked against the same form
+// of the source, or its other form.
+func (g *Glossary) Check(source []poeditor.TermTranslated, language string, translations []poeditor.TermTranslated) []Violation {
+	sources := make(map[poeditor.TermBase]interface{}, len(source))
+	for _, t := range source {
+		sources[t.TermBase] = t.Translation.Content
+	}
+	var violations []Violation
+	for _, t := range translations {
+		src, ok := sources[t.TermBase]
+		if !ok || poeditor.EmptyContent(src) {
+			src = t.Term.Term
+		}
+		ps := pairs(src, t.Translation.Content)
+		for _, e := range g.Entries {
+			// Report an entry once per term, however many forms violate it
+			for _, p := range ps {
+				if msg := e.check(language, p[0], p[1]); msg != "" {
+					violations = append(violations, Violation{
+						TermBase: t.TermBase,
+						Language: language,
+						Entry:    e.Term,
+						Message:  msg,
+					})
+					break
+				}
+			}
+		}
+	}
+	return violations
+}
+
+// check returns the violation of the entry by a translation, or an empty
+// string
+func (e Entry) check(language, source, translation string) string {
+	if !containsWord(source, e.Term, e.CaseSensitive) {
+		return ""
+	}
+	if e.DoNotTranslate {
+		if !containsWord(translation, e.Term, e.CaseSensitive) {
+			return fmt.Sprintf("%q must not be translated", e.Term)
+		}
+		return ""
+	}
+	required, ok := e.translation(language)
+	if !ok || required == "" {
+		return ""
+	}
+	if !strings.Contains(strings.ToLower(translation), strings.ToLower(required)) {
+		return fmt.Sprintf("%q must be translated as %q", e.Term, required)
+	}
+	return ""
+}
+
+// containsWord reports whether s contains word, not preceded or followed by
+// a letter or digit
+func containsWord(s, word string, caseSensitive bool) bool {
+	if !caseSensitive {
+		s, word = strings.ToLower(s), strings.ToLower(word)
+	}
+	for offset := 0; ; {
+		i := strings.Index(s[offset:], word)
+		if i < 0 {
+			return false
+		}
+		start, end := offset+i, offset+i+len(word)
+		before, _ := utf8.DecodeLastRuneInString(s[:start])
+		after, _ := utf8.DecodeRuneInString(s[end:])
+		if !isWordRune(before) && !isWordRune(after) {
+			return true
+		}
+		_, size := utf8.DecodeRuneInString(s[start:])
+		offset = start + size
+	}
+}
+
+func isWordRune(r rune) bool {
+	return r != utf8.RuneError && (unicode.IsLetter(r) || unicode.IsDigit(r))
+}
+
+// pairs returns each translated form of a translation along with the source
+// form of the same category, or the other form of the source
+func pairs(source, translation interface{}) [][2]string {
+	src := make(map[string]string)
+	switch s := source.(type) {
+	case string:
+		src[cldr.Other] = s
+	case poeditor.Plural:
+		for _, f := range s.Forms() {
+			src[f.Category] = f.Text
+		}
+	}
+	var ps [][2]string
+	switch t := translation.(type) {
+	case string:
+		if t != "" {
+			ps = append(ps, [2]string{src[cldr.Other], t})
+		}
+	case poeditor.Plural:
+		for _, f := range t.Forms() {
+			s, ok := src[f.Category]
+			if !ok {
+				s = src[cldr.Other]
+			}
+			ps = append(ps, [2]string{s, f.Text})
+		}
+	}
+	return ps
+}
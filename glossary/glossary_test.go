@@ -0,0 +1,108 @@
+package glossary_test
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/blacksails/poeditor"
+	"github.com/blacksails/poeditor/glossary"
+	"github.com/blacksails/poeditor/internal/termtest"
+)
+
+func TestLoad(t *testing.T) {
+	dir := t.TempDir()
+	name := filepath.Join(dir, "glossary.yml")
+	data := "source_language: en\n" +
+		"entries:\n" +
+		"  - term: workspace\n" +
+		"    translations: {de: Arbeitsbereich}\n" +
+		"  - term: POEditor\n" +
+		"    do_not_translate: true\n"
+	if err := os.WriteFile(name, []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+	g, err := glossary.Load(name)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	expected := &glossary.Glossary{
+		SourceLanguage: "en",
+		Entries: []glossary.Entry{
+			{Term: "workspace", Translations: map[string]string{"de": "Arbeitsbereich"}},
+			{Term: "POEditor", DoNotTranslate: true},
+		},
+	}
+	if !reflect.DeepEqual(g, expected) {
+		t.Errorf("Expected %+v, got %+v", expected, g)
+	}
+
+	name = filepath.Join(dir, "invalid.json")
+	if err := os.WriteFile(name, []byte(`{"source_language": "en", "entries": [{"term": "x"}]}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := glossary.Load(name); err == nil {
+		t.Error("Expected an entry without rule to be invalid")
+	}
+}
+
+func TestCheck(t *testing.T) {
+	g := &glossary.Glossary{
+		SourceLanguage: "en",
+		Entries: []glossary.Entry{
+			{Term: "workspace", Translations: map[string]string{"de": "Arbeitsbereich", "pt": "espaço de trabalho"}},
+			{Term: "POEditor", DoNotTranslate: true, CaseSensitive: true},
+		},
+	}
+	source := []poeditor.TermTranslated{
+		termtest.Translated("title", "Your Workspace"),
+		termtest.Translated("workspaces", poeditor.Plural{One: "One workspace", Other: "%d workspaces"}),
+		termtest.Translated("about", "About POEditor"),
+		termtest.Translated("poeditors", "poeditor and workspaces2"),
+	}
+	translations := []poeditor.TermTranslated{
+		termtest.Translated("title", "Dein Arbeitsbereich"),
+		termtest.Translated("workspaces", poeditor.Plural{One: "Ein Bereich", Other: "%d Bereiche"}),
+		termtest.Translated("about", "Über PO-Editor"),
+		termtest.Translated("poeditors", "egal"),
+		termtest.Translated("Open the workspace", "Arbeitsplatz öffnen"),
+		termtest.Translated("untranslated workspace", ""),
+	}
+	violations := g.Check(source, "de", translations)
+	var got []string
+	for _, v := range violations {
+		got = append(got, v.String())
+	}
+	expected := []string{
+		`workspaces [de]: "workspace" must be translated as "Arbeitsbereich"`,
+		`about [de]: "POEditor" must not be translated`,
+		`Open the workspace [de]: "workspace" must be translated as "Arbeitsbereich"`,
+	}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("\nExpected %q\nGot      %q", expected, got)
+	}
+
+	violations = g.Check(source, "pt-BR", []poeditor.TermTranslated{termtest.Translated("title", "Seu espaço")})
+	if len(violations) != 1 || violations[0].Entry != "workspace" {
+		t.Errorf("Expected the pt translation to apply to pt-BR, got %+v", violations)
+	}
+
+	// Entries which are not case sensitive accept the term in any case in
+	// the translation too
+	g = &glossary.Glossary{Entries: []glossary.Entry{{Term: "POEditor", DoNotTranslate: true}}}
+	source = []poeditor.TermTranslated{termtest.Translated("export", "poeditor export")}
+	for _, test := range []struct {
+		translation string
+		violations  int
+	}{
+		{"poeditor Export", 0},
+		{"Export aus POEDITOR", 0},
+		{"PO-Editor Export", 1},
+	} {
+		violations = g.Check(source, "de", []poeditor.TermTranslated{termtest.Translated("export", test.translation)})
+		if len(violations) != test.violations {
+			t.Errorf("%q: expected %d violations, got %+v", test.translation, test.violations, violations)
+		}
+	}
+}
@@ -0,0 +1,111 @@
+package glossary
+
+import (
+	"github.com/blacksails/poeditor"
+)
+
+// CheckProject checks the translations of every language of the project.
+// Projects without the source language of the glossary have no violations.
+func (g *Glossary) CheckProject(p *poeditor.Project) ([]Violation, error) {
+	languages, err := p.ListLanguages()
+	if err != nil {
+		return nil, err
+	}
+	found := false
+	for _, l := range languages {
+		if l.Code == g.SourceLanguage {
+			found = true
+			break
+		}
+	}
+	if !found {
+		return nil, nil
+	}
+	src := poeditor.Language{Project: p, Code: g.SourceLanguage}
+	source, err := src.ListTerms()
+	if err != nil {
+		return nil, err
+	}
+	var violations []Violation
+	for _, l := range languages {
+		if l.Code == g.SourceLanguage {
+			continue
+		}
+		l.Project = p
+		terms, err := l.ListTerms()
+		if err != nil {
+			return nil, err
+		}
+		for _, v := range g.Check(source, l.Code, terms) {
+			v.Project = p.ID
+			violations = append(violations, v)
+		}
+	}
+	return violations, nil
+}
+
+// CheckAll checks every project accessible by the API token
+func (g *Glossary) CheckAll(poe *poeditor.POEditor) ([]Violation, error) {
+	projects, err := poe.ListProjects()
+	if err != nil {
+		return nil, err
+	}
+	var violations []Violation
+	for _, p := range projects {
+		vs, err := g.CheckProject(p)
+		if err != nil {
+			return nil, err
+		}
+		violations = append(violations, vs...)
+	}
+	return violations, nil
+}
+
+// AddComments comments on the terms with violations in their projects, with
+// one comment per term and language listing its violations. The counts of
+// the projects are summed.
+func AddComments(poe *poeditor.POEditor, violations []Violation) (poeditor.CountResult, error) {
+	type key struct {
+		project  int
+		language string
+		term     poeditor.TermBase
+	}
+	var (
+		keys     []key
+		comments = make(map[key]string)
+	)
+	for _, v := range violations {
+		k := key{v.Project, v.Language, v.TermBase}
+		if _, ok := comments[k]; !ok {
+			keys = append(keys, k)
+			comments[k] = "Glossary " + v.Language + ":"
+		}
+		comments[k] += "\n- " + v.Message
+	}
+	var (
+		total     poeditor.CountResult
+		projects  []int
+		byProject = make(map[int][]poeditor.TermComment)
+	)
+	for _, k := range keys {
+		if _, ok := byProject[k.project]; !ok {
+			projects = append(projects, k.project)
+		}
+		byProject[k.project] = append(byProject[k.project], poeditor.TermComment{
+			TermBase: k.term,
+			Comment:  comments[k],
+		})
+	}
+	for _, id := range projects {
+		c, err := poe.Project(id).AddComments(byProject[id])
+		if err != nil {
+			return total, err
+		}
+		total.Parsed += c.Parsed
+		total.Added += c.Added
+		total.Deleted += c.Deleted
+		total.WithAddedComment += c.WithAddedComment
+		total.Updated += c.Updated
+	}
+	return total, nil
+}
@@ -0,0 +1,383 @@
+// Package goi18n converts between go-i18n v2 message files and terms.
+//
+// Message files are read and written in the TOML, JSON and YAML formats of
+// github.com/nicksnyder/go-i18n. The id of a message is the term, the
+// description is the term comment and messages with plural forms become
+// plural terms. Terms have no context in go-i18n, so contexts are dropped.
+//
+// go-i18n records the hash of the source message a translation was made
+// from. Push marks translations whose hash no longer matches their source
+// message as fuzzy, and Pull writes the current hash for translations which
+// are not fuzzy.
+package goi18n
+
+import (
+	"bytes"
+	"crypto/sha1"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/blacksails/poeditor"
+	"github.com/blacksails/poeditor/internal/toml"
+	"github.com/blacksails/poeditor/internal/yaml"
+)
+
+// Message file formats
+const (
+	FormatTOML = "toml"
+	FormatJSON = "json"
+	FormatYAML = "yaml"
+)
+
+// ErrFormatUnsupported is returned for unknown message file formats
+var ErrFormatUnsupported = errors.New("goi18n: unsupported file format")
+
+// FormatFromFilename returns the format matching the extension of a file
+// name, such as active.en.toml, or an empty string if it is unknown
+func FormatFromFilename(name string) string {
+	switch strings.ToLower(filepath.Ext(name)) {
+	case ".toml":
+		return FormatTOML
+	case ".json":
+		return FormatJSON
+	case ".yaml", ".yml":
+		return FormatYAML
+	}
+	return ""
+}
+
+// Message is a message of a go-i18n message file
+type Message struct {
+	ID          string `json:"-"`
+	Description string `json:"description,omitempty"`
+	Hash        string `json:"hash,omitempty"`
+	LeftDelim   string `json:"leftDelim,omitempty"`
+	RightDelim  string `json:"rightDelim,omitempty"`
+	Zero        string `json:"zero,omitempty"`
+	One         string `json:"one,omitempty"`
+	Two         string `json:"two,omitempty"`
+	Few         string `json:"few,omitempty"`
+	Many        string `json:"many,omitempty"`
+	Other       string `json:"other,omitempty"`
+}
+
+// Hash returns the hash of a source message the way goi18n merge computes
+// it, from the description and the other form
+func Hash(m Message) string {
+	h := sha1.New()
+	io.WriteString(h, m.Description)
+	io.WriteString(h, m.Other)
+	return fmt.Sprintf("sha1-%x", h.Sum(nil))
+}
+
+// Stale reports whether the message was translated from another version of
+// the source message. Messages without a hash are never stale.
+func (m Message) Stale(source Message) bool {
+	return m.Hash != "" && m.Hash != Hash(source)
+}
+
+// plural reports whether the message has other forms than other
+func (m Message) plural() bool {
+	return m.Zero != "" || m.One != "" || m.Two != "" || m.Few != "" || m.Many != ""
+}
+
+// simple reports whether the message can be written as a plain string
+func (m Message) simple() bool {
+	return m == Message{ID: m.ID, Other: m.Other}
+}
+
+// fields maps the keys of message tables to the fields of a message
+func (m *Message) fields() map[string]*string {
+	return map[string]*string{
+		"id":          &m.ID,
+		"description": &m.Description,
+		"hash":        &m.Hash,
+		"leftdelim":   &m.LeftDelim,
+		"rightdelim":  &m.RightDelim,
+		"zero":        &m.Zero,
+		"one":         &m.One,
+		"two":         &m.Two,
+		"few":         &m.Few,
+		"many":        &m.Many,
+		"other":       &m.Other,
+		// The name of the other form in go-i18n v1 files
+		"translation": &m.Other,
+	}
+}
+
+// TermTranslated returns the message as a term with its translation
+func (m Message) TermTranslated() poeditor.TermTranslated {
+	var t poeditor.TermTranslated
+	t.Term.Term = m.ID
+	t.Comment = m.Description
+	t.Translation.Content = m.Other
+	if m.plural() {
+		t.Plural = m.ID
+		t.Translation.Content = poeditor.Plural{
+			Zero: m.Zero, One: m.One, Two: m.Two, Few: m.Few, Many: m.Many, Other: m.Other,
+		}
+	}
+	return t
+}
+
+// NewMessage returns the message of a term and its translation. The context
+// of the term is dropped.
+func NewMessage(t poeditor.TermTranslated) Message {
+	m := Message{ID: t.Term.Term, Description: t.Comment}
+	switch c := t.Translation.Content.(type) {
+	case string:
+		m.Other = c
+	case poeditor.Plural:
+		m.Zero, m.One, m.Two, m.Few, m.Many, m.Other = c.Zero, c.One, c.Two, c.Few, c.Many, c.Other
+	}
+	return m
+}
+
+// Read decodes a message file. Nested tables without message fields are
+// flattened into ids joined by dots, and messages are sorted by id.
+func Read(r io.Reader, format string) ([]Message, error) {
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	var tree interface{}
+	switch format {
+	case FormatTOML:
+		tree, err = toml.Parse(data)
+	case FormatJSON:
+		if len(bytes.TrimSpace(data)) > 0 {
+			err = json.Unmarshal(data, &tree)
+		}
+	case FormatYAML:
+		tree, err = yaml.Parse(data)
+	default:
+		return nil, ErrFormatUnsupported
+	}
+	if err != nil {
+		return nil, err
+	}
+	var msgs []Message
+	switch tree := tree.(type) {
+	case nil:
+	case map[string]interface{}:
+		if err := readTable(&msgs, "", tree); err != nil {
+			return nil, err
+		}
+	case []interface{}:
+		// The message list of go-i18n v1 files
+		for _, v := range tree {
+			table, ok := v.(map[string]interface{})
+			if !ok {
+				return nil, errors.New("goi18n: message lists must hold tables")
+			}
+			m, err := readMessage("", table)
+			if err != nil {
+				return nil, err
+			}
+			msgs = append(msgs, m)
+		}
+	default:
+		return nil, errors.New("goi18n: a message file must hold a table")
+	}
+	sort.SliceStable(msgs, func(i, j int) bool { return msgs[i].ID < msgs[j].ID })
+	return msgs, nil
+}
+
+func readTable(msgs *[]Message, prefix string, table map[string]interface{}) error {
+	for key, v := range table {
+		id := prefix + key
+		switch v := v.(type) {
+		case string:
+			*msgs = append(*msgs, Message{ID: id, Other: v})
+		case map[string]interface{}:
+			if !isMessage(v) {
+				if err := readTable(msgs, id+".", v); err != nil {
+					return err
+				}
+				continue
+			}
+			m, err := readMessage(id, v)
+			if err != nil {
+				return err
+			}
+			*msgs = append(*msgs, m)
+		default:
+			return fmt.Errorf("goi18n: message %q is not a string or table", id)
+		}
+	}
+	return nil
+}
+
+// isMessage reports whether a table is a message rather than a group of
+// nested messages
+func isMessage(table map[string]interface{}) bool {
+	fields := (&Message{}).fields()
+	for key, v := range table {
+		if _, ok := v.(string); ok && fields[strings.ToLower(key)] != nil {
+			return true
+		}
+	}
+	return false
+}
+
+func readMessage(id string, table map[string]interface{}) (Message, error) {
+	m := Message{ID: id}
+	fields := m.fields()
+	for key, v := range table {
+		f := fields[strings.ToLower(key)]
+		if f == nil {
+			continue
+		}
+		s, ok := v.(string)
+		if !ok {
+			return m, fmt.Errorf("goi18n: field %s of message %q is not a string", key, id)
+		}
+		*f = s
+	}
+	if m.ID == "" {
+		return m, errors.New("goi18n: message without id")
+	}
+	return m, nil
+}
+
+// Write encodes messages as a message file. Messages holding only the other
+// form are written as plain strings.
+func Write(w io.Writer, format string, msgs []Message) error {
+	msgs = append([]Message(nil), msgs...)
+	sort.SliceStable(msgs, func(i, j int) bool { return msgs[i].ID < msgs[j].ID })
+	var buf bytes.Buffer
+	switch format {
+	case FormatTOML:
+		writeTOML(&buf, msgs)
+	case FormatJSON:
+		if err := writeJSON(&buf, msgs); err != nil {
+			return err
+		}
+	case FormatYAML:
+		writeYAML(&buf, msgs)
+	default:
+		return ErrFormatUnsupported
+	}
+	_, err := buf.WriteTo(w)
+	return err
+}
+
+// entries returns the fields of a message table which are set, in the
+// order go-i18n writes them
+func (m Message) entries() [][2]string {
+	var entries [][2]string
+	for _, e := range [][2]string{
+		{"description", m.Description}, {"hash", m.Hash},
+		{"leftDelim", m.LeftDelim}, {"rightDelim", m.RightDelim},
+		{"zero", m.Zero}, {"one", m.One}, {"two", m.Two},
+		{"few", m.Few}, {"many", m.Many}, {"other", m.Other},
+	} {
+		if e[1] != "" {
+			entries = append(entries, e)
+		}
+	}
+	return entries
+}
+
+func writeTOML(buf *bytes.Buffer, msgs []Message) {
+	// Keys of the root table come before the first table header
+	for _, m := range msgs {
+		if m.simple() {
+			fmt.Fprintf(buf, "%s = %s\n", toml.QuoteKey(m.ID), toml.Quote(m.Other))
+		}
+	}
+	for _, m := range msgs {
+		if m.simple() {
+			continue
+		}
+		if buf.Len() > 0 {
+			buf.WriteString("\n")
+		}
+		fmt.Fprintf(buf, "[%s]\n", toml.QuoteKey(m.ID))
+		for _, e := range m.entries() {
+			fmt.Fprintf(buf, "%s = %s\n", strings.ToLower(e[0]), toml.Quote(e[1]))
+		}
+	}
+}
+
+func writeJSON(buf *bytes.Buffer, msgs []Message) error {
+	buf.WriteString("{")
+	for i, m := range msgs {
+		var v interface{} = m
+		if m.simple() {
+			v = m.Other
+		}
+		key, err := marshal(m.ID)
+		if err != nil {
+			return err
+		}
+		value, err := marshal(v)
+		if err != nil {
+			return err
+		}
+		if i > 0 {
+			buf.WriteString(",")
+		}
+		value = bytes.Replace(value, []byte("\n"), []byte("\n  "), -1)
+		fmt.Fprintf(buf, "\n  %s: %s", key, value)
+	}
+	if len(msgs) > 0 {
+		buf.WriteString("\n")
+	}
+	buf.WriteString("}\n")
+	return nil
+}
+
+func writeYAML(buf *bytes.Buffer, msgs []Message) {
+	for _, m := range msgs {
+		if m.simple() {
+			fmt.Fprintf(buf, "%s: %s\n", yamlKey(m.ID), yamlString(m.Other))
+			continue
+		}
+		fmt.Fprintf(buf, "%s:\n", yamlKey(m.ID))
+		for _, e := range m.entries() {
+			fmt.Fprintf(buf, "  %s: %s\n", e[0], yamlString(e[1]))
+		}
+	}
+}
+
+// marshal encodes a value as indented JSON without escaping HTML, which is
+// common in messages
+func marshal(v interface{}) ([]byte, error) {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(v); err != nil {
+		return nil, err
+	}
+	return bytes.TrimSuffix(buf.Bytes(), []byte("\n")), nil
+}
+
+// yamlString returns s as a double quoted YAML scalar, whose escapes are a
+// superset of those of JSON
+func yamlString(s string) string {
+	b, _ := marshal(s)
+	return string(b)
+}
+
+// yamlKey returns key as a plain scalar if it is an identifier, or else
+// quoted
+func yamlKey(key string) string {
+	for i, c := range key {
+		if !(c == '_' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || i > 0 && (c >= '0' && c <= '9' || c == '.' || c == '-')) {
+			return yamlString(key)
+		}
+	}
+	switch strings.ToLower(key) {
+	case "", "true", "false", "yes", "no", "on", "off", "null":
+		return yamlString(key)
+	}
+	return key
+}
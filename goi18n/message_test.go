@@ -0,0 +1,140 @@
+package goi18n_test
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/blacksails/poeditor"
+	"github.com/blacksails/poeditor/goi18n"
+)
+
+var messages = []goi18n.Message{
+	{ID: "HelloWorld", Other: "Hello <b>World</b>!"},
+	{ID: "PersonCats", Description: "The number of cats a person has",
+		Hash: "sha1-f937a0e05e19bfe6cd70937c980eaf1f9832f091",
+		One:  "{{.Name}} has {{.Count}} cat.", Other: "{{.Name}} has {{.Count}} cats."},
+	{ID: "quote \"key\"", Other: "line\nbreak"},
+}
+
+func TestReadWrite(t *testing.T) {
+	for _, format := range []string{goi18n.FormatTOML, goi18n.FormatJSON, goi18n.FormatYAML} {
+		var buf bytes.Buffer
+		if err := goi18n.Write(&buf, format, messages); err != nil {
+			t.Fatalf("%s: %s", format, err)
+		}
+		got, err := goi18n.Read(&buf, format)
+		if err != nil {
+			t.Fatalf("%s: %s", format, err)
+		}
+		if !reflect.DeepEqual(got, messages) {
+			t.Errorf("%s:\nExpected %+v\nGot      %+v", format, messages, got)
+		}
+	}
+}
+
+func TestRead(t *testing.T) {
+	tests := []struct {
+		format, doc string
+	}{
+		{goi18n.FormatTOML, `
+[Nested.PersonCats]
+Description = "The number of cats a person has"
+one = "{{.Name}} has {{.Count}} cat."
+other = "{{.Name}} has {{.Count}} cats."
+
+[Nested.Deeper]
+Hello = "Hello"
+`},
+		{goi18n.FormatJSON, `{
+  "Nested": {
+    "PersonCats": {"description": "The number of cats a person has", "one": "{{.Name}} has {{.Count}} cat.", "other": "{{.Name}} has {{.Count}} cats."},
+    "Deeper": {"Hello": "Hello"}
+  }
+}`},
+		{goi18n.FormatYAML, `- id: Nested.Deeper.Hello
+  translation: Hello
+- id: Nested.PersonCats
+  description: The number of cats a person has
+  one: "{{.Name}} has {{.Count}} cat."
+  other: "{{.Name}} has {{.Count}} cats."
+`},
+	}
+	want := []goi18n.Message{
+		{ID: "Nested.Deeper.Hello", Other: "Hello"},
+		{ID: "Nested.PersonCats", Description: "The number of cats a person has",
+			One: "{{.Name}} has {{.Count}} cat.", Other: "{{.Name}} has {{.Count}} cats."},
+	}
+	for _, test := range tests {
+		got, err := goi18n.Read(strings.NewReader(test.doc), test.format)
+		if err != nil {
+			t.Fatalf("%s: %s", test.format, err)
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("%s:\nExpected %+v\nGot      %+v", test.format, want, got)
+		}
+	}
+	if _, err := goi18n.Read(strings.NewReader("a = 1\n"), goi18n.FormatTOML); err == nil {
+		t.Error("Expected an error for a number message")
+	}
+}
+
+func TestReadYAMLBlockScalars(t *testing.T) {
+	doc := `Welcome:
+  description: Shown on the start page
+  other: |
+    Welcome!
+    Have a look around.
+Terms: >-
+  By signing up you agree
+  to the terms.
+`
+	got, err := goi18n.Read(strings.NewReader(doc), goi18n.FormatYAML)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []goi18n.Message{
+		{ID: "Terms", Other: "By signing up you agree to the terms."},
+		{ID: "Welcome", Description: "Shown on the start page", Other: "Welcome!\nHave a look around.\n"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("\nExpected %+v\nGot      %+v", want, got)
+	}
+}
+
+func TestStale(t *testing.T) {
+	source := messages[1]
+	translation := goi18n.Message{ID: source.ID, Hash: goi18n.Hash(source), Other: "{{.Name}} a {{.Count}} chats."}
+	if goi18n.Hash(source) != source.Hash {
+		t.Errorf("Expected hash %s, got %s", source.Hash, goi18n.Hash(source))
+	}
+	if translation.Stale(source) {
+		t.Error("Expected translation with the source hash not to be stale")
+	}
+	source.Other = "{{.Name}} owns {{.Count}} cats."
+	if !translation.Stale(source) {
+		t.Error("Expected translation of a changed source to be stale")
+	}
+	translation.Hash = ""
+	if translation.Stale(source) {
+		t.Error("Expected translation without hash not to be stale")
+	}
+}
+
+func TestTermTranslated(t *testing.T) {
+	var want poeditor.TermTranslated
+	want.Term.Term = "PersonCats"
+	want.Plural = "PersonCats"
+	want.Comment = "The number of cats a person has"
+	want.Translation.Content = poeditor.Plural{One: "{{.Name}} has {{.Count}} cat.", Other: "{{.Name}} has {{.Count}} cats."}
+	got := messages[1].TermTranslated()
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("\nExpected %+v\nGot      %+v", want, got)
+	}
+	m := messages[1]
+	m.Hash = ""
+	if back := goi18n.NewMessage(got); back != m {
+		t.Errorf("\nExpected %+v\nGot      %+v", m, back)
+	}
+}
@@ -0,0 +1,119 @@
+package goi18n
+
+import (
+	"github.com/blacksails/poeditor"
+)
+
+// PushResult is returned from Push
+type PushResult struct {
+	// Terms counts the terms added for new source messages
+	Terms poeditor.CountResult
+	// Translations counts the updated translations by language
+	Translations map[string]poeditor.CountResult
+	// Stale lists the ids of the translations marked fuzzy by language,
+	// because their hash does not match the source message
+	Stale map[string][]string
+}
+
+// Push adds the source messages missing in the project as terms, and updates
+// the translations of every language in messages, which are keyed by
+// language code and include the source language. Terms missing in the
+// message files are left alone.
+func Push(p *poeditor.Project, source string, messages map[string][]Message) (PushResult, error) {
+	res := PushResult{
+		Translations: make(map[string]poeditor.CountResult),
+		Stale:        make(map[string][]string),
+	}
+	sources := make(map[string]Message)
+	var terms []poeditor.Term
+	for _, m := range messages[source] {
+		sources[m.ID] = m
+		terms = append(terms, m.TermTranslated().Term)
+	}
+	if len(terms) > 0 {
+		c, err := p.AddTerms(terms)
+		if err != nil {
+			return res, err
+		}
+		res.Terms = c
+	}
+	for code, msgs := range messages {
+		var translations []poeditor.TermTranslation
+		for _, m := range msgs {
+			src, ok := sources[m.ID]
+			if code != source && !ok {
+				// Translations of removed source messages have no term
+				continue
+			}
+			t := poeditor.TermTranslation{Translation: m.TermTranslated().Translation}
+			t.Term = m.ID
+			if code != source && m.Stale(src) {
+				t.Translation.Fuzzy = 1
+				res.Stale[code] = append(res.Stale[code], m.ID)
+			}
+			translations = append(translations, t)
+		}
+		if len(translations) == 0 {
+			continue
+		}
+		l := poeditor.Language{Project: p, Code: code}
+		c, err := l.Update(translations)
+		if err != nil {
+			return res, err
+		}
+		res.Translations[code] = c
+	}
+	return res, nil
+}
+
+// Pull returns the messages of a language. Descriptions are taken from the
+// term comments, and translations which are not fuzzy get the hash of the
+// source message so go-i18n considers them up to date. Untranslated terms
+// and terms with a context are left out.
+func Pull(p *poeditor.Project, source, language string) ([]Message, error) {
+	src := poeditor.Language{Project: p, Code: source}
+	sourceTerms, err := src.ListTerms()
+	if err != nil {
+		return nil, err
+	}
+	hashes := make(map[string]string)
+	var sources []Message
+	for _, t := range sourceTerms {
+		if t.Context != "" {
+			continue
+		}
+		m := NewMessage(t)
+		hashes[m.ID] = Hash(m)
+		if !empty(m) {
+			sources = append(sources, m)
+		}
+	}
+	if language == source {
+		return sources, nil
+	}
+	l := poeditor.Language{Project: p, Code: language}
+	terms, err := l.ListTerms()
+	if err != nil {
+		return nil, err
+	}
+	var msgs []Message
+	for _, t := range terms {
+		if t.Context != "" {
+			continue
+		}
+		m := NewMessage(t)
+		if empty(m) {
+			continue
+		}
+		if t.Translation.Fuzzy == 0 {
+			m.Hash = hashes[m.ID]
+		}
+		msgs = append(msgs, m)
+	}
+	return msgs, nil
+}
+
+// empty reports whether a message has no translation
+func empty(m Message) bool {
+	return Message{ID: m.ID, Description: m.Description, Hash: m.Hash} == m
+}
@@ -0,0 +1,473 @@
+// Package icu parses and validates ICU MessageFormat messages.
+//
+// Parse checks the syntax of a message and returns its tree. Check also
+// requires plural arguments to cover the CLDR plural categories of the
+// language, and select arguments to have an other branch:
+//
+//	msg, err := icu.Parse("{count, plural, one {# file} other {# files}}")
+//	problems := icu.Check("ru", msg) // plural count misses few, many
+//
+// A Validator checks translations before they are written with
+// Language.Update, comparing their argument names with the source language.
+// Its Validate method is a poeditor.TranslationValidator:
+//
+//	p.AddTranslationValidator(icu.NewValidator(source).Validate)
+package icu
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Argument types with sub-messages
+const (
+	TypePlural        = "plural"
+	TypeSelect        = "select"
+	TypeSelectOrdinal = "selectordinal"
+)
+
+// Message is a parsed message, a sequence of Text, Pound and *Argument nodes
+type Message []Node
+
+// Node is a part of a message
+type Node interface {
+	node()
+}
+
+// Text is literal text, with quoting removed
+type Text string
+
+// Pound is the # of a plural sub-message, which prints the plural number
+type Pound struct{}
+
+// Argument is an argument such as {name}, {n, number, integer} or
+// {n, plural, one {...} other {...}}
+type Argument struct {
+	Name string
+	// Type is empty for simple arguments such as {name}
+	Type string
+	// Style is the style of simple arguments, e.g. integer or ::currency/EUR
+	Style string
+	// Offset is the offset of plural arguments
+	Offset int
+	// Options are the branches of plural, select and selectordinal
+	// arguments
+	Options []Option
+	// Pos is the byte offset of the argument in the message
+	Pos int
+}
+
+// Option is a branch of a plural or select argument
+type Option struct {
+	// Selector is a keyword such as one or other, or an exact value such as
+	// =0
+	Selector string
+	Message  Message
+}
+
+func (Text) node()      {}
+func (Pound) node()     {}
+func (*Argument) node() {}
+
+// SyntaxError is returned from Parse for invalid messages
+type SyntaxError struct {
+	// Offset is the byte offset of the error in the message
+	Offset int
+	Msg    string
+}
+
+func (e *SyntaxError) Error() string {
+	return fmt.Sprintf("icu: offset %d: %s", e.Offset, e.Msg)
+}
+
+// Parse parses a message
+func Parse(s string) (Message, error) {
+	p := &parser{src: s}
+	msg, err := p.message(0)
+	if err != nil {
+		return nil, err
+	}
+	if p.pos < len(s) {
+		return nil, p.errorf("unexpected }")
+	}
+	return msg, nil
+}
+
+type parser struct {
+	src string
+	pos int
+}
+
+func (p *parser) errorf(format string, args ...interface{}) error {
+	return &SyntaxError{Offset: p.pos, Msg: fmt.Sprintf(format, args...)}
+}
+
+func (p *parser) eof() bool {
+	return p.pos >= len(p.src)
+}
+
+func (p *parser) peek() byte {
+	if p.eof() {
+		return 0
+	}
+	return p.src[p.pos]
+}
+
+// message parses nodes until an unmatched } or the end of the input. In
+// plural sub-messages, which have a depth of plural arguments above zero, #
+// is a Pound node.
+func (p *parser) message(plurals int) (Message, error) {
+	var (
+		msg  Message
+		text strings.Builder
+	)
+	flush := func() {
+		if text.Len() > 0 {
+			msg = append(msg, Text(text.String()))
+			text.Reset()
+		}
+	}
+	for !p.eof() {
+		c := p.peek()
+		switch {
+		case c == '}':
+			flush()
+			return msg, nil
+		case c == '{':
+			flush()
+			arg, err := p.argument(plurals)
+			if err != nil {
+				return nil, err
+			}
+			msg = append(msg, arg)
+		case c == '#' && plurals > 0:
+			flush()
+			msg = append(msg, Pound{})
+			p.pos++
+		case c == '\'':
+			p.quoted(&text, plurals > 0)
+		default:
+			text.WriteByte(c)
+			p.pos++
+		}
+	}
+	flush()
+	return msg, nil
+}
+
+// quoted reads an apostrophe: two apostrophes are a literal apostrophe, and an
+// apostrophe before a special character starts quoted text up to the next
+// single apostrophe. Other apostrophes are literal.
+func (p *parser) quoted(text *strings.Builder, plural bool) {
+	p.pos++
+	switch c := p.peek(); {
+	case c == '\'':
+		text.WriteByte('\'')
+		p.pos++
+		return
+	case c == '{' || c == '}' || c == '|' || c == '#' && plural:
+	default:
+		text.WriteByte('\'')
+		return
+	}
+	for !p.eof() {
+		c := p.peek()
+		p.pos++
+		if c != '\'' {
+			text.WriteByte(c)
+			continue
+		}
+		if p.peek() == '\'' {
+			text.WriteByte('\'')
+			p.pos++
+			continue
+		}
+		return
+	}
+}
+
+func (p *parser) skipSpace() {
+	for !p.eof() && isSpace(p.peek()) {
+		p.pos++
+	}
+}
+
+func isSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r'
+}
+
+// word reads a name, keyword or selector
+func (p *parser) word() string {
+	start := p.pos
+	for !p.eof() {
+		c := p.peek()
+		if isSpace(c) || strings.IndexByte("{},#'", c) >= 0 {
+			break
+		}
+		p.pos++
+	}
+	return p.src[start:p.pos]
+}
+
+func (p *parser) expect(c byte) error {
+	p.skipSpace()
+	if p.peek() != c {
+		if p.eof() {
+			return p.errorf("expected %c, found end of message", c)
+		}
+		return p.errorf("expected %c, found %q", c, p.peek())
+	}
+	p.pos++
+	return nil
+}
+
+func (p *parser) argument(plurals int) (*Argument, error) {
+	arg := &Argument{Pos: p.pos}
+	p.pos++
+	p.skipSpace()
+	arg.Name = p.word()
+	if !validName(arg.Name) {
+		return nil, p.errorf("invalid argument name %q", arg.Name)
+	}
+	p.skipSpace()
+	if p.peek() == '}' {
+		p.pos++
+		return arg, nil
+	}
+	if err := p.expect(','); err != nil {
+		return nil, err
+	}
+	p.skipSpace()
+	arg.Type = p.word()
+	switch arg.Type {
+	case "":
+		return nil, p.errorf("missing argument type")
+	case TypePlural, TypeSelectOrdinal:
+		if err := p.expect(','); err != nil {
+			return nil, err
+		}
+		if err := p.options(arg, plurals+1); err != nil {
+			return nil, err
+		}
+	case TypeSelect:
+		if err := p.expect(','); err != nil {
+			return nil, err
+		}
+		if err := p.options(arg, plurals); err != nil {
+			return nil, err
+		}
+	default:
+		p.skipSpace()
+		if p.peek() == ',' {
+			p.pos++
+			style, err := p.style()
+			if err != nil {
+				return nil, err
+			}
+			arg.Style = style
+		}
+	}
+	if err := p.expect('}'); err != nil {
+		return nil, err
+	}
+	return arg, nil
+}
+
+// style reads the style of a simple argument, which may contain quoted text
+// and balanced braces
+func (p *parser) style() (string, error) {
+	start := p.pos
+	depth := 0
+	for !p.eof() {
+		switch p.peek() {
+		case '\'':
+			end := strings.IndexByte(p.src[p.pos+1:], '\'')
+			if end < 0 {
+				return "", p.errorf("unterminated quote")
+			}
+			p.pos += end + 2
+			continue
+		case '{':
+			depth++
+		case '}':
+			if depth == 0 {
+				return strings.TrimSpace(p.src[start:p.pos]), nil
+			}
+			depth--
+		}
+		p.pos++
+	}
+	return "", p.errorf("unterminated argument")
+}
+
+// options reads the offset and branches of a plural or select argument
+func (p *parser) options(arg *Argument, plurals int) error {
+	seen := make(map[string]bool)
+	for {
+		p.skipSpace()
+		if p.eof() || p.peek() == '}' {
+			break
+		}
+		start := p.pos
+		selector := p.word()
+		if strings.HasPrefix(selector, "offset:") && arg.Type != TypeSelect && len(arg.Options) == 0 && arg.Offset == 0 {
+			n := strings.TrimPrefix(selector, "offset:")
+			if n == "" {
+				p.skipSpace()
+				n = p.word()
+			}
+			offset, err := strconv.Atoi(n)
+			if err != nil || offset < 0 {
+				p.pos = start
+				return p.errorf("invalid offset %q", n)
+			}
+			arg.Offset = offset
+			continue
+		}
+		if selector == "" {
+			return p.errorf("expected a selector, found %q", p.peek())
+		}
+		if err := validSelector(arg.Type, selector); err != nil {
+			p.pos = start
+			return p.errorf("%s", err)
+		}
+		if seen[selector] {
+			p.pos = start
+			return p.errorf("duplicate selector %s", selector)
+		}
+		seen[selector] = true
+		if err := p.expect('{'); err != nil {
+			return err
+		}
+		msg, err := p.message(plurals)
+		if err != nil {
+			return err
+		}
+		if err := p.expect('}'); err != nil {
+			return err
+		}
+		arg.Options = append(arg.Options, Option{Selector: selector, Message: msg})
+	}
+	if len(arg.Options) == 0 {
+		return p.errorf("%s argument %s has no options", arg.Type, arg.Name)
+	}
+	return nil
+}
+
+func validSelector(typ, selector string) error {
+	if typ == TypeSelect {
+		if !validName(selector) {
+			return fmt.Errorf("invalid selector %q", selector)
+		}
+		return nil
+	}
+	if strings.HasPrefix(selector, "=") {
+		if _, err := strconv.ParseFloat(selector[1:], 64); err != nil {
+			return fmt.Errorf("invalid exact value %q", selector)
+		}
+		return nil
+	}
+	switch selector {
+	case "zero", "one", "two", "few", "many", "other":
+		return nil
+	}
+	return fmt.Errorf("invalid plural category %q", selector)
+}
+
+// validName accepts ICU argument names and numbers
+func validName(name string) bool {
+	if name == "" {
+		return false
+	}
+	for _, r := range name {
+		if !(r == '_' || r == '-' || unicode.IsLetter(r) || unicode.IsDigit(r)) {
+			return false
+		}
+	}
+	return true
+}
+
+// String formats the message in ICU syntax, quoting literal braces,
+// apostrophes and the # of plural sub-messages. Parsing the result returns
+// the message, except for the positions of its arguments.
+func (m Message) String() string {
+	var b strings.Builder
+	m.format(&b, false)
+	return b.String()
+}
+
+func (m Message) format(b *strings.Builder, plural bool) {
+	for _, n := range m {
+		switch n := n.(type) {
+		case Text:
+			for _, r := range string(n) {
+				switch {
+				case r == '\'':
+					b.WriteString("''")
+				case r == '{' || r == '}' || r == '#' && plural:
+					b.WriteString("'" + string(r) + "'")
+				default:
+					b.WriteRune(r)
+				}
+			}
+		case Pound:
+			b.WriteByte('#')
+		case *Argument:
+			n.format(b, plural)
+		}
+	}
+}
+
+func (a *Argument) format(b *strings.Builder, plural bool) {
+	b.WriteString("{" + a.Name)
+	if a.Type != "" {
+		b.WriteString(", " + a.Type)
+	}
+	if a.Style != "" {
+		b.WriteString(", " + a.Style)
+	}
+	if a.Options != nil {
+		b.WriteString(",")
+		if a.Offset != 0 {
+			b.WriteString(" offset:" + strconv.Itoa(a.Offset))
+		}
+		for _, o := range a.Options {
+			b.WriteString(" " + o.Selector + " {")
+			o.Message.format(b, plural || a.Type != TypeSelect)
+			b.WriteString("}")
+		}
+	}
+	b.WriteString("}")
+}
+
+// Arguments returns the sorted names of the arguments of the message,
+// including those of sub-messages
+func (m Message) Arguments() []string {
+	seen := make(map[string]bool)
+	m.walk(func(a *Argument) {
+		seen[a.Name] = true
+	})
+	names := make([]string, 0, len(seen))
+	for name := range seen {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// walk calls f for every argument of the message and its sub-messages
+func (m Message) walk(f func(a *Argument)) {
+	for _, n := range m {
+		a, ok := n.(*Argument)
+		if !ok {
+			continue
+		}
+		f(a)
+		for _, o := range a.Options {
+			o.Message.walk(f)
+		}
+	}
+}
@@ -0,0 +1,127 @@
+package icu_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/blacksails/poeditor"
+	"github.com/blacksails/poeditor/icu"
+)
+
+func TestParse(t *testing.T) {
+	got, err := icu.Parse("Hi {name}, it''s '{literal}' {n, number, ::currency/EUR} " +
+		"{count, plural, offset:1 =0 {none} one {# {gender, select, female {her} other {his}} file} other {# files}}")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := icu.Message{
+		icu.Text("Hi "),
+		&icu.Argument{Name: "name", Pos: 3},
+		icu.Text(", it's {literal} "),
+		&icu.Argument{Name: "n", Type: "number", Style: "::currency/EUR", Pos: 29},
+		icu.Text(" "),
+		&icu.Argument{Name: "count", Type: icu.TypePlural, Offset: 1, Pos: 57, Options: []icu.Option{
+			{Selector: "=0", Message: icu.Message{icu.Text("none")}},
+			{Selector: "one", Message: icu.Message{
+				icu.Pound{},
+				icu.Text(" "),
+				&icu.Argument{Name: "gender", Type: icu.TypeSelect, Pos: 99, Options: []icu.Option{
+					{Selector: "female", Message: icu.Message{icu.Text("her")}},
+					{Selector: "other", Message: icu.Message{icu.Text("his")}},
+				}},
+				icu.Text(" file"),
+			}},
+			{Selector: "other", Message: icu.Message{icu.Pound{}, icu.Text(" files")}},
+		}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("\nExpected %#v\nGot      %#v", want, got)
+	}
+	if args := got.Arguments(); !reflect.DeepEqual(args, []string{"count", "gender", "n", "name"}) {
+		t.Errorf("Unexpected arguments %q", args)
+	}
+}
+
+func TestMessageString(t *testing.T) {
+	for _, s := range []string{
+		"Hi {name}, it''s '{'literal'}' {n, number, ::currency/EUR}",
+		"{count, plural, offset:1 =0 {none} one {# '#'1 {gender, select, female {her} other {his}}} other {# files}}",
+		"{place, selectordinal, one {#st} other {#th}} # {g, select, a {#} other {b}}",
+	} {
+		msg, err := icu.Parse(s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := msg.String(); got != s {
+			t.Errorf("\nExpected %s\nGot      %s", s, got)
+		}
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	for _, s := range []string{
+		"{",
+		"a}",
+		"{a b}",
+		"{n, plural}",
+		"{n, plural, some {x}}",
+		"{n, plural, one {x} one {y}}",
+		"{n, select, a {x}",
+		"{n, number",
+		"{, number}",
+	} {
+		if _, err := icu.Parse(s); err == nil {
+			t.Errorf("%q: expected a syntax error", s)
+		}
+	}
+}
+
+func TestValidator(t *testing.T) {
+	term := func(content interface{}) poeditor.TermTranslation {
+		var t poeditor.TermTranslation
+		t.Term = "files"
+		t.Translation.Content = content
+		return t
+	}
+	var source poeditor.TermTranslated
+	source.Term.Term = "files"
+	source.Translation.Content = "{count, plural, one {# file in {dir}} other {# files in {dir}}}"
+	v := icu.NewValidator([]poeditor.TermTranslated{source})
+	tests := []struct {
+		lang    string
+		content interface{}
+		want    []string
+	}{
+		{"de", "{count, plural, one {# Datei in {dir}} other {# Dateien in {dir}}}", nil},
+		{"ru", "{count, plural, one {# файл в {dir}} other {# файлов в {dir}}}", []string{
+			"plural argument count misses the few, many categories of ru",
+		}},
+		{"de", "{count, plural, one {# Datei in {folder}}}", []string{
+			"plural argument count has no other branch",
+			"missing argument dir",
+			"unknown argument folder",
+		}},
+		{"de", "{count, plural, one {# Datei}", []string{"icu: offset 29: expected }, found end of message"}},
+		{"de", poeditor.Plural{One: "{dir}: eine Datei", Other: "{dir}: # Dateien"}, []string{"missing argument count", "missing argument count"}},
+	}
+	for _, test := range tests {
+		got := v.Validate(test.lang, term(test.content))
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("%v: expected %q, got %q", test.content, test.want, got)
+		}
+	}
+	if err := v.ValidateUpdate("de", []poeditor.TermTranslation{term("{count, select, x {y}}")}); err == nil {
+		t.Error("Expected ValidateUpdate to fail")
+	}
+
+	// Plural sources are compared form by form, falling back to other
+	var plural poeditor.TermTranslated
+	plural.Term.Term = "files"
+	plural.Translation.Content = poeditor.Plural{One: "{n} file", Other: "{n} files in {dir}"}
+	v = icu.NewValidator([]poeditor.TermTranslated{plural})
+	got := v.Validate("de", term(poeditor.Plural{One: "{m} Datei", Few: "{n} Dateien", Other: "{n} Dateien in {dir}"}))
+	want := []string{"missing argument n", "unknown argument m", "missing argument dir"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected %q, got %q", want, got)
+	}
+}
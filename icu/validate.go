@@ -0,0 +1,151 @@
+package icu
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/blacksails/poeditor"
+	"github.com/blacksails/poeditor/internal/cldr"
+)
+
+// Check returns the problems of a parsed message in a language: plural
+// arguments missing CLDR categories of the language, and plural, select and
+// selectordinal arguments without an other branch. Exact values such as =1
+// do not count as categories.
+func Check(lang string, m Message) []string {
+	var problems []string
+	m.walk(func(a *Argument) {
+		if a.Options == nil {
+			return
+		}
+		selectors := make(map[string]bool)
+		for _, o := range a.Options {
+			selectors[o.Selector] = true
+		}
+		if !selectors[cldr.Other] {
+			problems = append(problems, fmt.Sprintf("%s argument %s has no other branch", a.Type, a.Name))
+		}
+		if a.Type != TypePlural {
+			return
+		}
+		var missing []string
+		for _, c := range cldr.Categories(lang) {
+			if !selectors[c] && c != cldr.Other {
+				missing = append(missing, c)
+			}
+		}
+		if len(missing) > 0 {
+			problems = append(problems, fmt.Sprintf("plural argument %s misses the %s categories of %s",
+				a.Name, strings.Join(missing, ", "), lang))
+		}
+	})
+	return problems
+}
+
+// CompareArguments returns problems for arguments of the translation missing
+// in the source and for arguments of the source missing in the translation
+func CompareArguments(source, translation Message) []string {
+	src, tr := source.Arguments(), translation.Arguments()
+	var problems []string
+	for _, name := range src {
+		if !contains(tr, name) {
+			problems = append(problems, "missing argument "+name)
+		}
+	}
+	for _, name := range tr {
+		if !contains(src, name) {
+			problems = append(problems, "unknown argument "+name)
+		}
+	}
+	return problems
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+// Validator checks the ICU messages of translations. Plural translations are
+// checked form by form.
+type Validator struct {
+	// source holds the parsed forms of the source terms by category, with
+	// strings as the other form
+	source map[poeditor.TermBase]map[string]Message
+}
+
+// NewValidator returns a validator comparing the arguments of translations
+// with those of the source terms. Each form of a plural translation is
+// compared with the same form of the source, or with its other form. Source
+// messages which do not parse are ignored, and source may be nil to only
+// check translations on their own.
+func NewValidator(source []poeditor.TermTranslated) *Validator {
+	v := &Validator{source: make(map[poeditor.TermBase]map[string]Message)}
+	for _, t := range source {
+		forms := make(map[string]Message)
+		for _, f := range contentForms(t.Translation.Content) {
+			if m, err := Parse(f.Text); err == nil {
+				forms[f.Category] = m
+			}
+		}
+		if len(forms) > 0 {
+			v.source[t.TermBase] = forms
+		}
+	}
+	return v
+}
+
+// contentForms returns the forms of translation content, a string being the
+// other form
+func contentForms(c interface{}) []poeditor.PluralForm {
+	switch c := c.(type) {
+	case string:
+		if c != "" {
+			return []poeditor.PluralForm{{Category: cldr.Other, Text: c}}
+		}
+	case poeditor.Plural:
+		return c.Forms()
+	}
+	return nil
+}
+
+// Validate returns the problems of a translation in a language
+func (v *Validator) Validate(language string, t poeditor.TermTranslation) []string {
+	var problems []string
+	for _, f := range contentForms(t.Translation.Content) {
+		m, err := Parse(f.Text)
+		if err != nil {
+			problems = append(problems, err.Error())
+			continue
+		}
+		problems = append(problems, Check(language, m)...)
+		src, ok := v.source[t.TermBase][f.Category]
+		if !ok {
+			src, ok = v.source[t.TermBase][cldr.Other]
+		}
+		if ok {
+			problems = append(problems, CompareArguments(src, m)...)
+		}
+	}
+	return problems
+}
+
+// ValidateUpdate validates the translations before they are passed to
+// Language.Update, returning a *poeditor.ValidationError listing the problems
+// of every term. Registering Validate with Project.AddTranslationValidator
+// runs the same checks on every update.
+func (v *Validator) ValidateUpdate(language string, terms []poeditor.TermTranslation) error {
+	var violations []poeditor.Violation
+	for _, t := range terms {
+		for _, p := range v.Validate(language, t) {
+			violations = append(violations, poeditor.Violation{TermBase: t.TermBase, Language: language, Message: p})
+		}
+	}
+	if violations == nil {
+		return nil
+	}
+	return &poeditor.ValidationError{Violations: violations}
+}
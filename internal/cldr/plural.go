@@ -0,0 +1,379 @@
+// Package cldr implements the CLDR cardinal plural rules of common languages.
+//
+// See https://unicode.org/reports/tr35/tr35-numbers.html#Language_Plural_Rules
+// for the definition of the operands and rules.
+package cldr
+
+import (
+	"errors"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Plural categories
+const (
+	Zero  = "zero"
+	One   = "one"
+	Two   = "two"
+	Few   = "few"
+	Many  = "many"
+	Other = "other"
+)
+
+// Operands are the operands of a number used by plural rules
+type Operands struct {
+	// N is the absolute value of the number
+	N float64
+	// I is the integer digits of N
+	I int64
+	// V is the number of visible fraction digits, with trailing zeros
+	V int
+	// W is the number of visible fraction digits, without trailing zeros
+	W int
+	// F is the visible fraction digits, with trailing zeros
+	F int64
+	// T is the visible fraction digits, without trailing zeros
+	T int64
+}
+
+// ParseOperands parses the operands of a decimal number such as 1 or 1.50
+func ParseOperands(s string) (Operands, error) {
+	var o Operands
+	s = strings.TrimPrefix(s, "-")
+	n, err := strconv.ParseFloat(s, 64)
+	if err != nil || math.IsInf(n, 0) || math.IsNaN(n) || strings.ContainsAny(s, "eEpPxX_") {
+		return o, errors.New("cldr: invalid number " + strconv.Quote(s))
+	}
+	o.N = n
+	integer, fraction := s, ""
+	if i := strings.IndexByte(s, '.'); i >= 0 {
+		integer, fraction = s[:i], s[i+1:]
+	}
+	if integer != "" {
+		if o.I, err = strconv.ParseInt(integer, 10, 64); err != nil {
+			// Too large for the integer operand, only N is exact enough
+			o.I = math.MaxInt64
+		}
+	}
+	o.V = len(fraction)
+	if o.V > 0 {
+		o.F, _ = strconv.ParseInt(fraction, 10, 64)
+	}
+	trimmed := strings.TrimRight(fraction, "0")
+	o.W = len(trimmed)
+	if o.W > 0 {
+		o.T, _ = strconv.ParseInt(trimmed, 10, 64)
+	}
+	return o, nil
+}
+
+// IntOperands returns the operands of an integer
+func IntOperands(n int64) Operands {
+	if n < 0 {
+		n = -n
+	}
+	return Operands{N: float64(n), I: n}
+}
+
+// rule is a set of plural categories and a function choosing among them.
+// gettext is the C expression of the rule for integers, as used in the
+// Plural-Forms header of gettext files.
+type rule struct {
+	categories []string
+	gettext    string
+	choose     func(o Operands) string
+}
+
+var rules = map[string]rule{}
+
+func register(r rule, langs ...string) {
+	for _, l := range langs {
+		rules[l] = r
+	}
+}
+
+func init() {
+	register(rule{[]string{Other}, "0", func(o Operands) string { return Other }},
+		"bo", "dz", "id", "ig", "ii", "ja", "jv", "km", "ko", "lo", "ms", "my", "sah",
+		"th", "to", "vi", "wo", "yo", "yue", "zh")
+	// one: i = 1 and v = 0
+	register(rule{[]string{One, Other}, "n != 1", func(o Operands) string {
+		if o.I == 1 && o.V == 0 {
+			return One
+		}
+		return Other
+	}}, "ast", "ca", "de", "en", "et", "fi", "fy", "gl", "ia", "io", "it", "lij",
+		"nl", "sc", "sv", "sw", "ur", "yi", "pt-pt")
+	// one: n = 1
+	register(rule{[]string{One, Other}, "n != 1", func(o Operands) string {
+		if o.N == 1 {
+			return One
+		}
+		return Other
+	}}, "af", "az", "bg", "el", "eo", "es", "eu", "fo", "ha", "hu", "ka", "kk", "ky",
+		"lb", "ml", "mn", "nb", "ne", "nn", "no", "om", "or", "ps", "sq", "ta", "te",
+		"tk", "tr", "ug", "uz")
+	// one: i = 0,1
+	register(rule{[]string{One, Other}, "n > 1", func(o Operands) string {
+		if o.I == 0 || o.I == 1 {
+			return One
+		}
+		return Other
+	}}, "fr", "ff", "hy", "kab", "pt")
+	// one: i = 0 or n = 1
+	register(rule{[]string{One, Other}, "n > 1", func(o Operands) string {
+		if o.I == 0 || o.N == 1 {
+			return One
+		}
+		return Other
+	}}, "am", "as", "bn", "fa", "gu", "hi", "kn", "mr", "zu")
+	// one: n = 1 or t != 0 and i = 0,1
+	register(rule{[]string{One, Other}, "n != 1", func(o Operands) string {
+		if o.N == 1 || o.T != 0 && (o.I == 0 || o.I == 1) {
+			return One
+		}
+		return Other
+	}}, "da")
+	// one: t = 0 and i % 10 = 1 and i % 100 != 11 or t % 10 = 1 and t % 100 != 11
+	register(rule{[]string{One, Other}, "n % 10 != 1 || n % 100 == 11", func(o Operands) string {
+		if o.T == 0 && o.I%10 == 1 && o.I%100 != 11 || o.T%10 == 1 && o.T%100 != 11 {
+			return One
+		}
+		return Other
+	}}, "is")
+	// one: v = 0 and i % 10 = 1 and i % 100 != 11 or f % 10 = 1 and f % 100 != 11
+	register(rule{[]string{One, Other}, "n % 10 != 1 || n % 100 == 11", func(o Operands) string {
+		if o.V == 0 && o.I%10 == 1 && o.I%100 != 11 || o.F%10 == 1 && o.F%100 != 11 {
+			return One
+		}
+		return Other
+	}}, "mk")
+	register(rule{[]string{One, Few, Many, Other}, "n % 10 == 1 && n % 100 != 11 ? 0 : n % 10 >= 2 && n % 10 <= 4 && (n % 100 < 12 || n % 100 > 14) ? 1 : 2", slavicEast}, "be", "ru", "uk")
+	register(rule{[]string{One, Few, Other}, "n % 10 == 1 && n % 100 != 11 ? 0 : n % 10 >= 2 && n % 10 <= 4 && (n % 100 < 12 || n % 100 > 14) ? 1 : 2", slavicSouth}, "bs", "hr", "sh", "sr")
+	register(rule{[]string{One, Few, Many, Other}, "n == 1 ? 0 : n >= 2 && n <= 4 ? 1 : 2", func(o Operands) string {
+		switch {
+		case o.I == 1 && o.V == 0:
+			return One
+		case o.I >= 2 && o.I <= 4 && o.V == 0:
+			return Few
+		case o.V != 0:
+			return Many
+		}
+		return Other
+	}}, "cs", "sk")
+	register(rule{[]string{One, Few, Many, Other}, "n == 1 ? 0 : n % 10 >= 2 && n % 10 <= 4 && (n % 100 < 12 || n % 100 > 14) ? 1 : 2", func(o Operands) string {
+		i10, i100 := o.I%10, o.I%100
+		switch {
+		case o.V != 0:
+			return Other
+		case o.I == 1:
+			return One
+		case i10 >= 2 && i10 <= 4 && (i100 < 12 || i100 > 14):
+			return Few
+		}
+		return Many
+	}}, "pl")
+	register(rule{[]string{One, Few, Many, Other}, "n % 10 == 1 && (n % 100 < 11 || n % 100 > 19) ? 0 : n % 10 >= 2 && (n % 100 < 11 || n % 100 > 19) ? 1 : 2", func(o Operands) string {
+		n10, n100 := math.Mod(o.N, 10), math.Mod(o.N, 100)
+		switch {
+		case n10 == 1 && (n100 < 11 || n100 > 19):
+			return One
+		case n10 >= 2 && n10 <= 9 && n10 == math.Trunc(n10) && (n100 < 11 || n100 > 19):
+			return Few
+		case o.F != 0:
+			return Many
+		}
+		return Other
+	}}, "lt")
+	register(rule{[]string{Zero, One, Other}, "n % 10 == 0 || n % 100 >= 11 && n % 100 <= 19 ? 0 : n % 10 == 1 && n % 100 != 11 ? 1 : 2", func(o Operands) string {
+		n10, n100 := math.Mod(o.N, 10), math.Mod(o.N, 100)
+		f10, f100 := o.F%10, o.F%100
+		switch {
+		case n10 == 0 || n100 >= 11 && n100 <= 19 && n100 == math.Trunc(n100) ||
+			o.V == 2 && f100 >= 11 && f100 <= 19:
+			return Zero
+		case n10 == 1 && n100 != 11 || o.V == 2 && f10 == 1 && f100 != 11 || o.V != 2 && f10 == 1:
+			return One
+		}
+		return Other
+	}}, "lv")
+	register(rule{[]string{One, Few, Other}, "n == 1 ? 0 : n == 0 || n % 100 >= 2 && n % 100 <= 19 ? 1 : 2", func(o Operands) string {
+		n100 := math.Mod(o.N, 100)
+		switch {
+		case o.I == 1 && o.V == 0:
+			return One
+		case o.V != 0 || o.N == 0 || n100 >= 2 && n100 <= 19 && n100 == math.Trunc(n100):
+			return Few
+		}
+		return Other
+	}}, "ro", "mo")
+	register(rule{[]string{One, Two, Few, Other}, "n % 100 == 1 ? 0 : n % 100 == 2 ? 1 : n % 100 == 3 || n % 100 == 4 ? 2 : 3", func(o Operands) string {
+		i100 := o.I % 100
+		switch {
+		case o.V == 0 && i100 == 1:
+			return One
+		case o.V == 0 && i100 == 2:
+			return Two
+		case o.V == 0 && (i100 == 3 || i100 == 4) || o.V != 0:
+			return Few
+		}
+		return Other
+	}}, "sl")
+	register(rule{[]string{One, Two, Other}, "n == 1 ? 0 : n == 2 ? 1 : 2", func(o Operands) string {
+		switch {
+		case o.I == 1 && o.V == 0 || o.I == 0 && o.V != 0:
+			return One
+		case o.I == 2 && o.V == 0:
+			return Two
+		}
+		return Other
+	}}, "he", "iw")
+	register(rule{[]string{Zero, One, Two, Few, Many, Other}, "n == 0 ? 0 : n == 1 ? 1 : n == 2 ? 2 : n % 100 >= 3 && n % 100 <= 10 ? 3 : n % 100 >= 11 ? 4 : 5", func(o Operands) string {
+		n100 := math.Mod(o.N, 100)
+		whole := n100 == math.Trunc(n100)
+		switch {
+		case o.N == 0:
+			return Zero
+		case o.N == 1:
+			return One
+		case o.N == 2:
+			return Two
+		case whole && n100 >= 3 && n100 <= 10:
+			return Few
+		case whole && n100 >= 11 && n100 <= 99:
+			return Many
+		}
+		return Other
+	}}, "ar", "ars")
+	register(rule{[]string{One, Two, Few, Many, Other}, "n == 1 ? 0 : n == 2 ? 1 : n >= 3 && n <= 6 ? 2 : n >= 7 && n <= 10 ? 3 : 4", func(o Operands) string {
+		switch {
+		case o.N == 1:
+			return One
+		case o.N == 2:
+			return Two
+		case o.N >= 3 && o.N <= 6 && o.N == math.Trunc(o.N):
+			return Few
+		case o.N >= 7 && o.N <= 10 && o.N == math.Trunc(o.N):
+			return Many
+		}
+		return Other
+	}}, "ga")
+	register(rule{[]string{Zero, One, Two, Few, Many, Other}, "n == 0 ? 0 : n == 1 ? 1 : n == 2 ? 2 : n == 3 ? 3 : n == 6 ? 4 : 5", func(o Operands) string {
+		switch o.N {
+		case 0:
+			return Zero
+		case 1:
+			return One
+		case 2:
+			return Two
+		case 3:
+			return Few
+		case 6:
+			return Many
+		}
+		return Other
+	}}, "cy")
+}
+
+// slavicEast is the rule of Russian, Ukrainian and Belarusian
+func slavicEast(o Operands) string {
+	i10, i100 := o.I%10, o.I%100
+	switch {
+	case o.V != 0:
+		return Other
+	case i10 == 1 && i100 != 11:
+		return One
+	case i10 >= 2 && i10 <= 4 && (i100 < 12 || i100 > 14):
+		return Few
+	}
+	return Many
+}
+
+// slavicSouth is the rule of Croatian, Serbian and Bosnian
+func slavicSouth(o Operands) string {
+	i10, i100 := o.I%10, o.I%100
+	f10, f100 := o.F%10, o.F%100
+	switch {
+	case o.V == 0 && i10 == 1 && i100 != 11 || f10 == 1 && f100 != 11:
+		return One
+	case o.V == 0 && i10 >= 2 && i10 <= 4 && (i100 < 12 || i100 > 14) ||
+		f10 >= 2 && f10 <= 4 && (f100 < 12 || f100 > 14):
+		return Few
+	}
+	return Other
+}
+
+// lookup finds the rule of a language tag such as pt-BR, trying the full tag
+// before the base language. Unknown languages use the English rule.
+func lookup(lang string) rule {
+	lang = strings.ToLower(strings.Replace(lang, "_", "-", -1))
+	for {
+		if r, ok := rules[lang]; ok {
+			return r
+		}
+		i := strings.LastIndexByte(lang, '-')
+		if i < 0 {
+			return rules["en"]
+		}
+		lang = lang[:i]
+	}
+}
+
+// Known reports whether the plural rule of the language is known
+func Known(lang string) bool {
+	lang = strings.ToLower(strings.Replace(lang, "_", "-", -1))
+	if i := strings.IndexByte(lang, '-'); i >= 0 {
+		lang = lang[:i]
+	}
+	_, ok := rules[lang]
+	return ok
+}
+
+// Categories returns the plural categories used by the language, in CLDR
+// order
+func Categories(lang string) []string {
+	return append([]string(nil), lookup(lang).categories...)
+}
+
+// Plural returns the plural category of a number in the language
+func Plural(lang string, o Operands) string {
+	return lookup(lang).choose(o)
+}
+
+// SameCategory reports whether the category covers the same integers in both
+// languages. The English one is only 1, so it matches the German one but not
+// the French one, which is also 0, or the Russian one, which is also 21.
+func SameCategory(a, b, category string) bool {
+	ra, rb := lookup(a), lookup(b)
+	found := false
+	// Integer rules repeat after 100, apart from small numbers
+	for n := int64(0); n < 1000; n++ {
+		o := IntOperands(n)
+		ca, cb := ra.choose(o), rb.choose(o)
+		if (ca == category) != (cb == category) {
+			return false
+		}
+		found = found || ca == category
+	}
+	return found
+}
+
+// Gettext returns the plural forms of the language in gettext files: the
+// categories used by integers in CLDR order, indexed by msgstr[n], and the C
+// expression choosing the index of a number for the Plural-Forms header
+func Gettext(lang string) ([]string, string) {
+	r := lookup(lang)
+	used := make(map[string]bool)
+	// Integer rules repeat after 100, apart from small numbers
+	for n := int64(0); n < 1000; n++ {
+		used[r.choose(IntOperands(n))] = true
+	}
+	var categories []string
+	for _, c := range r.categories {
+		if used[c] {
+			categories = append(categories, c)
+		}
+	}
+	return categories, r.gettext
+}
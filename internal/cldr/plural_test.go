@@ -0,0 +1,234 @@
+package cldr_test
+
+import (
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/blacksails/poeditor/internal/cldr"
+)
+
+func TestPlural(t *testing.T) {
+	tests := []struct {
+		lang   string
+		number string
+		want   string
+	}{
+		{"en", "1", cldr.One},
+		{"en", "1.0", cldr.Other},
+		{"en", "2", cldr.Other},
+		{"fr", "0", cldr.One},
+		{"fr", "1.5", cldr.One},
+		{"pt-BR", "0", cldr.One},
+		{"pt_PT", "0", cldr.Other},
+		{"ru", "21", cldr.One},
+		{"ru", "11", cldr.Many},
+		{"ru", "23", cldr.Few},
+		{"ru", "1.5", cldr.Other},
+		{"pl", "22", cldr.Few},
+		{"pl", "12", cldr.Many},
+		{"cs", "3", cldr.Few},
+		{"cs", "0.5", cldr.Many},
+		{"ar", "0", cldr.Zero},
+		{"ar", "2", cldr.Two},
+		{"ar", "103", cldr.Few},
+		{"ar", "111", cldr.Many},
+		{"ar", "100", cldr.Other},
+		{"ja", "1", cldr.Other},
+		{"lv", "10", cldr.Zero},
+		{"lt", "12", cldr.Other},
+		{"xx", "1", cldr.One},
+	}
+	for _, test := range tests {
+		o, err := cldr.ParseOperands(test.number)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %s", test.number, err)
+		}
+		if got := cldr.Plural(test.lang, o); got != test.want {
+			t.Errorf("%s %s: expected %s, got %s", test.lang, test.number, test.want, got)
+		}
+	}
+}
+
+func TestParseOperands(t *testing.T) {
+	o, err := cldr.ParseOperands("-1.50")
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	want := cldr.Operands{N: 1.5, I: 1, V: 2, W: 1, F: 50, T: 5}
+	if o != want {
+		t.Errorf("\nExpected %+v\nGot      %+v", want, o)
+	}
+	if _, err := cldr.ParseOperands("1e3"); err == nil {
+		t.Errorf("Expected an error")
+	}
+}
+
+func TestCategories(t *testing.T) {
+	if got := cldr.Categories("uk-UA"); !reflect.DeepEqual(got, []string{"one", "few", "many", "other"}) {
+		t.Errorf("Unexpected categories %v", got)
+	}
+}
+
+func TestSameCategory(t *testing.T) {
+	tests := []struct {
+		a, b, category string
+		want           bool
+	}{
+		{"en", "de", "one", true},
+		{"en", "es", "one", true},
+		{"en", "fr", "one", false},
+		{"en", "ru", "one", false},
+		{"ru", "uk", "few", true},
+		{"en", "lv", "zero", false},
+		{"ja", "ko", "other", true},
+		{"en", "de", "few", false},
+	}
+	for _, test := range tests {
+		if got := cldr.SameCategory(test.a, test.b, test.category); got != test.want {
+			t.Errorf("%s %s %s: expected %v, got %v", test.a, test.b, test.category, test.want, got)
+		}
+	}
+}
+
+func TestGettext(t *testing.T) {
+	tests := []struct {
+		lang       string
+		categories []string
+	}{
+		{"ja", []string{"other"}},
+		{"en", []string{"one", "other"}},
+		{"es", []string{"one", "other"}},
+		{"pt-BR", []string{"one", "other"}},
+		{"hi", []string{"one", "other"}},
+		{"da", []string{"one", "other"}},
+		{"is", []string{"one", "other"}},
+		{"mk", []string{"one", "other"}},
+		{"ru", []string{"one", "few", "many"}},
+		{"hr", []string{"one", "few", "other"}},
+		{"cs", []string{"one", "few", "other"}},
+		{"pl", []string{"one", "few", "many"}},
+		{"lt", []string{"one", "few", "other"}},
+		{"lv", []string{"zero", "one", "other"}},
+		{"ro", []string{"one", "few", "other"}},
+		{"sl", []string{"one", "two", "few", "other"}},
+		{"he", []string{"one", "two", "other"}},
+		{"ar", []string{"zero", "one", "two", "few", "many", "other"}},
+		{"ga", []string{"one", "two", "few", "many", "other"}},
+		{"cy", []string{"zero", "one", "two", "few", "many", "other"}},
+	}
+	for _, test := range tests {
+		categories, expr := cldr.Gettext(test.lang)
+		if !reflect.DeepEqual(categories, test.categories) {
+			t.Errorf("%s: expected categories %v, got %v", test.lang, test.categories, categories)
+			continue
+		}
+		// The expression must choose the index of the CLDR category
+		for n := int64(0); n <= 1000; n++ {
+			i := evalC(t, expr, n)
+			want := cldr.Plural(test.lang, cldr.IntOperands(n))
+			if i < 0 || int(i) >= len(categories) || categories[i] != want {
+				t.Errorf("%s: %s with n = %d is %d, expected %s", test.lang, expr, n, i, want)
+				break
+			}
+		}
+	}
+}
+
+// evalC evaluates a gettext plural expression. Boolean results are 0 or 1.
+func evalC(t *testing.T, expr string, n int64) int64 {
+	t.Helper()
+	tokens := strings.Fields(strings.NewReplacer("(", " ( ", ")", " ) ", "?", " ? ", ":", " : ").Replace(expr))
+	pos := 0
+	peek := func() string {
+		if pos < len(tokens) {
+			return tokens[pos]
+		}
+		return ""
+	}
+	b := func(v bool) int64 {
+		if v {
+			return 1
+		}
+		return 0
+	}
+	var ternary func() int64
+	primary := func() int64 {
+		tok := peek()
+		pos++
+		switch tok {
+		case "(":
+			v := ternary()
+			pos++
+			return v
+		case "n":
+			return n
+		}
+		v, err := strconv.ParseInt(tok, 10, 64)
+		if err != nil {
+			t.Fatalf("%s: unexpected %q", expr, tok)
+		}
+		return v
+	}
+	// Binary operators by increasing precedence
+	levels := [][]string{{"||"}, {"&&"}, {"==", "!="}, {"<", "<=", ">", ">="}, {"%"}}
+	var binary func(level int) int64
+	binary = func(level int) int64 {
+		if level == len(levels) {
+			return primary()
+		}
+		v := binary(level + 1)
+		for {
+			op := peek()
+			found := false
+			for _, o := range levels[level] {
+				found = found || o == op
+			}
+			if !found {
+				return v
+			}
+			pos++
+			w := binary(level + 1)
+			switch op {
+			case "||":
+				v = b(v != 0 || w != 0)
+			case "&&":
+				v = b(v != 0 && w != 0)
+			case "==":
+				v = b(v == w)
+			case "!=":
+				v = b(v != w)
+			case "<":
+				v = b(v < w)
+			case "<=":
+				v = b(v <= w)
+			case ">":
+				v = b(v > w)
+			case ">=":
+				v = b(v >= w)
+			case "%":
+				v = v % w
+			}
+		}
+	}
+	ternary = func() int64 {
+		cond := binary(0)
+		if peek() != "?" {
+			return cond
+		}
+		pos++
+		a := ternary()
+		pos++
+		c := ternary()
+		if cond != 0 {
+			return a
+		}
+		return c
+	}
+	v := ternary()
+	if pos != len(tokens) {
+		t.Fatalf("%s: unexpected %q", expr, peek())
+	}
+	return v
+}
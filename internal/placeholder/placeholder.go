@@ -0,0 +1,277 @@
+// Package placeholder finds the placeholders of translation strings: printf
+// verbs such as %s, %[2]d or %.2f, including the positional %1$s and the
+// %@ of Apple platforms, named placeholders in braces such as {name} or {0},
+// and template actions such as {{.Count}}. Protected also
+// finds the markup translations must keep.
+package placeholder
+
+import (
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Kinds of placeholders
+const (
+	// Printf is a fmt verb
+	Printf = "printf"
+	// Named is a name or number in braces
+	Named = "named"
+	// Template is a template action in double braces
+	Template = "template"
+)
+
+// Placeholder is a placeholder found in a string
+type Placeholder struct {
+	Kind string
+	// Text is the placeholder as written, e.g. %[1]d or {name}
+	Text string
+	// Start and End are the byte offsets of Text
+	Start, End int
+	// Verb is the fmt verb of printf placeholders
+	Verb byte
+	// Index is the argument a printf placeholder consumes, counted from 1
+	Index int
+	// Stars are the arguments consumed by star widths and precisions
+	Stars []int
+	// Name is the content of a named placeholder, or the trimmed action of
+	// a template placeholder
+	Name string
+}
+
+// Parse returns the placeholders of s in order. A literal %% is not a
+// placeholder.
+func Parse(s string) []Placeholder {
+	var (
+		ps  []Placeholder
+		arg = 1
+	)
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '%':
+			p, end, next := parsePrintf(s, i, arg)
+			if end < 0 {
+				continue
+			}
+			arg = next
+			if p.Verb != '%' {
+				ps = append(ps, p)
+			}
+			i = end - 1
+		case '{':
+			if strings.HasPrefix(s[i:], "{{") {
+				end := strings.Index(s[i:], "}}")
+				if end < 0 {
+					continue
+				}
+				action := strings.TrimSpace(s[i+2 : i+end])
+				ps = append(ps, Placeholder{Kind: Template, Text: s[i : i+end+2], Start: i, End: i + end + 2, Name: action})
+				i += end + 1
+				continue
+			}
+			end := strings.IndexByte(s[i:], '}')
+			if end < 0 {
+				continue
+			}
+			name := s[i+1 : i+end]
+			if !validName(name) {
+				continue
+			}
+			ps = append(ps, Placeholder{Kind: Named, Text: s[i : i+end+1], Start: i, End: i + end + 1, Name: name})
+			i += end
+		}
+	}
+	return ps
+}
+
+// parsePrintf parses the verb at s[start], which is a percent sign. It
+// returns the placeholder, its end offset or -1 when it is malformed, and the
+// next implicit argument index.
+func parsePrintf(s string, start, arg int) (Placeholder, int, int) {
+	var stars []int
+	i := start + 1
+	// A positional argument such as %2$s
+	if n, end, ok := parsePosition(s, i); ok {
+		arg, i = n, end
+	}
+	for i < len(s) && strings.IndexByte("+-# 0", s[i]) >= 0 {
+		i++
+	}
+	// Width and precision, each an explicit index, a star or digits
+	for part := 0; part < 2; part++ {
+		if part == 1 {
+			if i >= len(s) || s[i] != '.' {
+				break
+			}
+			i++
+		}
+		if n, end, ok := parseIndex(s, i); ok {
+			arg, i = n, end
+		}
+		switch {
+		case i < len(s) && s[i] == '*':
+			stars = append(stars, arg)
+			arg++
+			i++
+		default:
+			for i < len(s) && s[i] >= '0' && s[i] <= '9' {
+				i++
+			}
+		}
+	}
+	if n, end, ok := parseIndex(s, i); ok {
+		arg, i = n, end
+	}
+	if i >= len(s) {
+		return Placeholder{}, -1, arg
+	}
+	verb := s[i]
+	if verb == '%' {
+		return Placeholder{Verb: '%'}, i + 1, arg
+	}
+	if !isVerb(verb) {
+		return Placeholder{}, -1, arg
+	}
+	p := Placeholder{Kind: Printf, Text: s[start : i+1], Start: start, End: i + 1, Verb: verb, Index: arg, Stars: stars}
+	return p, i + 1, arg + 1
+}
+
+// parseIndex parses an explicit argument index such as [2]
+func parseIndex(s string, i int) (int, int, bool) {
+	if i >= len(s) || s[i] != '[' {
+		return 0, i, false
+	}
+	end := strings.IndexByte(s[i:], ']')
+	if end < 0 {
+		return 0, i, false
+	}
+	n, err := strconv.Atoi(s[i+1 : i+end])
+	if err != nil || n < 1 {
+		return 0, i, false
+	}
+	return n, i + end + 1, true
+}
+
+// parsePosition parses the argument position of POSIX and Java format
+// strings, such as the 2$ of %2$s
+func parsePosition(s string, i int) (int, int, bool) {
+	end := i
+	for end < len(s) && s[end] >= '0' && s[end] <= '9' {
+		end++
+	}
+	if end == i || end >= len(s) || s[end] != '$' {
+		return 0, i, false
+	}
+	n, err := strconv.Atoi(s[i:end])
+	if err != nil || n < 1 {
+		return 0, i, false
+	}
+	return n, end + 1, true
+}
+
+// isVerb accepts the fmt verbs, and the @ of Objective-C and Swift objects
+func isVerb(c byte) bool {
+	return strings.IndexByte("vTtbcdoOqxXUeEfFgGsp@", c) >= 0
+}
+
+// validName accepts identifiers and numbers, so that JSON or CSS in braces
+// is not taken for a placeholder
+func validName(name string) bool {
+	if name == "" {
+		return false
+	}
+	for i, c := range name {
+		switch {
+		case c == '_' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z':
+		case c >= '0' && c <= '9':
+		case c == '.' || c == '-':
+			if i == 0 {
+				return false
+			}
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// GoType returns the Go type of the argument of a printf verb
+func GoType(verb byte) string {
+	switch verb {
+	case 'd', 'o', 'O', 'b', 'c', 'U':
+		return "int"
+	case 'e', 'E', 'f', 'F', 'g', 'G':
+		return "float64"
+	case 's', 'q':
+		return "string"
+	case 't':
+		return "bool"
+	}
+	return "interface{}"
+}
+
+// Args returns the Go types of the arguments consumed by the printf
+// placeholders of s, indexed from 0. Arguments used with verbs of different
+// types, and arguments skipped by explicit indexes, are interface{}.
+func Args(s string) []string {
+	var args []string
+	for _, p := range Parse(s) {
+		if p.Kind != Printf {
+			continue
+		}
+		for _, i := range p.Stars {
+			args = setArg(args, i, "int")
+		}
+		args = setArg(args, p.Index, GoType(p.Verb))
+	}
+	for i, t := range args {
+		if t == "" {
+			args[i] = "interface{}"
+		}
+	}
+	return args
+}
+
+// setArg sets the type of argument i, falling back to interface{} when it
+// already has another type
+func setArg(args []string, i int, t string) []string {
+	for len(args) < i {
+		args = append(args, "")
+	}
+	switch args[i-1] {
+	case "", t:
+		args[i-1] = t
+	default:
+		args[i-1] = "interface{}"
+	}
+	return args
+}
+
+// markupPattern matches HTML and XML tags and character entities
+var markupPattern = regexp.MustCompile(`</?[a-zA-Z][^<>]*>|&(?:[a-zA-Z]+|#[0-9]+|#[xX][0-9a-fA-F]+);`)
+
+// Protected returns the sorted, non-overlapping byte ranges of s which
+// translations must keep as they are: the placeholders, and the tags and
+// character entities of markup
+func Protected(s string) [][2]int {
+	var ranges [][2]int
+	for _, p := range Parse(s) {
+		ranges = append(ranges, [2]int{p.Start, p.End})
+	}
+	for _, m := range markupPattern.FindAllStringIndex(s, -1) {
+		ranges = append(ranges, [2]int{m[0], m[1]})
+	}
+	sort.Slice(ranges, func(i, j int) bool { return ranges[i][0] < ranges[j][0] })
+	var merged [][2]int
+	for _, r := range ranges {
+		if n := len(merged); n > 0 && r[0] < merged[n-1][1] {
+			if r[1] > merged[n-1][1] {
+				merged[n-1][1] = r[1]
+			}
+			continue
+		}
+		merged = append(merged, r)
+	}
+	return merged
+}
@@ -0,0 +1,65 @@
+package placeholder_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/blacksails/poeditor/internal/placeholder"
+)
+
+func TestParse(t *testing.T) {
+	var texts []string
+	for _, p := range placeholder.Parse("%d%% of {count} files by %[1]s, {not json} %*d %z {0} {{ .Count }} {{") {
+		texts = append(texts, p.Text)
+	}
+	want := []string{"%d", "{count}", "%[1]s", "%*d", "{0}", "{{ .Count }}"}
+	if !reflect.DeepEqual(texts, want) {
+		t.Errorf("\nExpected %q\nGot      %q", want, texts)
+	}
+
+	var got []placeholder.Placeholder
+	for _, p := range placeholder.Parse("%2$s has %1$.2f and %@ %0$s") {
+		got = append(got, placeholder.Placeholder{Text: p.Text, Verb: p.Verb, Index: p.Index})
+	}
+	wantPositional := []placeholder.Placeholder{
+		{Text: "%2$s", Verb: 's', Index: 2},
+		{Text: "%1$.2f", Verb: 'f', Index: 1},
+		{Text: "%@", Verb: '@', Index: 2},
+	}
+	if !reflect.DeepEqual(got, wantPositional) {
+		t.Errorf("\nExpected %+v\nGot      %+v", wantPositional, got)
+	}
+}
+
+func TestArgs(t *testing.T) {
+	tests := []struct {
+		s    string
+		want []string
+	}{
+		{"plain", nil},
+		{"%s has %d files", []string{"string", "int"}},
+		{"%.2f%%", []string{"float64"}},
+		{"%[2]s before %[1]d", []string{"int", "string"}},
+		{"%[2]d", []string{"interface{}", "int"}},
+		{"%d as %[1]s", []string{"interface{}"}},
+		{"%*d", []string{"int", "int"}},
+		{"%v {name}", []string{"interface{}"}},
+	}
+	for _, test := range tests {
+		if got := placeholder.Args(test.s); !reflect.DeepEqual(got, test.want) {
+			t.Errorf("%q: expected %q, got %q", test.s, test.want, got)
+		}
+	}
+}
+
+func TestProtected(t *testing.T) {
+	s := `<a href="{url}">%s</a> &amp; {{.Name}}`
+	var texts []string
+	for _, r := range placeholder.Protected(s) {
+		texts = append(texts, s[r[0]:r[1]])
+	}
+	want := []string{`<a href="{url}">`, "%s", "</a>", "&amp;", "{{.Name}}"}
+	if !reflect.DeepEqual(texts, want) {
+		t.Errorf("\nExpected %q\nGot      %q", want, texts)
+	}
+}
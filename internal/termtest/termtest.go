@@ -0,0 +1,13 @@
+// Package termtest builds the terms used by the tests of other packages.
+package termtest
+
+import "github.com/blacksails/poeditor"
+
+// Translated returns a term translated with the content, which is a string
+// or a poeditor.Plural
+func Translated(term string, content interface{}) poeditor.TermTranslated {
+	return poeditor.TermTranslated{
+		Term:        poeditor.Term{TermBase: poeditor.TermBase{Term: term}},
+		Translation: poeditor.Translation{Content: content},
+	}
+}
@@ -0,0 +1,382 @@
+// Package toml implements the subset of TOML used by message files: tables,
+// dotted and quoted keys, comments, and string, integer, float and boolean
+// values. Arrays, inline tables and dates are not supported.
+package toml
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Parse parses the TOML document into nested map[string]interface{} values
+// holding string, int64, float64 and bool values
+func Parse(data []byte) (map[string]interface{}, error) {
+	p := &parser{src: string(data), line: 1}
+	root := make(map[string]interface{})
+	table := root
+	for {
+		p.skipSpace(true)
+		if p.eof() {
+			return root, nil
+		}
+		if p.peek() == '[' {
+			p.pos++
+			if p.peek() == '[' {
+				return nil, p.errorf("arrays of tables are not supported")
+			}
+			keys, err := p.keys()
+			if err != nil {
+				return nil, err
+			}
+			if p.peek() != ']' {
+				return nil, p.errorf("expected ] after table name")
+			}
+			p.pos++
+			if table, err = p.table(root, keys, true); err != nil {
+				return nil, err
+			}
+		} else {
+			keys, err := p.keys()
+			if err != nil {
+				return nil, err
+			}
+			if p.peek() != '=' {
+				return nil, p.errorf("expected = after key")
+			}
+			p.pos++
+			p.skipSpace(false)
+			v, err := p.value()
+			if err != nil {
+				return nil, err
+			}
+			t, err := p.table(table, keys[:len(keys)-1], false)
+			if err != nil {
+				return nil, err
+			}
+			key := keys[len(keys)-1]
+			if _, ok := t[key]; ok {
+				return nil, p.errorf("duplicate key %q", key)
+			}
+			t[key] = v
+		}
+		p.skipSpace(false)
+		if !p.eof() && p.peek() != '\n' {
+			return nil, p.errorf("unexpected %q after value", p.peek())
+		}
+	}
+}
+
+// SyntaxError reports the line of an invalid document
+type SyntaxError struct {
+	Line int
+	Msg  string
+}
+
+func (e *SyntaxError) Error() string {
+	return fmt.Sprintf("toml: line %d: %s", e.Line, e.Msg)
+}
+
+type parser struct {
+	src  string
+	pos  int
+	line int
+	// defined holds the keys of the tables declared by a header, joined by
+	// NUL characters
+	defined map[string]bool
+}
+
+func (p *parser) errorf(format string, args ...interface{}) error {
+	return &SyntaxError{Line: p.line, Msg: fmt.Sprintf(format, args...)}
+}
+
+func (p *parser) eof() bool {
+	return p.pos >= len(p.src)
+}
+
+func (p *parser) peek() byte {
+	if p.eof() {
+		return 0
+	}
+	return p.src[p.pos]
+}
+
+// skipSpace skips blanks and comments, and newlines if lines is set
+func (p *parser) skipSpace(lines bool) {
+	for !p.eof() {
+		switch c := p.peek(); {
+		case c == ' ' || c == '\t' || c == '\r':
+			p.pos++
+		case c == '\n' && lines:
+			p.pos++
+			p.line++
+		case c == '#':
+			for !p.eof() && p.peek() != '\n' {
+				p.pos++
+			}
+		default:
+			return
+		}
+	}
+}
+
+// table returns the table at the keys below t, creating missing tables. A
+// table can only be declared by one header.
+func (p *parser) table(t map[string]interface{}, keys []string, header bool) (map[string]interface{}, error) {
+	for _, key := range keys {
+		switch v := t[key].(type) {
+		case nil:
+			next := make(map[string]interface{})
+			t[key] = next
+			t = next
+		case map[string]interface{}:
+			t = v
+		default:
+			return nil, p.errorf("key %q is not a table", key)
+		}
+	}
+	if header {
+		path := strings.Join(keys, "\x00")
+		if p.defined[path] {
+			return nil, p.errorf("table %q is defined twice", strings.Join(keys, "."))
+		}
+		if p.defined == nil {
+			p.defined = make(map[string]bool)
+		}
+		p.defined[path] = true
+	}
+	return t, nil
+}
+
+// keys parses a dotted key
+func (p *parser) keys() ([]string, error) {
+	var keys []string
+	for {
+		p.skipSpace(false)
+		var (
+			key string
+			err error
+		)
+		switch c := p.peek(); {
+		case c == '"' || c == '\'':
+			key, err = p.str()
+			if err != nil {
+				return nil, err
+			}
+		case isBareKey(c):
+			start := p.pos
+			for !p.eof() && isBareKey(p.peek()) {
+				p.pos++
+			}
+			key = p.src[start:p.pos]
+		default:
+			return nil, p.errorf("expected a key")
+		}
+		keys = append(keys, key)
+		p.skipSpace(false)
+		if p.peek() != '.' {
+			return keys, nil
+		}
+		p.pos++
+	}
+}
+
+func isBareKey(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '_' || c == '-'
+}
+
+// value parses a string, number or boolean
+func (p *parser) value() (interface{}, error) {
+	switch c := p.peek(); {
+	case c == '"' || c == '\'':
+		return p.str()
+	case c == '[' || c == '{':
+		return nil, p.errorf("arrays and inline tables are not supported")
+	}
+	start := p.pos
+	for !p.eof() && !strings.ContainsRune(" \t\r\n#", rune(p.peek())) {
+		p.pos++
+	}
+	s := p.src[start:p.pos]
+	switch s {
+	case "true":
+		return true, nil
+	case "false":
+		return false, nil
+	}
+	clean := strings.Replace(s, "_", "", -1)
+	if n, err := strconv.ParseInt(clean, 0, 64); err == nil {
+		return n, nil
+	}
+	if f, err := strconv.ParseFloat(clean, 64); err == nil {
+		return f, nil
+	}
+	return nil, p.errorf("invalid value %q", s)
+}
+
+// str parses a basic or literal string, either of which may be multi line
+func (p *parser) str() (string, error) {
+	quote := p.src[p.pos : p.pos+1]
+	if strings.HasPrefix(p.src[p.pos:], quote+quote+quote) {
+		return p.multiline(quote)
+	}
+	p.pos++
+	var b strings.Builder
+	for {
+		if p.eof() || p.peek() == '\n' {
+			return "", p.errorf("unterminated string")
+		}
+		c := p.peek()
+		switch {
+		case c == quote[0]:
+			p.pos++
+			return b.String(), nil
+		case c == '\\' && quote == `"`:
+			if err := p.escape(&b); err != nil {
+				return "", err
+			}
+		default:
+			b.WriteByte(c)
+			p.pos++
+		}
+	}
+}
+
+// multiline parses a multi line string. A newline right after the opening
+// delimiter is trimmed, and in basic strings a backslash at the end of a line
+// trims the following whitespace.
+func (p *parser) multiline(quote string) (string, error) {
+	delim := quote + quote + quote
+	p.pos += 3
+	if strings.HasPrefix(p.src[p.pos:], "\r\n") {
+		p.pos += 2
+		p.line++
+	} else if p.peek() == '\n' {
+		p.pos++
+		p.line++
+	}
+	var b strings.Builder
+	for {
+		if p.eof() {
+			return "", p.errorf("unterminated string")
+		}
+		if strings.HasPrefix(p.src[p.pos:], delim) {
+			p.pos += 3
+			// Up to two quotes may directly precede the closing delimiter
+			for i := 0; i < 2 && p.peek() == quote[0]; i++ {
+				b.WriteByte(quote[0])
+				p.pos++
+			}
+			return b.String(), nil
+		}
+		c := p.peek()
+		switch {
+		case c == '\\' && quote == `"`:
+			rest := strings.TrimLeft(p.src[p.pos+1:], " \t\r")
+			if strings.HasPrefix(rest, "\n") {
+				p.pos++
+				for !p.eof() && strings.ContainsRune(" \t\r\n", rune(p.peek())) {
+					if p.peek() == '\n' {
+						p.line++
+					}
+					p.pos++
+				}
+				continue
+			}
+			if err := p.escape(&b); err != nil {
+				return "", err
+			}
+		default:
+			if c == '\n' {
+				p.line++
+			}
+			b.WriteByte(c)
+			p.pos++
+		}
+	}
+}
+
+// escape parses an escape sequence of a basic string
+func (p *parser) escape(b *strings.Builder) error {
+	p.pos++
+	if p.eof() {
+		return p.errorf("unterminated string")
+	}
+	c := p.peek()
+	p.pos++
+	switch c {
+	case 'b':
+		b.WriteByte('\b')
+	case 't':
+		b.WriteByte('\t')
+	case 'n':
+		b.WriteByte('\n')
+	case 'f':
+		b.WriteByte('\f')
+	case 'r':
+		b.WriteByte('\r')
+	case '"', '\\':
+		b.WriteByte(c)
+	case 'u', 'U':
+		n := 4
+		if c == 'U' {
+			n = 8
+		}
+		if p.pos+n > len(p.src) {
+			return p.errorf("invalid unicode escape")
+		}
+		r, err := strconv.ParseUint(p.src[p.pos:p.pos+n], 16, 32)
+		if err != nil {
+			return p.errorf("invalid unicode escape")
+		}
+		b.WriteRune(rune(r))
+		p.pos += n
+	default:
+		return p.errorf("invalid escape \\%c", c)
+	}
+	return nil
+}
+
+// Quote returns s as a TOML basic string
+func Quote(s string) string {
+	var b strings.Builder
+	b.WriteByte('"')
+	for _, r := range s {
+		switch r {
+		case '"', '\\':
+			b.WriteByte('\\')
+			b.WriteRune(r)
+		case '\b':
+			b.WriteString(`\b`)
+		case '\t':
+			b.WriteString(`\t`)
+		case '\n':
+			b.WriteString(`\n`)
+		case '\f':
+			b.WriteString(`\f`)
+		case '\r':
+			b.WriteString(`\r`)
+		default:
+			if r < 0x20 || r == 0x7f {
+				fmt.Fprintf(&b, `\u%04X`, r)
+				continue
+			}
+			b.WriteRune(r)
+		}
+	}
+	b.WriteByte('"')
+	return b.String()
+}
+
+// QuoteKey returns key bare if possible, or else quoted
+func QuoteKey(key string) string {
+	if key == "" {
+		return `""`
+	}
+	for i := 0; i < len(key); i++ {
+		if !isBareKey(key[i]) {
+			return Quote(key)
+		}
+	}
+	return key
+}
@@ -0,0 +1,82 @@
+package toml_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/blacksails/poeditor/internal/toml"
+)
+
+func TestParse(t *testing.T) {
+	doc := `# messages
+HelloWorld = "Hello \"World\"!\n" # greeting
+"quoted key" = 'C:\path'
+a.b = 1_000
+ratio = 0.5
+on = true
+
+[PersonCats]
+description = """
+The number of cats
+a person has"""
+other = '''{{.Name}} has {{.Count}} cats.'''
+
+[nested."deep table"]
+x = """one \
+    line"""
+`
+	got, err := toml.Parse([]byte(doc))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]interface{}{
+		"HelloWorld": "Hello \"World\"!\n",
+		"quoted key": `C:\path`,
+		"a":          map[string]interface{}{"b": int64(1000)},
+		"ratio":      0.5,
+		"on":         true,
+		"PersonCats": map[string]interface{}{
+			"description": "The number of cats\na person has",
+			"other":       "{{.Name}} has {{.Count}} cats.",
+		},
+		"nested": map[string]interface{}{
+			"deep table": map[string]interface{}{"x": "one line"},
+		},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("\nExpected %#v\nGot      %#v", want, got)
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		doc  string
+		line int
+	}{
+		{"a = 1\na = 2\n", 2},
+		{"[t]\nx = 1\n[t]\n", 3},
+		{"a = \"open\n", 1},
+		{"a = [1, 2]\n", 1},
+		{"a = 1 b\n", 1},
+		{"a = 1\nb.c = 2\n[b.c]\n", 3},
+	}
+	for _, test := range tests {
+		_, err := toml.Parse([]byte(test.doc))
+		serr, ok := err.(*toml.SyntaxError)
+		if !ok || serr.Line != test.line {
+			t.Errorf("%q: expected a syntax error on line %d, got %v", test.doc, test.line, err)
+		}
+	}
+}
+
+func TestQuote(t *testing.T) {
+	doc := "k = " + toml.Quote("tab\t \"q\" \\ \x01 é") + "\n" + toml.QuoteKey("a key") + " = 1\n"
+	got, err := toml.Parse([]byte(doc))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]interface{}{"k": "tab\t \"q\" \\ \x01 é", "a key": int64(1)}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("\nExpected %#v\nGot      %#v", want, got)
+	}
+}
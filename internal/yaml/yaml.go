@@ -0,0 +1,567 @@
+// Package yaml implements the subset of YAML used by configuration files:
+// block mappings and sequences, flow sequences and mappings, comments, plain
+// or quoted scalars and literal (|) or folded (>) block scalars. Anchors,
+// tags, multi line plain or quoted scalars and multiple documents are not
+// supported.
+//
+// Documents are decoded into the same values encoding/json produces, and
+// Unmarshal fills structs through their json tags.
+package yaml
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Unmarshal parses the YAML document and stores the result in the value
+// pointed to by v, using the json struct tags of v
+func Unmarshal(data []byte, v interface{}) error {
+	tree, err := Parse(data)
+	if err != nil {
+		return err
+	}
+	b, err := json.Marshal(tree)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(b, v)
+}
+
+// Parse parses the YAML document into map[string]interface{},
+// []interface{}, string, float64, bool and nil values
+func Parse(data []byte) (interface{}, error) {
+	p := &parser{}
+	src := strings.Split(string(data), "\n")
+	for i := 0; i < len(src); i++ {
+		text := strings.TrimRight(stripComment(src[i]), " \t\r")
+		trimmed := strings.TrimLeft(text, " ")
+		if trimmed == "" || text == "---" {
+			continue
+		}
+		if strings.HasPrefix(trimmed, "\t") {
+			return nil, &SyntaxError{Line: i + 1, Msg: "tabs are not allowed in indentation"}
+		}
+		l := line{
+			num:    i + 1,
+			indent: len(text) - len(trimmed),
+			text:   trimmed,
+		}
+		if m := blockHeader.FindStringSubmatch(nodeValue(trimmed)); m != nil {
+			var err error
+			l.block = true
+			if l.scalar, i, err = blockScalar(src, i, l.indent, m); err != nil {
+				return nil, err
+			}
+		}
+		p.lines = append(p.lines, l)
+	}
+	if len(p.lines) == 0 {
+		return nil, nil
+	}
+	v, err := p.block(p.lines[0].indent)
+	if err != nil {
+		return nil, err
+	}
+	if p.pos < len(p.lines) {
+		return nil, p.errorf("unexpected indentation")
+	}
+	return v, nil
+}
+
+// SyntaxError reports the line of an invalid document
+type SyntaxError struct {
+	Line int
+	Msg  string
+}
+
+func (e *SyntaxError) Error() string {
+	return fmt.Sprintf("yaml: line %d: %s", e.Line, e.Msg)
+}
+
+type line struct {
+	num    int
+	indent int
+	text   string
+	// block is set when the line ends in a block scalar header, whose
+	// content is scalar
+	block  bool
+	scalar string
+}
+
+type parser struct {
+	lines []line
+	pos   int
+}
+
+func (p *parser) errorf(format string, args ...interface{}) error {
+	num := 0
+	if p.pos < len(p.lines) {
+		num = p.lines[p.pos].num
+	} else if len(p.lines) > 0 {
+		num = p.lines[len(p.lines)-1].num
+	}
+	return &SyntaxError{Line: num, Msg: fmt.Sprintf(format, args...)}
+}
+
+// block parses the mapping or sequence starting at the current line
+func (p *parser) block(indent int) (interface{}, error) {
+	if isItem(p.lines[p.pos].text) {
+		return p.sequence(indent)
+	}
+	if _, _, ok := splitKey(p.lines[p.pos].text); !ok {
+		l := p.lines[p.pos]
+		p.pos++
+		if l.block {
+			return l.scalar, nil
+		}
+		return scalar(l.text, l.num)
+	}
+	return p.mapping(indent)
+}
+
+func (p *parser) sequence(indent int) ([]interface{}, error) {
+	seq := []interface{}{}
+	for p.pos < len(p.lines) {
+		l := p.lines[p.pos]
+		if l.indent < indent {
+			break
+		}
+		if l.indent > indent {
+			return nil, p.errorf("unexpected indentation")
+		}
+		if !isItem(l.text) {
+			break
+		}
+		rest := strings.TrimLeft(l.text[1:], " ")
+		if rest == "" {
+			// The item is a block on the following lines
+			p.pos++
+			if p.pos >= len(p.lines) || p.lines[p.pos].indent <= indent {
+				seq = append(seq, nil)
+				continue
+			}
+			v, err := p.block(p.lines[p.pos].indent)
+			if err != nil {
+				return nil, err
+			}
+			seq = append(seq, v)
+			continue
+		}
+		// The item starts a block on the same line, such as "- key: value".
+		// Parse it as if the content started on a line of its own.
+		l.indent = indent + len(l.text) - len(rest)
+		l.text = rest
+		p.lines[p.pos] = l
+		v, err := p.block(p.lines[p.pos].indent)
+		if err != nil {
+			return nil, err
+		}
+		seq = append(seq, v)
+	}
+	return seq, nil
+}
+
+func (p *parser) mapping(indent int) (map[string]interface{}, error) {
+	m := make(map[string]interface{})
+	for p.pos < len(p.lines) {
+		l := p.lines[p.pos]
+		if l.indent < indent {
+			break
+		}
+		if l.indent > indent {
+			return nil, p.errorf("unexpected indentation")
+		}
+		key, value, ok := splitKey(l.text)
+		if !ok {
+			return nil, p.errorf("expected a key")
+		}
+		k, err := scalar(key, l.num)
+		if err != nil {
+			return nil, err
+		}
+		name := fmt.Sprint(k)
+		if _, dup := m[name]; dup {
+			return nil, p.errorf("duplicate key %q", name)
+		}
+		p.pos++
+		if l.block {
+			m[name] = l.scalar
+			continue
+		}
+		if value != "" {
+			if m[name], err = scalar(value, l.num); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		// A nested block is indented, except sequences which may start at
+		// the indentation of their key
+		next := p.pos < len(p.lines) && (p.lines[p.pos].indent > indent ||
+			p.lines[p.pos].indent == indent && isItem(p.lines[p.pos].text))
+		if !next {
+			m[name] = nil
+			continue
+		}
+		if m[name], err = p.block(p.lines[p.pos].indent); err != nil {
+			return nil, err
+		}
+	}
+	return m, nil
+}
+
+// blockHeader matches the header of a block scalar: the style, and an
+// indentation and a chomping indicator in either order
+var blockHeader = regexp.MustCompile(`^([|>])(?:([1-9])?([-+])?|([-+])([1-9]))$`)
+
+// nodeValue returns the inline value of a line, after the item markers and
+// the key
+func nodeValue(text string) string {
+	for isItem(text) {
+		text = strings.TrimLeft(text[1:], " ")
+	}
+	if _, value, ok := splitKey(text); ok {
+		return value
+	}
+	return text
+}
+
+// blockScalar reads the content of the block scalar whose header is on line
+// i of src, indented more than indent. It returns the scalar and the last
+// line of its content.
+func blockScalar(src []string, i, indent int, header []string) (string, int, error) {
+	style, chomp := header[1], header[3]+header[4]
+	explicit := header[2] + header[5]
+	content := 0
+	if explicit != "" {
+		n, _ := strconv.Atoi(explicit)
+		content = indent + n
+	}
+	var lines []string
+	end := i
+	for j := i + 1; j < len(src); j++ {
+		text := strings.TrimRight(src[j], "\r")
+		trimmed := strings.TrimLeft(text, " ")
+		n := len(text) - len(trimmed)
+		if trimmed == "" {
+			// Blank lines belong to the scalar until a less indented line
+			// ends it
+			lines = append(lines, "")
+			continue
+		}
+		if content == 0 {
+			if n <= indent {
+				break
+			}
+			content = n
+		}
+		if n < content {
+			if n > indent {
+				return "", 0, &SyntaxError{Line: j + 1, Msg: "insufficient indentation in block scalar"}
+			}
+			break
+		}
+		lines = append(lines, text[content:])
+		end = j
+	}
+	// Trailing blank lines are only kept by the keep indicator
+	lines = lines[:end-i]
+	trailing := 0
+	if chomp == "+" {
+		for j := end + 1; j < len(src) && strings.TrimSpace(src[j]) == ""; j++ {
+			if j == len(src)-1 && src[j] == "" {
+				// The empty string after the final line break
+				break
+			}
+			trailing++
+			end = j
+		}
+	}
+	var s string
+	if style == "|" {
+		s = strings.Join(lines, "\n")
+	} else {
+		s = fold(lines)
+	}
+	switch {
+	case chomp == "-" || len(lines) == 0 && chomp == "":
+	case chomp == "+":
+		s += "\n" + strings.Repeat("\n", trailing)
+	default:
+		s += "\n"
+	}
+	return s, end, nil
+}
+
+// fold joins the lines of a folded block scalar. Line breaks between lines of
+// text become spaces, while blank lines and lines indented further than the
+// content keep their line breaks.
+func fold(lines []string) string {
+	var b strings.Builder
+	// last is the last line with text
+	last := ""
+	for i, l := range lines {
+		switch {
+		case i == 0:
+		case l == "":
+			b.WriteByte('\n')
+		case lines[i-1] == "":
+			// The line break before the blank lines is folded away, unless
+			// it follows or precedes an indented line
+			if indented(l) || indented(last) {
+				b.WriteByte('\n')
+			}
+		case indented(l) || indented(last):
+			b.WriteByte('\n')
+		default:
+			b.WriteByte(' ')
+		}
+		b.WriteString(l)
+		if l != "" {
+			last = l
+		}
+	}
+	return b.String()
+}
+
+// indented reports whether a line of a folded block scalar is indented
+// further than the content
+func indented(l string) bool {
+	return strings.HasPrefix(l, " ") || strings.HasPrefix(l, "\t")
+}
+
+func isItem(text string) bool {
+	return text == "-" || strings.HasPrefix(text, "- ")
+}
+
+// splitKey splits "key: value" outside of quotes and flow collections
+func splitKey(text string) (key, value string, ok bool) {
+	if text == "" || strings.ContainsRune("[{", rune(text[0])) {
+		return "", "", false
+	}
+	i := scan(text, func(i int) bool {
+		return text[i] == ':' && (i+1 == len(text) || text[i+1] == ' ')
+	})
+	if i < 0 {
+		return "", "", false
+	}
+	return strings.TrimSpace(text[:i]), strings.TrimSpace(text[i+1:]), true
+}
+
+// stripComment removes a trailing comment outside of quotes
+func stripComment(text string) string {
+	i := scan(text, func(i int) bool {
+		return text[i] == '#' && (i == 0 || text[i-1] == ' ' || text[i-1] == '\t')
+	})
+	if i < 0 {
+		return text
+	}
+	return text[:i]
+}
+
+// scan returns the first index outside of quoted strings where match is true,
+// or -1
+func scan(text string, match func(i int) bool) int {
+	var quote byte
+	for i := 0; i < len(text); i++ {
+		c := text[i]
+		switch {
+		case quote == '"' && c == '\\':
+			i++
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case (c == '"' || c == '\'') && (i == 0 || strings.ContainsRune(" \t[{,:-", rune(text[i-1]))):
+			quote = c
+		case match(i):
+			return i
+		}
+	}
+	return -1
+}
+
+// scalar parses an inline value: a quoted or plain scalar, or a flow
+// collection
+func scalar(text string, num int) (interface{}, error) {
+	f := &flow{text: text, num: num}
+	v, err := f.value()
+	if err != nil {
+		return nil, err
+	}
+	f.skipSpace()
+	if f.pos < len(f.text) {
+		return nil, f.errorf("unexpected %q", f.text[f.pos:])
+	}
+	return v, nil
+}
+
+// flow parses inline values
+type flow struct {
+	text string
+	pos  int
+	num  int
+	// depth is the nesting of flow collections, where commas end plain
+	// scalars
+	depth int
+}
+
+func (f *flow) errorf(format string, args ...interface{}) error {
+	return &SyntaxError{Line: f.num, Msg: fmt.Sprintf(format, args...)}
+}
+
+func (f *flow) skipSpace() {
+	for f.pos < len(f.text) && (f.text[f.pos] == ' ' || f.text[f.pos] == '\t') {
+		f.pos++
+	}
+}
+
+func (f *flow) value() (interface{}, error) {
+	f.skipSpace()
+	if f.pos >= len(f.text) {
+		return nil, nil
+	}
+	switch f.text[f.pos] {
+	case '[':
+		return f.sequence()
+	case '{':
+		return f.mapping()
+	case '"', '\'':
+		return f.quoted()
+	case '&', '*', '!', '|', '>':
+		return nil, f.errorf("unsupported %q", f.text[f.pos])
+	}
+	start := f.pos
+	for f.pos < len(f.text) {
+		c := f.text[f.pos]
+		if f.depth > 0 && (c == ',' || c == ']' || c == '}' ||
+			c == ':' && (f.pos+1 == len(f.text) || f.text[f.pos+1] == ' ')) {
+			break
+		}
+		f.pos++
+	}
+	return plain(strings.TrimSpace(f.text[start:f.pos])), nil
+}
+
+func (f *flow) quoted() (string, error) {
+	q := f.text[f.pos]
+	var b strings.Builder
+	for i := f.pos + 1; i < len(f.text); i++ {
+		c := f.text[i]
+		switch {
+		case q == '\'' && c == '\'' && i+1 < len(f.text) && f.text[i+1] == '\'':
+			b.WriteByte('\'')
+			i++
+		case c == q && q == '\'':
+			f.pos = i + 1
+			return b.String(), nil
+		case c == q:
+			s, err := strconv.Unquote(f.text[f.pos : i+1])
+			if err != nil {
+				return "", f.errorf("invalid quoted string %s", f.text[f.pos:i+1])
+			}
+			f.pos = i + 1
+			return s, nil
+		case q == '"' && c == '\\':
+			i++
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return "", f.errorf("unterminated quoted string")
+}
+
+func (f *flow) sequence() ([]interface{}, error) {
+	f.pos++
+	f.depth++
+	seq := []interface{}{}
+	for {
+		f.skipSpace()
+		if f.pos >= len(f.text) {
+			return nil, f.errorf("unterminated flow sequence")
+		}
+		if f.text[f.pos] == ']' {
+			f.pos++
+			f.depth--
+			return seq, nil
+		}
+		v, err := f.value()
+		if err != nil {
+			return nil, err
+		}
+		seq = append(seq, v)
+		if err := f.separator(']'); err != nil {
+			return nil, err
+		}
+	}
+}
+
+func (f *flow) mapping() (map[string]interface{}, error) {
+	f.pos++
+	f.depth++
+	m := make(map[string]interface{})
+	for {
+		f.skipSpace()
+		if f.pos >= len(f.text) {
+			return nil, f.errorf("unterminated flow mapping")
+		}
+		if f.text[f.pos] == '}' {
+			f.pos++
+			f.depth--
+			return m, nil
+		}
+		k, err := f.value()
+		if err != nil {
+			return nil, err
+		}
+		f.skipSpace()
+		var v interface{}
+		if f.pos < len(f.text) && f.text[f.pos] == ':' {
+			f.pos++
+			if v, err = f.value(); err != nil {
+				return nil, err
+			}
+		}
+		m[fmt.Sprint(k)] = v
+		if err := f.separator('}'); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// separator consumes the comma between flow collection entries
+func (f *flow) separator(end byte) error {
+	f.skipSpace()
+	switch {
+	case f.pos < len(f.text) && f.text[f.pos] == ',':
+		f.pos++
+	case f.pos < len(f.text) && f.text[f.pos] == end:
+	default:
+		return f.errorf("expected , or %c", end)
+	}
+	return nil
+}
+
+// plain resolves the type of a plain scalar
+func plain(s string) interface{} {
+	switch s {
+	case "", "~", "null", "Null", "NULL":
+		return nil
+	case "true", "True", "TRUE":
+		return true
+	case "false", "False", "FALSE":
+		return false
+	}
+	if !strings.ContainsAny(s[:1], "+-.0123456789") {
+		return s
+	}
+	if n, err := strconv.ParseInt(s, 0, 64); err == nil {
+		return float64(n)
+	}
+	if n, err := strconv.ParseFloat(s, 64); err == nil && !strings.ContainsAny(s, "xXpP_") {
+		return n
+	}
+	return s
+}
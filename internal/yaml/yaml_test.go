@@ -0,0 +1,100 @@
+package yaml_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/blacksails/poeditor/internal/yaml"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		doc  string
+		want interface{}
+	}{
+		{"", nil},
+		{"a: 1\nb: two # comment\nc: 'it''s'\nd: \"x: #y\"\ne:\nf: true\n", map[string]interface{}{
+			"a": 1.0, "b": "two", "c": "it's", "d": "x: #y", "e": nil, "f": true,
+		}},
+		{"# config\nlist:\n  - a\n  - 2\nflow: [a, 'b, c', {k: v}]\n", map[string]interface{}{
+			"list": []interface{}{"a", 2.0},
+			"flow": []interface{}{"a", "b, c", map[string]interface{}{"k": "v"}},
+		}},
+		{"files:\n- path: locales/{lang}.json\n  format: key_value_json\n  upload:\n    overwrite: yes\n-\n  path: b\n", map[string]interface{}{
+			"files": []interface{}{
+				map[string]interface{}{
+					"path":   "locales/{lang}.json",
+					"format": "key_value_json",
+					"upload": map[string]interface{}{"overwrite": "yes"},
+				},
+				map[string]interface{}{"path": "b"},
+			},
+		}},
+		{"- - a\n  - b\n- c\n", []interface{}{[]interface{}{"a", "b"}, "c"}},
+		// Block scalars keep comments and blank lines of their content
+		{"literal: |\n  line 1\n    # not a comment\n\n  line 3\nnext: x\n", map[string]interface{}{
+			"literal": "line 1\n  # not a comment\n\nline 3\n", "next": "x",
+		}},
+		{"folded: >\n  one\n  two\n\n  three\n    indented\n  four\n", map[string]interface{}{
+			"folded": "one two\nthree\n  indented\nfour\n",
+		}},
+		{"strip: |-\n  a\n\nkeep: |+\n  b\n\nclip: >\n  c\n\n\n", map[string]interface{}{
+			"strip": "a", "keep": "b\n\n", "clip": "c\n",
+		}},
+		{"explicit: |2\n    indented\n  text\nempty: |\n", map[string]interface{}{
+			"explicit": "  indented\ntext\n", "empty": "",
+		}},
+		{"- |\n  item\n- key: >-\n    folded\n    item\n", []interface{}{
+			"item\n", map[string]interface{}{"key": "folded item"},
+		}},
+	}
+	for _, test := range tests {
+		got, err := yaml.Parse([]byte(test.doc))
+		if err != nil {
+			t.Errorf("%q: unexpected error: %s", test.doc, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("%q:\nExpected %#v\nGot      %#v", test.doc, test.want, got)
+		}
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		doc  string
+		line int
+	}{
+		{"a: 1\n  b: 2\n", 2},
+		{"a: 1\na: 2\n", 2},
+		{"a: [1, 2\n", 1},
+		{"a:\n  - 1\n  b: 2\n", 3},
+		{"a: \"x\n", 1},
+		{"a: &anchor 1\n", 1},
+		{"a: |\n    b\n  c\n", 3},
+	}
+	for _, test := range tests {
+		_, err := yaml.Parse([]byte(test.doc))
+		serr, ok := err.(*yaml.SyntaxError)
+		if !ok {
+			t.Errorf("%q: expected a syntax error, got %v", test.doc, err)
+			continue
+		}
+		if serr.Line != test.line {
+			t.Errorf("%q: expected line %d, got %d", test.doc, test.line, serr.Line)
+		}
+	}
+}
+
+func TestUnmarshal(t *testing.T) {
+	var v struct {
+		ProjectID int      `json:"project_id"`
+		Languages []string `json:"languages"`
+	}
+	if err := yaml.Unmarshal([]byte("project_id: 1234\nlanguages: [en, de]\n"), &v); err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if v.ProjectID != 1234 || !reflect.DeepEqual(v.Languages, []string{"en", "de"}) {
+		t.Errorf("Unexpected result %+v", v)
+	}
+}
@@ -0,0 +1,293 @@
+package poeditor
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// ReadKeyValueJSON decodes a key value JSON file. Plurals are objects keyed by
+// plural category, and terms with a context are nested in an object keyed by
+// the context. Keys are kept in file order and duplicates are not removed.
+func ReadKeyValueJSON(r io.Reader) ([]TermTranslated, error) {
+	dec := json.NewDecoder(r)
+	entries, err := readJSONObject(dec)
+	if err != nil {
+		return nil, err
+	}
+	var terms []TermTranslated
+	for _, e := range entries {
+		if t, ok := e.term(); ok {
+			terms = append(terms, t)
+			continue
+		}
+		// An object which is not a plural holds the terms of a context
+		for _, ce := range e.object {
+			t, ok := ce.term()
+			if !ok {
+				return nil, fmt.Errorf("key_value_json: unexpected object in context %q", e.key)
+			}
+			t.Context = e.key
+			terms = append(terms, t)
+		}
+	}
+	return terms, nil
+}
+
+// WriteKeyValueJSON encodes terms as a key value JSON file
+func WriteKeyValueJSON(w io.Writer, terms []TermTranslated) error {
+	var (
+		buf      bytes.Buffer
+		contexts []string
+		byCtx    = make(map[string][]TermTranslated)
+	)
+	for _, t := range terms {
+		if _, ok := byCtx[t.Context]; !ok && t.Context != "" {
+			contexts = append(contexts, t.Context)
+		}
+		byCtx[t.Context] = append(byCtx[t.Context], t)
+	}
+	writeTerms := func(indent string, terms []TermTranslated, first bool) error {
+		for _, t := range terms {
+			var v interface{}
+			switch c := t.Translation.Content.(type) {
+			case Plural:
+				v = pluralObject(c)
+			case string:
+				v = c
+			case nil:
+				v = ""
+			default:
+				return ErrTranslationInvalid
+			}
+			if err := writeJSONEntry(&buf, indent, t.Term.Term, v, first); err != nil {
+				return err
+			}
+			first = false
+		}
+		return nil
+	}
+	buf.WriteString("{")
+	if err := writeTerms("  ", byCtx[""], true); err != nil {
+		return err
+	}
+	for i, c := range contexts {
+		if i > 0 || len(byCtx[""]) > 0 {
+			buf.WriteString(",")
+		}
+		k, _ := marshalJSON(c)
+		fmt.Fprintf(&buf, "\n  %s: {", k)
+		if err := writeTerms("    ", byCtx[c], true); err != nil {
+			return err
+		}
+		buf.WriteString("\n  }")
+	}
+	buf.WriteString("\n}\n")
+	_, err := buf.WriteTo(w)
+	return err
+}
+
+// ReadJSON decodes a POEditor JSON file, which is an array of term objects
+func ReadJSON(r io.Reader) ([]TermTranslated, error) {
+	var entries []jsonTerm
+	if err := json.NewDecoder(r).Decode(&entries); err != nil {
+		return nil, err
+	}
+	terms := make([]TermTranslated, len(entries))
+	for i, e := range entries {
+		t := &terms[i]
+		t.Term.Term = e.Term
+		t.Context = e.Context
+		t.Plural = e.TermPlural
+		t.Reference = e.Reference
+		t.Comment = e.Comment
+		t.Tags = e.Tags
+		if e.Fuzzy {
+			t.Translation.Fuzzy = 1
+		}
+		tr := Translation{}
+		if len(e.Definition) == 0 || string(e.Definition) == "null" {
+			t.Translation.Content = ""
+			continue
+		}
+		if err := json.Unmarshal([]byte(`{"content":`+string(e.Definition)+`}`), &tr); err != nil {
+			return nil, fmt.Errorf("json: definition of %q: %s", e.Term, err)
+		}
+		t.Translation.Content = tr.Content
+	}
+	return terms, nil
+}
+
+// WriteJSON encodes terms as a POEditor JSON file
+func WriteJSON(w io.Writer, terms []TermTranslated) error {
+	entries := make([]jsonTerm, len(terms))
+	for i, t := range terms {
+		var def interface{}
+		switch c := t.Translation.Content.(type) {
+		case Plural:
+			def = pluralObject(c)
+		case string, nil:
+			def = c
+		default:
+			return ErrTranslationInvalid
+		}
+		d, err := json.Marshal(def)
+		if err != nil {
+			return err
+		}
+		entries[i] = jsonTerm{
+			Term:       t.Term.Term,
+			Definition: d,
+			Context:    t.Context,
+			TermPlural: t.Plural,
+			Reference:  t.Reference,
+			Comment:    t.Comment,
+			Tags:       t.Tags,
+			Fuzzy:      t.Translation.Fuzzy == 1,
+		}
+	}
+	b, err := marshalJSON(entries)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(append(bytes.Replace(b, []byte("\n  "), []byte("\n"), -1), '\n'))
+	return err
+}
+
+type jsonTerm struct {
+	Term       string          `json:"term"`
+	Definition json.RawMessage `json:"definition"`
+	Context    string          `json:"context"`
+	TermPlural string          `json:"term_plural"`
+	Reference  string          `json:"reference"`
+	Comment    string          `json:"comment"`
+	Tags       []string        `json:"tags,omitempty"`
+	Fuzzy      bool            `json:"fuzzy,omitempty"`
+}
+
+// jsonEntry is a key of a JSON object. Either value or object is set.
+type jsonEntry struct {
+	key    string
+	value  string
+	object []jsonEntry
+	isObj  bool
+}
+
+// term converts the entry to a term when it is a string or a plural object
+func (e jsonEntry) term() (TermTranslated, bool) {
+	var t TermTranslated
+	t.Term.Term = e.key
+	if !e.isObj {
+		t.Translation.Content = e.value
+		return t, true
+	}
+	if len(e.object) == 0 {
+		return t, false
+	}
+	var p Plural
+	fields := pluralFields(&p)
+	for _, pe := range e.object {
+		f, ok := fields[pe.key]
+		if !ok || pe.isObj {
+			return t, false
+		}
+		*f = pe.value
+	}
+	t.Plural = e.key
+	t.Translation.Content = p
+	return t, true
+}
+
+// readJSONObject reads an object of strings and nested objects while keeping
+// the order of the keys
+func readJSONObject(dec *json.Decoder) ([]jsonEntry, error) {
+	if err := expectDelim(dec, '{'); err != nil {
+		return nil, err
+	}
+	return readJSONObjectEntries(dec)
+}
+
+// readJSONObjectEntries reads the remainder of an object whose opening brace
+// has been consumed
+func readJSONObjectEntries(dec *json.Decoder) ([]jsonEntry, error) {
+	var entries []jsonEntry
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return nil, err
+		}
+		e := jsonEntry{key: tok.(string)}
+		tok, err = dec.Token()
+		if err != nil {
+			return nil, err
+		}
+		switch v := tok.(type) {
+		case string:
+			e.value = v
+		case json.Delim:
+			if v != '{' {
+				return nil, fmt.Errorf("unexpected %s in value of %q", v, e.key)
+			}
+			e.isObj = true
+			e.object, err = readJSONObjectEntries(dec)
+			if err != nil {
+				return nil, err
+			}
+		case nil:
+		default:
+			return nil, fmt.Errorf("unexpected value %v of %q", v, e.key)
+		}
+		entries = append(entries, e)
+	}
+	return entries, expectDelim(dec, '}')
+}
+
+func writeJSONEntry(buf *bytes.Buffer, indent, key string, v interface{}, first bool) error {
+	b, err := marshalJSON(v)
+	if err != nil {
+		return err
+	}
+	if !first {
+		buf.WriteString(",")
+	}
+	k, _ := marshalJSON(key)
+	b = bytes.Replace(b, []byte("\n  "), []byte("\n"+indent), -1)
+	fmt.Fprintf(buf, "\n%s%s: %s", indent, k, b)
+	return nil
+}
+
+// pluralObject returns the categories of a plural which are set, along with
+// other, for encoding as a JSON object
+func pluralObject(p Plural) map[string]string {
+	values := pluralValues(p)
+	o := make(map[string]string)
+	for _, c := range pluralForms(p) {
+		o[c] = values[c]
+	}
+	return o
+}
+
+// marshalJSON encodes v indented by two spaces, with every line but the first
+// prefixed by two spaces, and without escaping HTML
+func marshalJSON(v interface{}) ([]byte, error) {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("  ", "  ")
+	if err := enc.Encode(v); err != nil {
+		return nil, err
+	}
+	return bytes.TrimRight(buf.Bytes(), "\n"), nil
+}
+
+func expectDelim(dec *json.Decoder, d json.Delim) error {
+	tok, err := dec.Token()
+	if err != nil {
+		return err
+	}
+	if tok != d {
+		return fmt.Errorf("expected %q but got %v", d, tok)
+	}
+	return nil
+}
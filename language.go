@@ -3,12 +3,22 @@ package poeditor
 import (
 	"encoding/json"
 	"io"
+	"time"
 )
 
-// Language represents a single language of a project
+// Language represents a single language of a project. The statistics are only
+// set on languages returned from Project.ListLanguages.
 type Language struct {
 	Project *Project
 	Code    string
+	Name    string
+	// Translations is the number of translated terms
+	Translations int
+	// Percentage is the share of translated terms, from 0 to 100
+	Percentage float32
+	// Updated is the time of the last change, it is zero if the language has
+	// never been translated
+	Updated time.Time
 }
 
 // AvailableLanguages lists all languages supported by POEditor. This is handy
@@ -28,7 +38,14 @@ func (p *Project) ListLanguages() ([]Language, error) {
 	}
 	ls := make([]Language, len(res.Languages))
 	for i, l := range res.Languages {
-		ls[i] = Language{Project: p, Code: l.Code}
+		ls[i] = Language{
+			Project:      p,
+			Code:         l.Code,
+			Name:         l.Name,
+			Translations: l.Translations,
+			Percentage:   l.Percentage,
+			Updated:      l.Updated.Time,
+		}
 	}
 	return ls, nil
 }
@@ -39,7 +56,8 @@ func (p *Project) AddLanguage(code string) error {
 	return p.post("/languages/add", map[string]string{"language": code}, nil, nil)
 }
 
-// Update inserts or overwrites translations for a language
+// Update inserts or overwrites translations for a language. The translation
+// validators of the project are run first, see AddTranslationValidator.
 // TODO: add fuzzy_trigger
 func (l *Language) Update(terms []TermTranslation) (CountResult, error) {
 	var res UploadResult
@@ -54,6 +72,9 @@ func (l *Language) Update(terms []TermTranslation) (CountResult, error) {
 		}
 		return res.Translations, ErrTranslationInvalid
 	}
+	if err := l.validateTranslations(terms); err != nil {
+		return res.Translations, err
+	}
 	// Encode and send translations
 	ts, err := json.Marshal(terms)
 	if err != nil {
@@ -87,8 +108,9 @@ type languagesResult struct {
 }
 
 type language struct {
-	Name         string  `json:"name"`
-	Code         string  `json:"code"`
-	Translations int     `json:"translations"`
-	Percentage   float32 `json:"percentage"`
+	Name         string       `json:"name"`
+	Code         string       `json:"code"`
+	Translations int          `json:"translations"`
+	Percentage   float32      `json:"percentage"`
+	Updated      poEditorTime `json:"updated"`
 }
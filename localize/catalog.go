@@ -0,0 +1,224 @@
+// Package localize serves translations at runtime.
+//
+// A Catalog holds the translations of several languages, loaded from
+// exported files or directly from POEditor. A Localizer looks up messages in
+// a chain of languages, selecting plural forms by the CLDR rules of the
+// language the message was found in:
+//
+//	c := localize.NewCatalog("en")
+//	if err := c.LoadFS(locales, "locales/{lang}.json"); err != nil {
+//		return err
+//	}
+//	l := c.Localizer("pt-BR") // pt-BR, then pt, then en
+//	l.T("welcome")
+//	l.N("%d new messages", n)
+//	l.Context("menu").T("Open")
+package localize
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/blacksails/poeditor"
+)
+
+// langPlaceholder is replaced by the language code in file templates
+const langPlaceholder = "{lang}"
+
+// messages holds the translations of a language, which are strings or
+// poeditor.Plural values
+type messages map[poeditor.TermBase]interface{}
+
+// snapshot holds the messages of every language by normalized code. It is
+// never modified once stored in a Catalog.
+type snapshot map[string]messages
+
+// Catalog holds translations for several languages. It is safe for
+// concurrent use, and readers are never blocked by updates.
+type Catalog struct {
+	reference string
+	// mu serializes updates, which replace the snapshot
+	mu   sync.Mutex
+	data atomic.Value
+}
+
+// NewCatalog returns an empty catalog. The reference language ends every
+// fallback chain, and may be empty.
+func NewCatalog(reference string) *Catalog {
+	c := &Catalog{reference: normalize(reference)}
+	c.data.Store(snapshot{})
+	return c
+}
+
+func (c *Catalog) load() snapshot {
+	return c.data.Load().(snapshot)
+}
+
+// update copies the snapshot, lets f change the copy and stores it
+func (c *Catalog) update(f func(s snapshot)) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	old := c.load()
+	s := make(snapshot, len(old))
+	for lang, msgs := range old {
+		s[lang] = msgs
+	}
+	f(s)
+	c.data.Store(s)
+}
+
+// Add adds translations to a language, replacing existing translations of the
+// same terms. Empty translations are ignored, so lookups fall back to the
+// next language.
+func (c *Catalog) Add(lang string, terms []poeditor.TermTranslated) {
+	lang = normalize(lang)
+	c.update(func(s snapshot) {
+		msgs := make(messages, len(s[lang])+len(terms))
+		for k, v := range s[lang] {
+			msgs[k] = v
+		}
+		addMessages(msgs, terms)
+		s[lang] = msgs
+	})
+}
+
+// Set replaces all translations of a language
+func (c *Catalog) Set(lang string, terms []poeditor.TermTranslated) {
+	msgs := make(messages, len(terms))
+	addMessages(msgs, terms)
+	c.update(func(s snapshot) {
+		s[normalize(lang)] = msgs
+	})
+}
+
+func addMessages(msgs messages, terms []poeditor.TermTranslated) {
+	for _, t := range terms {
+		if !poeditor.EmptyContent(t.Translation.Content) {
+			msgs[t.TermBase] = t.Translation.Content
+		}
+	}
+}
+
+// Languages returns the normalized codes of the languages in the catalog
+func (c *Catalog) Languages() []string {
+	s := c.load()
+	langs := make([]string, 0, len(s))
+	for lang := range s {
+		langs = append(langs, lang)
+	}
+	sort.Strings(langs)
+	return langs
+}
+
+// LoadFS loads the files matching a template such as locales/{lang}.json,
+// where {lang} is the language code. The format of the files is determined
+// by their extension.
+func (c *Catalog) LoadFS(fsys fs.FS, template string) error {
+	if !strings.Contains(template, langPlaceholder) {
+		return fmt.Errorf("localize: template %q does not contain %s", template, langPlaceholder)
+	}
+	names, err := fs.Glob(fsys, strings.Replace(template, langPlaceholder, "*", -1))
+	if err != nil {
+		return err
+	}
+	pattern := regexp.MustCompile("^" + strings.Replace(regexp.QuoteMeta(template),
+		regexp.QuoteMeta(langPlaceholder), "([^/]+)", -1) + "$")
+	for _, name := range names {
+		m := pattern.FindStringSubmatch(name)
+		if m == nil {
+			continue
+		}
+		terms, err := decodeFile(fsys, name)
+		if err != nil {
+			return err
+		}
+		c.Add(m[1], terms)
+	}
+	return nil
+}
+
+// LoadDir loads the files in dir matching a template, see LoadFS
+func (c *Catalog) LoadDir(dir, template string) error {
+	return c.LoadFS(os.DirFS(dir), template)
+}
+
+func decodeFile(fsys fs.FS, name string) ([]poeditor.TermTranslated, error) {
+	format := poeditor.FormatFromFilename(path.Base(name))
+	if format == "" {
+		return nil, fmt.Errorf("localize: %s: unknown file format", name)
+	}
+	f, err := fsys.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	terms, err := poeditor.Decode(f, format)
+	if err != nil {
+		return nil, fmt.Errorf("localize: %s: %w", name, err)
+	}
+	return terms, nil
+}
+
+// LoadLanguage replaces the translations of a language with those listed by
+// Language.ListTerms
+func (c *Catalog) LoadLanguage(l *poeditor.Language) error {
+	terms, err := l.ListTerms()
+	if err != nil {
+		return err
+	}
+	c.Set(l.Code, terms)
+	return nil
+}
+
+// LoadProject loads the translations of every language of a project
+func (c *Catalog) LoadProject(p *poeditor.Project) error {
+	langs, err := p.ListLanguages()
+	if err != nil {
+		return err
+	}
+	for i := range langs {
+		if err := c.LoadLanguage(&langs[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// normalize turns language codes such as pt_BR into pt-br
+func normalize(lang string) string {
+	return strings.ToLower(strings.Replace(lang, "_", "-", -1))
+}
+
+// chain returns the languages to look up for the given languages: each
+// language is followed by its parents, e.g. pt-br by pt, and the reference
+// language comes last
+func (c *Catalog) chain(langs []string) []string {
+	var chain []string
+	seen := make(map[string]bool)
+	add := func(lang string) {
+		if lang != "" && !seen[lang] {
+			seen[lang] = true
+			chain = append(chain, lang)
+		}
+	}
+	for _, lang := range langs {
+		lang = normalize(lang)
+		for lang != "" {
+			add(lang)
+			i := strings.LastIndexByte(lang, '-')
+			if i < 0 {
+				break
+			}
+			lang = lang[:i]
+		}
+	}
+	add(c.reference)
+	return chain
+}
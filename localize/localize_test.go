@@ -0,0 +1,196 @@
+package localize_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"sync"
+	"testing"
+	"testing/fstest"
+
+	"github.com/blacksails/poeditor"
+	"github.com/blacksails/poeditor/internal/termtest"
+	"github.com/blacksails/poeditor/localize"
+)
+
+var locales = fstest.MapFS{
+	"locales/en.json": {Data: []byte(`{
+		"welcome": "Welcome",
+		"bye": "Goodbye",
+		"%d files": {"one": "%d file", "other": "%d files"},
+		"menu": {"Open": "Open"}
+	}`)},
+	"locales/pt.json": {Data: []byte(`{
+		"welcome": "Bem-vindo",
+		"%d files": {"one": "%d arquivo", "other": "%d arquivos"}
+	}`)},
+	"locales/pt_BR.json": {Data: []byte(`{"welcome": "Bem-vindo ao Brasil", "bye": ""}`)},
+	"locales/ru.json": {Data: []byte(`{
+		"%d files": {"one": "%d файл", "few": "%d файла", "many": "%d файлов", "other": "%d файла"},
+		"menu": {"Open": "Открыть"}
+	}`)},
+	"locales/README.md": {Data: []byte("not a catalog")},
+}
+
+func TestLocalizer(t *testing.T) {
+	c := localize.NewCatalog("en")
+	if err := c.LoadFS(locales, "locales/{lang}.json"); err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if got := c.Languages(); !reflect.DeepEqual(got, []string{"en", "pt", "pt-br", "ru"}) {
+		t.Errorf("Unexpected languages %v", got)
+	}
+	br := c.Localizer("pt-BR")
+	ru := c.Localizer("ru")
+	tests := []struct {
+		got, want string
+	}{
+		{br.T("welcome"), "Bem-vindo ao Brasil"},
+		{br.T("bye"), "Goodbye"},
+		{br.T("missing %s", "key"), "missing key"},
+		{br.N("%d files", 0), "0 arquivo"},
+		{br.N("%d files", 2), "2 arquivos"},
+		{c.Localizer("en").N("%d files", 1), "1 file"},
+		{c.Localizer("en").N("%d files", "1.0", 1), "1 files"},
+		{ru.N("%d files", 21), "21 файл"},
+		{ru.N("%d files", 3), "3 файла"},
+		{ru.N("%d files", 11), "11 файлов"},
+		{ru.N("%d files", 1.5, 2), "2 файла"},
+		{ru.Context("menu").T("Open"), "Открыть"},
+		{ru.T("Open"), "Open"},
+		{br.Context("menu").T("Open"), "Open"},
+	}
+	for i, test := range tests {
+		if test.got != test.want {
+			t.Errorf("%d: expected %q, got %q", i, test.want, test.got)
+		}
+	}
+	if got := br.Languages(); !reflect.DeepEqual(got, []string{"pt-br", "pt", "en"}) {
+		t.Errorf("Unexpected fallback chain %v", got)
+	}
+}
+
+func TestRefresherLoadSnapshot(t *testing.T) {
+	name := filepath.Join(t.TempDir(), "snapshot.json")
+	err := os.WriteFile(name, []byte(`{"languages": {"de": {
+		"updated": "2024-01-02T03:04:05Z",
+		"terms": [
+			{"term": "welcome", "translation": {"content": "Willkommen"}},
+			{"term": "%d files", "translation": {"content": {"one": "%d Datei", "other": "%d Dateien"}}}
+		]
+	}}}`), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := localize.Refresher{Catalog: localize.NewCatalog("de"), Snapshot: name}
+	r.Catalog.Add("de", []poeditor.TermTranslated{termtest.Translated("bundled", "Mitgeliefert")})
+	r.Catalog.Add("fr", []poeditor.TermTranslated{termtest.Translated("welcome", "Bienvenue")})
+	if err := r.LoadSnapshot(); err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	// The snapshot replaces German and keeps French
+	if got := r.Catalog.Localizer("de").T("bundled"); got != "bundled" {
+		t.Errorf("Expected the snapshot to replace German, got %q", got)
+	}
+	if got := r.Catalog.Localizer("fr").T("welcome"); got != "Bienvenue" {
+		t.Errorf("Expected French to be kept, got %q", got)
+	}
+	l := r.Catalog.Localizer("de-AT")
+	if got := l.T("welcome"); got != "Willkommen" {
+		t.Errorf("Unexpected translation %q", got)
+	}
+	if got := l.N("%d files", 3); got != "3 Dateien" {
+		t.Errorf("Unexpected plural %q", got)
+	}
+}
+
+func TestRefresherRefresh(t *testing.T) {
+	var (
+		mu        sync.Mutex
+		languages = map[string]string{"de": "2024-01-01T00:00:00+0000", "fr": "2024-01-01T00:00:00+0000"}
+		lists     = make(map[string]int)
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		var result interface{}
+		switch r.URL.Path {
+		case "/languages/list":
+			var ls []map[string]string
+			for code, updated := range languages {
+				ls = append(ls, map[string]string{"code": code, "updated": updated})
+			}
+			result = map[string]interface{}{"languages": ls}
+		case "/terms/list":
+			code := r.FormValue("language")
+			lists[code]++
+			result = map[string]interface{}{"terms": []map[string]interface{}{
+				{"term": "welcome", "translation": map[string]string{"content": code + " " + languages[code]}},
+			}}
+		default:
+			t.Errorf("Unexpected request to %s", r.URL.Path)
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"response": map[string]string{"status": "success", "code": "200"},
+			"result":   result,
+		})
+	}))
+	defer srv.Close()
+	snapshot := filepath.Join(t.TempDir(), "snapshot.json")
+	r := localize.Refresher{
+		Catalog:  localize.NewCatalog("de"),
+		Project:  poeditor.NewWithEndpoint("token", srv.URL).Project(1),
+		Snapshot: snapshot,
+	}
+	refresh := func(step string, expectedLists map[string]int, expectedLanguages []string) {
+		t.Helper()
+		if err := r.Refresh(); err != nil {
+			t.Fatalf("%s: unexpected error: %s", step, err)
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		if !reflect.DeepEqual(lists, expectedLists) {
+			t.Errorf("%s: expected term lists %v, got %v", step, expectedLists, lists)
+		}
+		if got := r.Catalog.Languages(); !reflect.DeepEqual(got, expectedLanguages) {
+			t.Errorf("%s: expected languages %v, got %v", step, expectedLanguages, got)
+		}
+		b, err := os.ReadFile(snapshot)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %s", step, err)
+		}
+		var f struct {
+			Languages map[string]json.RawMessage `json:"languages"`
+		}
+		if err := json.Unmarshal(b, &f); err != nil {
+			t.Fatalf("%s: unexpected error: %s", step, err)
+		}
+		var saved []string
+		for code := range f.Languages {
+			saved = append(saved, code)
+		}
+		sort.Strings(saved)
+		if !reflect.DeepEqual(saved, expectedLanguages) {
+			t.Errorf("%s: expected snapshot languages %v, got %v", step, expectedLanguages, saved)
+		}
+	}
+
+	refresh("first", map[string]int{"de": 1, "fr": 1}, []string{"de", "fr"})
+	refresh("unchanged", map[string]int{"de": 1, "fr": 1}, []string{"de", "fr"})
+
+	mu.Lock()
+	languages["de"] = "2024-02-01T00:00:00+0000"
+	delete(languages, "fr")
+	mu.Unlock()
+	refresh("updated and removed", map[string]int{"de": 2, "fr": 1}, []string{"de"})
+	if got := r.Catalog.Localizer("de").T("welcome"); got != "de 2024-02-01T00:00:00+0000" {
+		t.Errorf("Expected the updated translation, got %q", got)
+	}
+	if got := r.Catalog.Localizer("fr").T("welcome"); got != "de 2024-02-01T00:00:00+0000" {
+		t.Errorf("Expected the removed language to fall back to the reference, got %q", got)
+	}
+}
@@ -0,0 +1,131 @@
+package localize
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/blacksails/poeditor"
+	"github.com/blacksails/poeditor/internal/cldr"
+)
+
+// Localizer looks up messages in a chain of languages. It reads the current
+// translations of its catalog on every lookup.
+type Localizer struct {
+	catalog *Catalog
+	langs   []string
+	context string
+}
+
+// Localizer returns a localizer for the languages in order of preference.
+// Each language falls back to its parent languages, and the chain ends with
+// the reference language of the catalog.
+func (c *Catalog) Localizer(langs ...string) *Localizer {
+	return &Localizer{catalog: c, langs: c.chain(langs)}
+}
+
+// Languages returns the fallback chain of the localizer
+func (l *Localizer) Languages() []string {
+	return append([]string(nil), l.langs...)
+}
+
+// Context returns a localizer looking up terms with the given context, which
+// matches TermBase.Context
+func (l *Localizer) Context(context string) *Localizer {
+	c := *l
+	c.context = context
+	return &c
+}
+
+// lookup finds the first translation of the key in the fallback chain, and
+// returns it with its language
+func (l *Localizer) lookup(key string) (interface{}, string, bool) {
+	s := l.catalog.load()
+	term := poeditor.TermBase{Term: key, Context: l.context}
+	for _, lang := range l.langs {
+		if c, ok := s[lang][term]; ok {
+			return c, lang, true
+		}
+	}
+	return nil, "", false
+}
+
+// T returns the translation of the key, formatted with args by fmt.Sprintf
+// when any are given. The key itself is returned when no language has a
+// translation. Plural translations yield their other form.
+func (l *Localizer) T(key string, args ...interface{}) string {
+	msg := key
+	if c, _, ok := l.lookup(key); ok {
+		switch c := c.(type) {
+		case string:
+			msg = c
+		case poeditor.Plural:
+			msg = c.Other
+		}
+	}
+	if len(args) == 0 {
+		return msg
+	}
+	return fmt.Sprintf(msg, args...)
+}
+
+// N returns the plural form of the key for count, which is an integer, a
+// float or a decimal string such as "1.50". The form is selected by the CLDR
+// rules of the language the translation was found in, falling back to the
+// other form. The message is formatted with args, or with count when no args
+// are given and the message has a verb.
+func (l *Localizer) N(key string, count interface{}, args ...interface{}) string {
+	msg := key
+	if c, lang, ok := l.lookup(key); ok {
+		switch c := c.(type) {
+		case string:
+			msg = c
+		case poeditor.Plural:
+			msg = pluralForm(c, cldr.Plural(lang, operands(count)))
+		}
+	}
+	if len(args) == 0 {
+		if !strings.Contains(msg, "%") {
+			return msg
+		}
+		args = []interface{}{count}
+	}
+	return fmt.Sprintf(msg, args...)
+}
+
+// pluralForm returns the form of a category, or the other form if it is not
+// set
+func pluralForm(p poeditor.Plural, category string) string {
+	if form := p.Form(category); form != "" {
+		return form
+	}
+	return p.Other
+}
+
+// operands returns the plural operands of a count. Unsupported values yield
+// the operands of zero.
+func operands(count interface{}) cldr.Operands {
+	var s string
+	switch n := count.(type) {
+	case int:
+		return cldr.IntOperands(int64(n))
+	case int8:
+		return cldr.IntOperands(int64(n))
+	case int16:
+		return cldr.IntOperands(int64(n))
+	case int32:
+		return cldr.IntOperands(int64(n))
+	case int64:
+		return cldr.IntOperands(n)
+	case uint, uint8, uint16, uint32, uint64:
+		s = fmt.Sprint(n)
+	case float32:
+		s = strconv.FormatFloat(float64(n), 'f', -1, 32)
+	case float64:
+		s = strconv.FormatFloat(n, 'f', -1, 64)
+	case string:
+		s = n
+	}
+	o, _ := cldr.ParseOperands(s)
+	return o
+}
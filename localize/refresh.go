@@ -0,0 +1,210 @@
+package localize
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/blacksails/poeditor"
+)
+
+// DefaultRefreshInterval is used by Refresher.Run when no interval is set
+const DefaultRefreshInterval = 5 * time.Minute
+
+// Refresher keeps a catalog up to date with a POEditor project. A language
+// is only downloaded again when its updated time changes, and the catalog
+// is swapped in one step per refresh, so readers never see a partial update.
+//
+// With a snapshot file the last good translations survive restarts: they
+// are saved after every successful refresh and loaded when the API cannot be
+// reached.
+type Refresher struct {
+	Catalog *Catalog
+	Project *poeditor.Project
+	// Interval is the time between refreshes, DefaultRefreshInterval by
+	// default
+	Interval time.Duration
+	// Snapshot is the path of the snapshot file, no snapshot is kept when
+	// it is empty
+	Snapshot string
+	// OnError is called with the errors of background refreshes
+	OnError func(error)
+
+	mu      sync.Mutex
+	updated map[string]time.Time
+}
+
+// snapshotFile is the content of a snapshot file
+type snapshotFile struct {
+	Languages map[string]snapshotLanguage `json:"languages"`
+}
+
+type snapshotLanguage struct {
+	Updated time.Time                  `json:"updated"`
+	Terms   []poeditor.TermTranslation `json:"terms"`
+}
+
+// Refresh lists the languages of the project and downloads the terms of
+// those updated since the last refresh. Languages removed from the project
+// are removed from the catalog and the snapshot.
+func (r *Refresher) Refresh() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	langs, err := r.Project.ListLanguages()
+	if err != nil {
+		return err
+	}
+	if r.updated == nil {
+		r.updated = make(map[string]time.Time)
+	}
+	changed := make(map[string][]poeditor.TermTranslated)
+	updated := make(map[string]time.Time)
+	current := make(map[string]bool, len(langs))
+	for i := range langs {
+		l := &langs[i]
+		current[l.Code] = true
+		if u, ok := r.updated[l.Code]; ok && u.Equal(l.Updated) {
+			continue
+		}
+		terms, err := l.ListTerms()
+		if err != nil {
+			return err
+		}
+		changed[l.Code] = terms
+		updated[l.Code] = l.Updated
+	}
+	var removed []string
+	for code := range r.updated {
+		if !current[code] {
+			removed = append(removed, code)
+		}
+	}
+	if len(changed) == 0 && len(removed) == 0 {
+		return nil
+	}
+	r.Catalog.update(func(s snapshot) {
+		for _, code := range removed {
+			delete(s, normalize(code))
+		}
+		for code, terms := range changed {
+			msgs := make(messages, len(terms))
+			addMessages(msgs, terms)
+			s[normalize(code)] = msgs
+		}
+	})
+	for _, code := range removed {
+		delete(r.updated, code)
+	}
+	for code, u := range updated {
+		r.updated[code] = u
+	}
+	if r.Snapshot == "" {
+		return nil
+	}
+	return r.save()
+}
+
+// save writes the catalog to the snapshot file. The file is replaced
+// atomically, so a crash never leaves a partial snapshot.
+func (r *Refresher) save() error {
+	s := r.Catalog.load()
+	f := snapshotFile{Languages: make(map[string]snapshotLanguage)}
+	for code, u := range r.updated {
+		msgs := s[normalize(code)]
+		terms := make([]poeditor.TermTranslation, 0, len(msgs))
+		for term, content := range msgs {
+			terms = append(terms, poeditor.TermTranslation{
+				TermBase:    term,
+				Translation: poeditor.Translation{Content: content},
+			})
+		}
+		f.Languages[code] = snapshotLanguage{Updated: u, Terms: terms}
+	}
+	b, err := json.Marshal(f)
+	if err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(r.Snapshot), filepath.Base(r.Snapshot)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(b); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), r.Snapshot)
+}
+
+// LoadSnapshot loads the translations of the snapshot file into the
+// catalog. Languages in the snapshot replace those in the catalog, and other
+// languages of the catalog are kept. The next refresh only downloads
+// languages updated since the snapshot was saved.
+func (r *Refresher) LoadSnapshot() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	b, err := os.ReadFile(r.Snapshot)
+	if err != nil {
+		return err
+	}
+	var f snapshotFile
+	if err := json.Unmarshal(b, &f); err != nil {
+		return err
+	}
+	r.updated = make(map[string]time.Time)
+	r.Catalog.update(func(s snapshot) {
+		for code, l := range f.Languages {
+			msgs := make(messages, len(l.Terms))
+			for _, t := range l.Terms {
+				if !poeditor.EmptyContent(t.Translation.Content) {
+					msgs[t.TermBase] = t.Translation.Content
+				}
+			}
+			s[normalize(code)] = msgs
+			r.updated[code] = l.Updated
+		}
+	})
+	return nil
+}
+
+// Run refreshes the catalog until the context is done. When the first
+// refresh fails the snapshot file is loaded instead. Errors are passed to
+// OnError, and Run only returns the error of the context.
+func (r *Refresher) Run(ctx context.Context) error {
+	if err := r.Refresh(); err != nil {
+		r.report(err)
+		if r.Snapshot != "" {
+			if err := r.LoadSnapshot(); err != nil && !os.IsNotExist(err) {
+				r.report(err)
+			}
+		}
+	}
+	interval := r.Interval
+	if interval <= 0 {
+		interval = DefaultRefreshInterval
+	}
+	t := time.NewTicker(interval)
+	defer t.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-t.C:
+			if err := r.Refresh(); err != nil {
+				r.report(err)
+			}
+		}
+	}
+}
+
+func (r *Refresher) report(err error) {
+	if r.OnError != nil {
+		r.OnError(err)
+	}
+}
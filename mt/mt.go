@@ -0,0 +1,328 @@
+// Package mt pre-fills untranslated terms with machine translations. Any
+// provider can be plugged in by implementing Translator. Translations are
+// written marked fuzzy, so they are reviewed before they are used:
+//
+//	pf := mt.Prefiller{Translator: myProvider}
+//	res, err := pf.Prefill(poeditor.Language{Project: p, Code: "de"}, "en")
+//
+// Placeholders, tags and character entities are replaced with <ph id="n"/>
+// tokens before the texts are sent, and restored in the translations.
+// Translations which lose or duplicate a token are rejected instead of
+// being written.
+package mt
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/blacksails/poeditor"
+	"github.com/blacksails/poeditor/internal/cldr"
+	"github.com/blacksails/poeditor/internal/placeholder"
+)
+
+// DefaultBatchSize is the number of texts sent to the translator at once
+// when Prefiller.BatchSize is zero
+const DefaultBatchSize = 50
+
+// ErrTextCount is returned when a translator returns another number of
+// translations than it was given texts
+var ErrTextCount = errors.New("mt: translator returned a wrong number of translations")
+
+// Translator translates texts from the source to the target language. It
+// returns one translation per text, in the same order.
+type Translator interface {
+	Translate(source, target string, texts []string) ([]string, error)
+}
+
+// TranslatorFunc adapts a function to the Translator interface
+type TranslatorFunc func(source, target string, texts []string) ([]string, error)
+
+// Translate calls f
+func (f TranslatorFunc) Translate(source, target string, texts []string) ([]string, error) {
+	return f(source, target, texts)
+}
+
+// Stub is a deterministic Translator for tests. It prefixes texts with the
+// target language, e.g. "[de] Hello", and records the batches it is given.
+type Stub struct {
+	Batches [][]string
+}
+
+// Translate returns the texts prefixed with the target language
+func (s *Stub) Translate(source, target string, texts []string) ([]string, error) {
+	s.Batches = append(s.Batches, texts)
+	translations := make([]string, len(texts))
+	for i, t := range texts {
+		translations[i] = "[" + target + "] " + t
+	}
+	return translations, nil
+}
+
+// Rejection is a machine translation which was not written
+type Rejection struct {
+	poeditor.TermBase
+	// Form is the plural category of the translation, if it is a plural
+	Form string `json:"form,omitempty"`
+	// Text is the translation as returned by the translator
+	Text    string `json:"text"`
+	Message string `json:"message"`
+}
+
+func (r Rejection) String() string {
+	name := r.Term
+	if r.Context != "" {
+		name += " (" + r.Context + ")"
+	}
+	if r.Form != "" {
+		name += " [" + r.Form + "]"
+	}
+	return name + ": " + r.Message
+}
+
+// Result is the outcome of pre-filling a language
+type Result struct {
+	// Translations are the machine translations, marked fuzzy
+	Translations []poeditor.TermTranslation
+	// Rejected are the terms whose translation lost placeholders or markup
+	Rejected []Rejection
+	// Count is the result of Language.Update, which is zero for
+	// Prefiller.Translate
+	Count poeditor.CountResult
+}
+
+// Prefiller machine translates untranslated terms
+type Prefiller struct {
+	Translator Translator
+	// BatchSize is the number of texts sent to the translator at once,
+	// DefaultBatchSize when zero
+	BatchSize int
+}
+
+// unit is a text sent to the translator
+type unit struct {
+	term int
+	form string
+	// text is the source text with protected parts replaced by tokens
+	text string
+	// protected are the parts replaced by tokens, by token id
+	protected []string
+}
+
+// tokenPattern matches the tokens of protected parts, allowing for the
+// spacing translators may change
+var tokenPattern = regexp.MustCompile(`<ph\s+id\s*=\s*"(\d+)"\s*/?>`)
+
+// Translate machine translates the target terms which are untranslated, from
+// the source terms matched by TermBase. Source terms without translation
+// are translated from the term itself, and its plural for terms with a
+// plural. Plural translations get the plural categories of the target
+// language, translated from the same category of the source when it covers
+// the same numbers in both languages, and from the other form otherwise.
+func (pf Prefiller) Translate(sourceLang string, source []poeditor.TermTranslated, targetLang string, target []poeditor.TermTranslated) (Result, error) {
+	var res Result
+	sources := make(map[poeditor.TermBase]poeditor.TermTranslated, len(source))
+	for _, t := range source {
+		sources[t.TermBase] = t
+	}
+	var (
+		units []unit
+		terms []poeditor.TermBase
+	)
+	for _, t := range target {
+		if !poeditor.EmptyContent(t.Translation.Content) {
+			continue
+		}
+		src, ok := sources[t.TermBase]
+		if !ok {
+			src = t
+			src.Translation = poeditor.Translation{}
+		}
+		forms := sourceForms(src, sourceLang, targetLang)
+		if forms == nil {
+			continue
+		}
+		for _, f := range forms {
+			units = append(units, protect(len(terms), f.Category, f.Text))
+		}
+		terms = append(terms, t.TermBase)
+	}
+	translated, err := pf.translate(sourceLang, targetLang, units)
+	if err != nil {
+		return res, err
+	}
+	contents := make([]interface{}, len(terms))
+	rejected := make([]bool, len(terms))
+	for i, u := range units {
+		text, err := restore(translated[i], u.protected)
+		if err != nil {
+			rejected[u.term] = true
+			res.Rejected = append(res.Rejected, Rejection{
+				TermBase: terms[u.term],
+				Form:     u.form,
+				Text:     translated[i],
+				Message:  err.Error(),
+			})
+			continue
+		}
+		if u.form == "" {
+			contents[u.term] = text
+			continue
+		}
+		p, _ := contents[u.term].(poeditor.Plural)
+		p.SetForm(u.form, text)
+		contents[u.term] = p
+	}
+	for i, tb := range terms {
+		if rejected[i] {
+			continue
+		}
+		res.Translations = append(res.Translations, poeditor.TermTranslation{
+			TermBase:    tb,
+			Translation: poeditor.Translation{Content: contents[i], Fuzzy: 1},
+		})
+	}
+	return res, nil
+}
+
+// translate sends the texts of the units to the translator in batches
+func (pf Prefiller) translate(source, target string, units []unit) ([]string, error) {
+	size := pf.BatchSize
+	if size <= 0 {
+		size = DefaultBatchSize
+	}
+	translated := make([]string, 0, len(units))
+	for start := 0; start < len(units); start += size {
+		end := start + size
+		if end > len(units) {
+			end = len(units)
+		}
+		texts := make([]string, end-start)
+		for i, u := range units[start:end] {
+			texts[i] = u.text
+		}
+		batch, err := pf.Translator.Translate(source, target, texts)
+		if err != nil {
+			return nil, err
+		}
+		if len(batch) != len(texts) {
+			return nil, fmt.Errorf("%w: sent %d texts, got %d", ErrTextCount, len(texts), len(batch))
+		}
+		translated = append(translated, batch...)
+	}
+	return translated, nil
+}
+
+// Prefill lists the terms of the language and of the source language,
+// machine translates the untranslated terms and writes them with
+// Language.Update, marked fuzzy. Rejected translations are not written.
+func (pf Prefiller) Prefill(l poeditor.Language, source string) (Result, error) {
+	src := poeditor.Language{Project: l.Project, Code: source}
+	sourceTerms, err := src.ListTerms()
+	if err != nil {
+		return Result{}, err
+	}
+	terms, err := l.ListTerms()
+	if err != nil {
+		return Result{}, err
+	}
+	res, err := pf.Translate(source, sourceTerms, l.Code, terms)
+	if err != nil || len(res.Translations) == 0 {
+		return res, err
+	}
+	res.Count, err = l.Update(res.Translations)
+	return res, err
+}
+
+// sourceForms returns the texts to translate for a term, with a category
+// per plural category of the target language for plurals. It returns nil
+// when there is no text to translate.
+func sourceForms(t poeditor.TermTranslated, sourceLang, targetLang string) []poeditor.PluralForm {
+	var p poeditor.Plural
+	switch c := t.Translation.Content.(type) {
+	case string:
+		if c != "" {
+			return []poeditor.PluralForm{{Text: c}}
+		}
+	case poeditor.Plural:
+		p = c
+	}
+	if p == (poeditor.Plural{}) {
+		if t.Plural == "" {
+			if t.Term.Term == "" {
+				return nil
+			}
+			return []poeditor.PluralForm{{Text: t.Term.Term}}
+		}
+		p = poeditor.Plural{One: t.Term.Term, Other: t.Plural}
+	}
+	var forms []poeditor.PluralForm
+	for _, c := range cldr.Categories(targetLang) {
+		text := p.Form(c)
+		if text == "" || !cldr.SameCategory(sourceLang, targetLang, c) {
+			// Categories missing in the source, or covering other numbers
+			// than in the target, are translated from its other form
+			text = p.Other
+		}
+		if text == "" {
+			text = p.One
+		}
+		if text != "" {
+			forms = append(forms, poeditor.PluralForm{Category: c, Text: text})
+		}
+	}
+	return forms
+}
+
+// protect replaces the placeholders and markup of s with tokens
+func protect(term int, category, s string) unit {
+	u := unit{term: term, form: category}
+	var (
+		b   strings.Builder
+		pos int
+	)
+	for i, r := range placeholder.Protected(s) {
+		b.WriteString(s[pos:r[0]])
+		b.WriteString(`<ph id="` + strconv.Itoa(i) + `"/>`)
+		u.protected = append(u.protected, s[r[0]:r[1]])
+		pos = r[1]
+	}
+	b.WriteString(s[pos:])
+	u.text = b.String()
+	return u
+}
+
+// restore replaces the tokens of a translation with the protected parts.
+// Every token must be kept exactly once.
+func restore(s string, protected []string) (string, error) {
+	seen := make([]bool, len(protected))
+	var err error
+	text := tokenPattern.ReplaceAllStringFunc(s, func(token string) string {
+		id, _ := strconv.Atoi(tokenPattern.FindStringSubmatch(token)[1])
+		switch {
+		case id >= len(protected):
+			err = fmt.Errorf("unknown token %s", token)
+		case seen[id]:
+			err = fmt.Errorf("duplicated %s", protected[id])
+		default:
+			seen[id] = true
+			return protected[id]
+		}
+		return token
+	})
+	if err != nil {
+		return "", err
+	}
+	var missing []string
+	for i, ok := range seen {
+		if !ok {
+			missing = append(missing, protected[i])
+		}
+	}
+	if len(missing) > 0 {
+		return "", fmt.Errorf("lost %s", strings.Join(missing, ", "))
+	}
+	return text, nil
+}
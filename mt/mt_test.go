@@ -0,0 +1,101 @@
+package mt_test
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/blacksails/poeditor"
+	"github.com/blacksails/poeditor/internal/termtest"
+	"github.com/blacksails/poeditor/mt"
+)
+
+func TestTranslate(t *testing.T) {
+	source := []poeditor.TermTranslated{
+		termtest.Translated("greeting", "Hello <b>%s</b>"),
+		termtest.Translated("done", "Done"),
+		termtest.Translated("files", poeditor.Plural{One: "{count} file", Other: "{count} files"}),
+	}
+	target := []poeditor.TermTranslated{
+		termtest.Translated("greeting", ""),
+		termtest.Translated("done", "Fertig"),
+		termtest.Translated("files", ""),
+		termtest.Translated("Untranslated source", ""),
+	}
+	stub := &mt.Stub{}
+	pf := mt.Prefiller{Translator: stub, BatchSize: 3}
+	res, err := pf.Translate("en", source, "ru", target)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	expected := []poeditor.TermTranslation{
+		{
+			TermBase:    poeditor.TermBase{Term: "greeting"},
+			Translation: poeditor.Translation{Content: "[ru] Hello <b>%s</b>", Fuzzy: 1},
+		},
+		{
+			TermBase: poeditor.TermBase{Term: "files"},
+			Translation: poeditor.Translation{Content: poeditor.Plural{
+				One:   "[ru] {count} files",
+				Few:   "[ru] {count} files",
+				Many:  "[ru] {count} files",
+				Other: "[ru] {count} files",
+			}, Fuzzy: 1},
+		},
+		{
+			TermBase:    poeditor.TermBase{Term: "Untranslated source"},
+			Translation: poeditor.Translation{Content: "[ru] Untranslated source", Fuzzy: 1},
+		},
+	}
+	if !reflect.DeepEqual(res.Translations, expected) {
+		t.Errorf("Expected %+v, got %+v", expected, res.Translations)
+	}
+	batches := [][]string{
+		{`Hello <ph id="0"/><ph id="1"/><ph id="2"/>`, `<ph id="0"/> files`, `<ph id="0"/> files`},
+		{`<ph id="0"/> files`, `<ph id="0"/> files`, "Untranslated source"},
+	}
+	if !reflect.DeepEqual(stub.Batches, batches) {
+		t.Errorf("Expected batches %q, got %q", batches, stub.Batches)
+	}
+
+	// The German one covers the same numbers as the English one
+	res, err = pf.Translate("en", source, "de", target)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	files := poeditor.Plural{One: "[de] {count} file", Other: "[de] {count} files"}
+	if len(res.Translations) != 3 || res.Translations[1].Translation.Content != files {
+		t.Errorf("Expected %+v, got %+v", files, res.Translations)
+	}
+}
+
+func TestTranslateRejected(t *testing.T) {
+	broken := mt.TranslatorFunc(func(source, target string, texts []string) ([]string, error) {
+		translations := make([]string, len(texts))
+		for i, s := range texts {
+			translations[i] = strings.Replace(s, `<ph id="1"/>`, "", 1)
+		}
+		return translations, nil
+	})
+	source := []poeditor.TermTranslated{termtest.Translated("a", "%s and %d"), termtest.Translated("b", "plain")}
+	target := []poeditor.TermTranslated{termtest.Translated("a", ""), termtest.Translated("b", "")}
+	res, err := mt.Prefiller{Translator: broken}.Translate("en", source, "de", target)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if len(res.Translations) != 1 || res.Translations[0].Term != "b" {
+		t.Errorf("Expected only b to be translated, got %+v", res.Translations)
+	}
+	if len(res.Rejected) != 1 || res.Rejected[0].String() != "a: lost %d" {
+		t.Errorf("Expected a to be rejected, got %+v", res.Rejected)
+	}
+
+	short := mt.TranslatorFunc(func(source, target string, texts []string) ([]string, error) {
+		return texts[1:], nil
+	})
+	_, err = mt.Prefiller{Translator: short}.Translate("en", source, "de", target)
+	if !errors.Is(err, mt.ErrTextCount) {
+		t.Errorf("Expected ErrTextCount, got %v", err)
+	}
+}
@@ -0,0 +1,199 @@
+package poeditor
+
+import (
+	"fmt"
+	"strings"
+)
+
+// flatContent returns translation content as a single string. Plurals are
+// written as ICU plural messages for formats without native plural support.
+func flatContent(c interface{}) (string, error) {
+	switch c := c.(type) {
+	case string:
+		return c, nil
+	case Plural:
+		return formatICUPlural("count", c), nil
+	case nil:
+		return "", nil
+	}
+	return "", ErrTranslationInvalid
+}
+
+// flatTerm is the inverse of flatContent. It builds a term from a key and a
+// value, turning ICU plural messages into a Plural translation.
+func flatTerm(key, value string) TermTranslated {
+	var t TermTranslated
+	t.Term.Term = key
+	t.Translation.Content = value
+	if _, p, ok := parseICUPlural(value); ok {
+		t.Plural = key
+		t.Translation.Content = p
+	}
+	return t
+}
+
+// pluralFields maps CLDR plural categories to the fields of a Plural
+func pluralFields(p *Plural) map[string]*string {
+	return map[string]*string{
+		"zero":  &p.Zero,
+		"one":   &p.One,
+		"two":   &p.Two,
+		"few":   &p.Few,
+		"many":  &p.Many,
+		"other": &p.Other,
+	}
+}
+
+// pluralValues maps CLDR plural categories to the values of a Plural
+func pluralValues(p Plural) map[string]string {
+	values := make(map[string]string)
+	for c, f := range pluralFields(&p) {
+		values[c] = *f
+	}
+	return values
+}
+
+// pluralForms returns the categories set on a Plural in CLDR order. Other is
+// always included.
+func pluralForms(p Plural) []string {
+	var forms []string
+	values := pluralValues(p)
+	for _, c := range []string{"zero", "one", "two", "few", "many"} {
+		if values[c] != "" {
+			forms = append(forms, c)
+		}
+	}
+	return append(forms, "other")
+}
+
+// icuSelector is a selector of an ICU plural argument and its message
+type icuSelector struct {
+	selector, msg string
+}
+
+// splitICUPlural splits messages that consist of a single ICU plural
+// argument into the argument name, the offset if any and the selectors
+func splitICUPlural(s string) (arg, offset string, selectors []icuSelector, ok bool) {
+	s = strings.TrimSpace(s)
+	if !strings.HasPrefix(s, "{") || icuBlockEnd(s, 0) != len(s)-1 {
+		return "", "", nil, false
+	}
+	parts := strings.SplitN(s[1:len(s)-1], ",", 3)
+	if len(parts) != 3 || strings.TrimSpace(parts[1]) != "plural" {
+		return "", "", nil, false
+	}
+	arg = strings.TrimSpace(parts[0])
+	rest := parts[2]
+	for {
+		rest = strings.TrimSpace(rest)
+		if rest == "" {
+			break
+		}
+		i := strings.IndexByte(rest, '{')
+		if i < 0 {
+			return "", "", nil, false
+		}
+		selector := strings.TrimSpace(rest[:i])
+		end := icuBlockEnd(rest, i)
+		if end < 0 {
+			return "", "", nil, false
+		}
+		msg := rest[i+1 : end]
+		rest = rest[end+1:]
+		if strings.HasPrefix(selector, "offset:") && len(selectors) == 0 {
+			// offset:n precedes the first selector
+			fields := strings.Fields(selector)
+			if len(fields) != 2 {
+				return "", "", nil, false
+			}
+			offset = strings.TrimSpace(strings.TrimPrefix(fields[0], "offset:"))
+			selector = fields[1]
+		}
+		if selector == "" || strings.ContainsAny(selector, " \t\n") {
+			return "", "", nil, false
+		}
+		selectors = append(selectors, icuSelector{selector, msg})
+	}
+	return arg, offset, selectors, true
+}
+
+// parseICUPlural parses messages that consist of a single ICU plural
+// argument with CLDR category selectors, e.g.
+// {count, plural, one{# item} other{# items}}. Messages with exact value
+// selectors such as =0, an offset or a repeated selector have no Plural
+// equivalent and are not parsed.
+func parseICUPlural(s string) (string, Plural, bool) {
+	var p Plural
+	arg, offset, selectors, ok := splitICUPlural(s)
+	if !ok || offset != "" {
+		return "", p, false
+	}
+	fields := pluralFields(&p)
+	seen := make(map[string]bool)
+	for _, sel := range selectors {
+		f, ok := fields[sel.selector]
+		if !ok || seen[sel.selector] {
+			return "", p, false
+		}
+		seen[sel.selector] = true
+		*f = sel.msg
+	}
+	return arg, p, true
+}
+
+// exactICUPlural reports whether s is an ICU plural message which
+// parseICUPlural leaves as text because of exact value selectors, an offset
+// or a repeated selector
+func exactICUPlural(s string) bool {
+	if _, _, _, ok := splitICUPlural(s); !ok {
+		return false
+	}
+	_, _, ok := parseICUPlural(s)
+	return !ok
+}
+
+// formatICUPlural is the inverse of parseICUPlural
+func formatICUPlural(arg string, p Plural) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "{%s, plural,", arg)
+	for _, c := range []struct{ selector, msg string }{
+		{"zero", p.Zero},
+		{"one", p.One},
+		{"two", p.Two},
+		{"few", p.Few},
+		{"many", p.Many},
+	} {
+		if c.msg != "" {
+			fmt.Fprintf(&b, " %s{%s}", c.selector, c.msg)
+		}
+	}
+	fmt.Fprintf(&b, " other{%s}}", p.Other)
+	return b.String()
+}
+
+// icuBlockEnd returns the index of the brace closing the one at start, or -1.
+// Apostrophe quoted braces are skipped.
+func icuBlockEnd(s string, start int) int {
+	depth := 0
+	quoted := false
+	for i := start; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case c == '\'':
+			if i+1 < len(s) && s[i+1] == '\'' {
+				i++
+			} else if quoted || (i+1 < len(s) && (s[i+1] == '{' || s[i+1] == '}')) {
+				quoted = !quoted
+			}
+		case quoted:
+		case c == '{':
+			depth++
+		case c == '}':
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return -1
+}
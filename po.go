@@ -0,0 +1,401 @@
+package poeditor
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/blacksails/poeditor/internal/cldr"
+)
+
+var (
+	poNPlurals = regexp.MustCompile(`nplurals\s*=\s*(\d+)`)
+	poLanguage = regexp.MustCompile(`(?m)^Language:\s*(\S+)`)
+)
+
+// poCategories returns the CLDR plural categories indexed by msgstr[n] in a
+// file of the language with the number of plural forms. Gettext indexes
+// plural forms while POEditor uses CLDR categories, so the categories of
+// files without a known language are only certain for up to two forms.
+func poCategories(language string, nplurals int) ([]string, error) {
+	if language != "" && cldr.Known(language) {
+		categories, _ := cldr.Gettext(language)
+		return categories, nil
+	}
+	switch nplurals {
+	case 1:
+		return []string{cldr.Other}, nil
+	case 2:
+		return []string{cldr.One, cldr.Other}, nil
+	}
+	return nil, fmt.Errorf("%d plural forms cannot be mapped without a known Language header", nplurals)
+}
+
+// ReadPO decodes a gettext .po or .pot file. Extracted comments (#.) and
+// translator comments (#) become the term comment, references (#:) the term
+// reference and the fuzzy flag marks the translation fuzzy. Obsolete entries
+// are skipped. Plural forms are mapped to CLDR categories by the Language
+// header, and translated forms which the language does not use are an error.
+func ReadPO(r io.Reader) ([]TermTranslated, error) {
+	terms, _, err := readPO(r, "")
+	return terms, err
+}
+
+// ReadPOLanguage decodes a gettext .po file of a language like ReadPO. The
+// language maps the plural forms of files without a Language header.
+func ReadPOLanguage(r io.Reader, language string) ([]TermTranslated, error) {
+	terms, _, err := readPO(r, language)
+	return terms, err
+}
+
+// readPO decodes a .po file, and returns the language of its Language header
+// or the given language
+func readPO(r io.Reader, language string) ([]TermTranslated, string, error) {
+	var (
+		terms    []TermTranslated
+		e        poEntry
+		field    *string
+		nplurals = 2
+		lineNo   int
+	)
+	flush := func() error {
+		defer func() {
+			e = poEntry{}
+			field = nil
+		}()
+		if e.empty() {
+			return nil
+		}
+		if e.msgid == "" && e.msgctxt == "" {
+			// The header entry
+			if m := poNPlurals.FindStringSubmatch(e.msgstr[0]); m != nil {
+				nplurals, _ = strconv.Atoi(m[1])
+			}
+			if m := poLanguage.FindStringSubmatch(e.msgstr[0]); m != nil {
+				language = m[1]
+			}
+			return nil
+		}
+		t, err := e.term(language, nplurals)
+		if err != nil {
+			return fmt.Errorf("po: line %d: %s", e.line, err)
+		}
+		terms = append(terms, t)
+		return nil
+	}
+	s := bufio.NewScanner(r)
+	s.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	for s.Scan() {
+		lineNo++
+		line := strings.TrimSpace(s.Text())
+		switch {
+		case line == "":
+			if err := flush(); err != nil {
+				return nil, "", err
+			}
+		case strings.HasPrefix(line, "#~"):
+			// Obsolete entries are not part of the project
+		case strings.HasPrefix(line, "#"):
+			// A comment after msgstr belongs to the next entry
+			if e.hasMsg {
+				if err := flush(); err != nil {
+					return nil, "", err
+				}
+			}
+			switch {
+			case strings.HasPrefix(line, "#."):
+				e.extracted = append(e.extracted, strings.TrimSpace(line[2:]))
+			case strings.HasPrefix(line, "#:"):
+				e.references = append(e.references, strings.TrimSpace(line[2:]))
+			case strings.HasPrefix(line, "#,"):
+				for _, f := range strings.Split(line[2:], ",") {
+					if strings.TrimSpace(f) == "fuzzy" {
+						e.fuzzy = true
+					}
+				}
+			case strings.HasPrefix(line, "#|"):
+			default:
+				e.comments = append(e.comments, strings.TrimSpace(line[1:]))
+			}
+		case strings.HasPrefix(line, `"`):
+			if field == nil {
+				return nil, "", fmt.Errorf("po: line %d: string without keyword", lineNo)
+			}
+			str, err := unquotePO(line)
+			if err != nil {
+				return nil, "", fmt.Errorf("po: line %d: %s", lineNo, err)
+			}
+			*field += str
+		default:
+			i := strings.IndexAny(line, " \t")
+			if i < 0 {
+				return nil, "", fmt.Errorf("po: line %d: unexpected %q", lineNo, line)
+			}
+			keyword := line[:i]
+			str, err := unquotePO(strings.TrimSpace(line[i:]))
+			if err != nil {
+				return nil, "", fmt.Errorf("po: line %d: %s", lineNo, err)
+			}
+			if keyword == "msgctxt" || (keyword == "msgid" && e.msgctxt == "") {
+				if e.hasMsg {
+					if err := flush(); err != nil {
+						return nil, "", err
+					}
+				}
+			}
+			if e.line == 0 {
+				e.line = lineNo
+			}
+			switch {
+			case keyword == "msgctxt":
+				field = &e.msgctxt
+			case keyword == "msgid":
+				field = &e.msgid
+			case keyword == "msgid_plural":
+				field = &e.msgidPlural
+			case keyword == "msgstr":
+				field = &e.msgstr[0]
+			case strings.HasPrefix(keyword, "msgstr[") && strings.HasSuffix(keyword, "]"):
+				n, err := strconv.Atoi(keyword[7 : len(keyword)-1])
+				if err != nil || n < 0 || n >= len(e.msgstr) {
+					return nil, "", fmt.Errorf("po: line %d: invalid plural index in %s", lineNo, keyword)
+				}
+				if n+1 > e.forms {
+					e.forms = n + 1
+				}
+				field = &e.msgstr[n]
+			default:
+				return nil, "", fmt.Errorf("po: line %d: unknown keyword %s", lineNo, keyword)
+			}
+			if strings.HasPrefix(keyword, "msgstr") {
+				e.hasMsg = true
+			}
+			*field = str
+		}
+	}
+	if err := s.Err(); err != nil {
+		return nil, "", err
+	}
+	if err := flush(); err != nil {
+		return nil, "", err
+	}
+	return terms, language, nil
+}
+
+// poWriteCategories returns the plural categories which WritePOLanguage can
+// write for a language
+func poWriteCategories(language string) []string {
+	categories, _ := cldr.Gettext(language)
+	return categories
+}
+
+// WritePO encodes terms as a gettext .po file without a language. Plurals
+// are written with the one and other forms, see WritePOLanguage for other
+// languages.
+func WritePO(w io.Writer, terms []TermTranslated) error {
+	return WritePOLanguage(w, "", terms)
+}
+
+// WritePOLanguage encodes terms of a language as a gettext .po file. The
+// Language and Plural-Forms headers are derived from the CLDR plural rules of
+// the language, and plural forms which the language does not use are an
+// error.
+func WritePOLanguage(w io.Writer, language string, terms []TermTranslated) error {
+	categories, expr := cldr.Gettext(language)
+	for _, t := range terms {
+		p, ok := t.Translation.Content.(Plural)
+		if !ok {
+			continue
+		}
+		values := pluralValues(p)
+		for _, c := range pluralForms(p) {
+			if values[c] != "" && !containsString(categories, c) {
+				if language == "" {
+					return fmt.Errorf("po: %s: the %s form needs a language to be written", t.Term.Term, c)
+				}
+				return fmt.Errorf("po: %s: the %s form is not used by %s", t.Term.Term, c, language)
+			}
+		}
+	}
+	bw := bufio.NewWriter(w)
+	bw.WriteString("msgid \"\"\nmsgstr \"\"\n")
+	if language != "" {
+		fmt.Fprintf(bw, "\"Language: %s\\n\"\n", language)
+	}
+	bw.WriteString("\"Content-Type: text/plain; charset=UTF-8\\n\"\n")
+	fmt.Fprintf(bw, "\"Plural-Forms: nplurals=%d; plural=(%s);\\n\"\n", len(categories), expr)
+	for _, t := range terms {
+		bw.WriteString("\n")
+		for _, c := range strings.Split(t.Comment, "\n") {
+			if c != "" {
+				bw.WriteString("#. " + c + "\n")
+			}
+		}
+		if t.Reference != "" {
+			bw.WriteString("#: " + t.Reference + "\n")
+		}
+		if t.Translation.Fuzzy == 1 {
+			bw.WriteString("#, fuzzy\n")
+		}
+		if t.Context != "" {
+			writePOString(bw, "msgctxt", t.Context)
+		}
+		writePOString(bw, "msgid", t.Term.Term)
+		switch c := t.Translation.Content.(type) {
+		case Plural:
+			plural := t.Plural
+			if plural == "" {
+				plural = t.Term.Term
+			}
+			writePOString(bw, "msgid_plural", plural)
+			values := pluralValues(c)
+			for i, category := range categories {
+				writePOString(bw, fmt.Sprintf("msgstr[%d]", i), values[category])
+			}
+		case string:
+			if t.Plural != "" {
+				writePOString(bw, "msgid_plural", t.Plural)
+				writePOString(bw, "msgstr[0]", c)
+				continue
+			}
+			writePOString(bw, "msgstr", c)
+		case nil:
+			writePOString(bw, "msgstr", "")
+		default:
+			return ErrTranslationInvalid
+		}
+	}
+	return bw.Flush()
+}
+
+type poEntry struct {
+	comments    []string
+	extracted   []string
+	references  []string
+	fuzzy       bool
+	msgctxt     string
+	msgid       string
+	msgidPlural string
+	msgstr      [6]string
+	forms       int
+	hasMsg      bool
+	// line is the line number of the first keyword
+	line int
+}
+
+func (e poEntry) empty() bool {
+	return !e.hasMsg && e.msgid == "" && e.msgctxt == "" && len(e.comments) == 0 &&
+		len(e.extracted) == 0 && len(e.references) == 0
+}
+
+func (e poEntry) term(language string, nplurals int) (TermTranslated, error) {
+	var t TermTranslated
+	t.Term.Term = e.msgid
+	t.Context = e.msgctxt
+	t.Plural = e.msgidPlural
+	t.Comment = strings.Join(append(e.extracted, e.comments...), "\n")
+	t.Reference = strings.Join(e.references, " ")
+	if e.fuzzy {
+		t.Translation.Fuzzy = 1
+	}
+	if e.msgidPlural == "" {
+		t.Translation.Content = e.msgstr[0]
+		return t, nil
+	}
+	// Untranslated forms need no category
+	categories, err := poCategories(language, nplurals)
+	var p Plural
+	fields := pluralFields(&p)
+	for i := 0; i < e.forms; i++ {
+		switch {
+		case e.msgstr[i] == "":
+		case err != nil:
+			return t, err
+		case i >= len(categories):
+			return t, fmt.Errorf("msgstr[%d] cannot be mapped to a plural category of %s", i, describePOLanguage(language))
+		default:
+			*fields[categories[i]] = e.msgstr[i]
+		}
+	}
+	t.Translation.Content = p
+	return t, nil
+}
+
+func describePOLanguage(language string) string {
+	if language == "" {
+		return "a file without language"
+	}
+	return language
+}
+
+func containsString(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+func writePOString(w *bufio.Writer, keyword, s string) {
+	if !strings.Contains(strings.TrimSuffix(s, "\n"), "\n") {
+		fmt.Fprintf(w, "%s %s\n", keyword, quotePO(s))
+		return
+	}
+	fmt.Fprintf(w, "%s \"\"\n", keyword)
+	lines := strings.SplitAfter(s, "\n")
+	for _, l := range lines {
+		if l != "" {
+			w.WriteString(quotePO(l) + "\n")
+		}
+	}
+}
+
+var poEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`, "\t", `\t`, "\r", `\r`)
+
+func quotePO(s string) string {
+	return `"` + poEscaper.Replace(s) + `"`
+}
+
+func unquotePO(s string) (string, error) {
+	if len(s) < 2 || s[0] != '"' || s[len(s)-1] != '"' {
+		return "", fmt.Errorf("malformed string %s", s)
+	}
+	var b strings.Builder
+	s = s[1 : len(s)-1]
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if c == '"' {
+			return "", fmt.Errorf("unescaped quote in string")
+		}
+		if c != '\\' {
+			b.WriteByte(c)
+			continue
+		}
+		if i++; i == len(s) {
+			return "", fmt.Errorf("trailing backslash in string")
+		}
+		switch s[i] {
+		case 'n':
+			b.WriteByte('\n')
+		case 't':
+			b.WriteByte('\t')
+		case 'r':
+			b.WriteByte('\r')
+		case 'a':
+			b.WriteByte('\a')
+		case 'b':
+			b.WriteByte('\b')
+		case 'f':
+			b.WriteByte('\f')
+		case 'v':
+			b.WriteByte('\v')
+		default:
+			b.WriteByte(s[i])
+		}
+	}
+	return b.String(), nil
+}
@@ -0,0 +1,142 @@
+package poeditor_test
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/blacksails/poeditor"
+)
+
+func TestReadPOPluralForms(t *testing.T) {
+	tests := []struct {
+		name     string
+		header   string
+		msgstrs  []string
+		expected poeditor.Plural
+	}{
+		{
+			name:     "english",
+			header:   "Language: en\\nPlural-Forms: nplurals=2; plural=(n != 1);\\n",
+			msgstrs:  []string{"%d file", "%d files"},
+			expected: poeditor.Plural{One: "%d file", Other: "%d files"},
+		},
+		{
+			name:     "russian",
+			header:   "Language: ru\\nPlural-Forms: nplurals=3;\\n",
+			msgstrs:  []string{"%d файл", "%d файла", "%d файлов"},
+			expected: poeditor.Plural{One: "%d файл", Few: "%d файла", Many: "%d файлов"},
+		},
+		{
+			name:     "polish",
+			header:   "Language: pl_PL\\nPlural-Forms: nplurals=3;\\n",
+			msgstrs:  []string{"%d plik", "%d pliki", "%d plików"},
+			expected: poeditor.Plural{One: "%d plik", Few: "%d pliki", Many: "%d plików"},
+		},
+		{
+			name:     "latvian",
+			header:   "Language: lv\\nPlural-Forms: nplurals=3;\\n",
+			msgstrs:  []string{"%d failu", "%d fails", "%d faili"},
+			expected: poeditor.Plural{Zero: "%d failu", One: "%d fails", Other: "%d faili"},
+		},
+		{
+			name:     "without language",
+			header:   "Plural-Forms: nplurals=2; plural=(n != 1);\\n",
+			msgstrs:  []string{"%d file", "%d files"},
+			expected: poeditor.Plural{One: "%d file", Other: "%d files"},
+		},
+		{
+			name:     "untranslated without language",
+			header:   "Plural-Forms: nplurals=3;\\n",
+			msgstrs:  []string{"", "", ""},
+			expected: poeditor.Plural{},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			terms, err := poeditor.ReadPO(strings.NewReader(poWithPlural(test.header, test.msgstrs)))
+			if err != nil {
+				t.Fatalf("Unexpected error: %s", err)
+			}
+			if len(terms) != 1 {
+				t.Fatalf("Expected 1 term, got %d", len(terms))
+			}
+			if !reflect.DeepEqual(terms[0].Translation.Content, test.expected) {
+				t.Errorf("\nExpected %+v \nGot      %+v", test.expected, terms[0].Translation.Content)
+			}
+		})
+	}
+}
+
+func TestReadPOUnmappedPluralForms(t *testing.T) {
+	tests := []struct {
+		name    string
+		header  string
+		msgstrs []string
+	}{
+		{"too many forms", "Language: ru\\nPlural-Forms: nplurals=4;\\n", []string{"a", "b", "c", "d"}},
+		{"without language", "Plural-Forms: nplurals=3;\\n", []string{"a", "b", "c"}},
+		{"unknown language", "Language: xx\\nPlural-Forms: nplurals=3;\\n", []string{"a", "b", "c"}},
+	}
+	for _, test := range tests {
+		_, err := poeditor.ReadPO(strings.NewReader(poWithPlural(test.header, test.msgstrs)))
+		if err == nil || !strings.Contains(err.Error(), "line 6") {
+			t.Errorf("%s: Expected an error on line 6, got %v", test.name, err)
+		}
+	}
+}
+
+func poWithPlural(header string, msgstrs []string) string {
+	po := "msgid \"\"\nmsgstr \"\"\n\"" + header + "\"\n\n#. A comment\nmsgid \"%d file\"\nmsgid_plural \"%d files\"\n"
+	for i, s := range msgstrs {
+		po += "msgstr[" + string(rune('0'+i)) + "] \"" + s + "\"\n"
+	}
+	return po
+}
+
+func TestWritePOLanguage(t *testing.T) {
+	terms := []poeditor.TermTranslated{
+		newTermTranslated("%d file", poeditor.Plural{One: "%d файл", Few: "%d файла", Many: "%d файлов"}, ""),
+		newTermTranslated("hello", "Привет", ""),
+	}
+	var buf bytes.Buffer
+	if err := poeditor.WritePOLanguage(&buf, "ru", terms); err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	for _, s := range []string{
+		`"Language: ru\n"`,
+		`"Plural-Forms: nplurals=3; plural=(n % 10 == 1 && n % 100 != 11 ? 0 : n % 10 >= 2 && n % 10 <= 4 && (n % 100 < 12 || n % 100 > 14) ? 1 : 2);\n"`,
+		"msgstr[0] \"%d файл\"\nmsgstr[1] \"%d файла\"\nmsgstr[2] \"%d файлов\"\n",
+	} {
+		if !strings.Contains(buf.String(), s) {
+			t.Errorf("Expected output to contain %s\n%s", s, buf.String())
+		}
+	}
+	got, err := poeditor.ReadPO(&buf)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if !reflect.DeepEqual(got, terms) {
+		t.Errorf("\nExpected %+v \nGot      %+v", terms, got)
+	}
+}
+
+func TestWritePOUnmappedPluralForms(t *testing.T) {
+	terms := []poeditor.TermTranslated{
+		newTermTranslated("%d file", poeditor.Plural{One: "%d файл", Few: "%d файла", Many: "%d файлов"}, ""),
+	}
+	if err := poeditor.WritePO(&bytes.Buffer{}, terms); err == nil {
+		t.Errorf("Expected an error writing few and many forms without a language")
+	}
+	if err := poeditor.WritePOLanguage(&bytes.Buffer{}, "de", terms); err == nil {
+		t.Errorf("Expected an error writing few and many forms in German")
+	}
+	var buf bytes.Buffer
+	if err := poeditor.WritePO(&buf, []poeditor.TermTranslated{newTermTranslated("hello", "Hello", "")}); err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if !strings.Contains(buf.String(), `"Plural-Forms: nplurals=2; plural=(n != 1);\n"`) {
+		t.Errorf("Expected English plural forms, got\n%s", buf.String())
+	}
+}
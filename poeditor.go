@@ -3,7 +3,6 @@ package poeditor
 import (
 	"bytes"
 	"encoding/json"
-	"fmt"
 	"io"
 	"log"
 	"mime/multipart"
@@ -25,11 +24,21 @@ type poster interface {
 
 type poEditorPoster struct {
 	apiToken string
+	endpoint string
 }
 
+// DefaultEndpoint is the base URL of the POEditor API
+const DefaultEndpoint = "https://api.poeditor.com/v2"
+
 // New returns a new POEditor given a POEditor API Token
 func New(apiToken string) *POEditor {
-	return &POEditor{poster: poEditorPoster{apiToken: apiToken}}
+	return NewWithEndpoint(apiToken, DefaultEndpoint)
+}
+
+// NewWithEndpoint returns a new POEditor which sends requests to the API at
+// the given base URL instead of DefaultEndpoint, such as a test server
+func NewWithEndpoint(apiToken, endpoint string) *POEditor {
+	return &POEditor{poster: poEditorPoster{apiToken: apiToken, endpoint: strings.TrimRight(endpoint, "/")}}
 }
 
 // Project returns a Project with the given id
@@ -60,7 +69,11 @@ func (p poEditorPoster) post(endpoint string, fields map[string]string, files ma
 	}
 	// Write files
 	for k, v := range files {
-		w, err := writer.CreateFormFile(k, k)
+		filename := k
+		if nr, ok := v.(namedReader); ok {
+			filename = nr.name
+		}
+		w, err := writer.CreateFormFile(k, filename)
 		if err != nil {
 			return err
 		}
@@ -74,7 +87,7 @@ func (p poEditorPoster) post(endpoint string, fields map[string]string, files ma
 		return err
 	}
 	// Send request
-	req, err := http.NewRequest("POST", fmt.Sprintf("https://api.poeditor.com/v2%s", endpoint), &body)
+	req, err := http.NewRequest("POST", p.endpoint+endpoint, &body)
 	if err != nil {
 		return err
 	}
@@ -109,6 +122,13 @@ func (p poEditorPoster) post(endpoint string, fields map[string]string, files ma
 	return nil
 }
 
+// namedReader is a file with a file name. POEditor uses the extension of an
+// uploaded file to determine its format.
+type namedReader struct {
+	io.Reader
+	name string
+}
+
 type poEditorResponse struct {
 	Response response    `json:"response"`
 	Result   interface{} `json:"result"`
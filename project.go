@@ -19,6 +19,8 @@ type Project struct {
 	ReferenceLanguage string
 	Terms             int
 	Created           time.Time
+
+	validators *validators
 }
 
 // ListProjects lists all the projects that are accessable by the used APIKey
@@ -85,7 +87,9 @@ func (p *Project) Update(props map[string]string) (*Project, error) {
 	if err != nil {
 		return nil, err
 	}
-	return res.Project.toProject(p.POEditor), nil
+	updated := res.Project.toProject(p.POEditor)
+	updated.validators = p.validators.copy()
+	return updated, nil
 }
 
 // Delete does its thing
@@ -97,7 +101,7 @@ func (p *Project) Delete() error {
 func (p *Project) Upload(reader io.Reader, options UploadOptions) (UploadResult, error) {
 	var (
 		res    UploadResult
-		fields map[string]string
+		fields = make(map[string]string)
 	)
 	var validUpdating = func() bool {
 		u := options.Updating
@@ -141,6 +145,9 @@ func (p *Project) Upload(reader io.Reader, options UploadOptions) (UploadResult,
 // CAUTION: this is a destructive operation. Any term not found in the input
 // array will be deleted from the project.
 func (p *Project) Sync(terms []Term) (CountResult, error) {
+	if err := p.validateTerms(OperationSync, terms); err != nil {
+		return CountResult{}, err
+	}
 	jsonTerms, err := json.Marshal(terms)
 	if err != nil {
 		return CountResult{}, err
@@ -274,6 +281,8 @@ const (
 	FileFormatXMB = "xmb"
 	// FileFormatXTB specifies an .xtb file
 	FileFormatXTB = "xtb"
+	// FileFormatARB specifies a Flutter .arb file
+	FileFormatARB = "arb"
 )
 
 const (
@@ -0,0 +1,233 @@
+package poeditor
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"strconv"
+	"strings"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+// ReadProperties decodes a Java .properties file. Comment lines directly
+// above an entry become the term comment. Files which are not valid UTF-8 are
+// read as ISO-8859-1, like java.util.Properties does.
+func ReadProperties(r io.Reader) ([]TermTranslated, error) {
+	b, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	if !utf8.Valid(b) {
+		b = latin1ToUTF8(b)
+	}
+	var (
+		terms    []TermTranslated
+		comments []string
+		logical  strings.Builder
+		cont     bool
+		lineNo   int
+	)
+	add := func() error {
+		key, value, err := splitProperty(logical.String())
+		if err != nil {
+			return fmt.Errorf("properties: line %d: %s", lineNo, err)
+		}
+		logical.Reset()
+		t := flatTerm(key, value)
+		t.Comment = strings.Join(comments, "\n")
+		comments = nil
+		terms = append(terms, t)
+		return nil
+	}
+	s := bufio.NewScanner(bytes.NewReader(b))
+	s.Buffer(nil, len(b)+1)
+	for s.Scan() {
+		lineNo++
+		line := strings.TrimLeft(s.Text(), " \t\f")
+		if !cont {
+			if line == "" {
+				comments = nil
+				continue
+			}
+			if line[0] == '#' || line[0] == '!' {
+				c, err := unescapeProperty(strings.TrimSpace(line[1:]))
+				if err != nil {
+					c = strings.TrimSpace(line[1:])
+				}
+				comments = append(comments, c)
+				continue
+			}
+		}
+		// An odd number of trailing backslashes continues the line
+		trailing := len(line) - len(strings.TrimRight(line, "\\"))
+		cont = trailing%2 == 1
+		if cont {
+			line = line[:len(line)-1]
+		}
+		logical.WriteString(line)
+		if cont {
+			continue
+		}
+		if err := add(); err != nil {
+			return nil, err
+		}
+	}
+	if err := s.Err(); err != nil {
+		return nil, err
+	}
+	// The last line may end in a continuation
+	if cont {
+		if err := add(); err != nil {
+			return nil, err
+		}
+	}
+	return terms, nil
+}
+
+// WriteProperties encodes terms as a Java .properties file. Non ASCII
+// characters are written as unicode escapes so the output is readable as both
+// ISO-8859-1 and UTF-8. Plurals are written as ICU plural messages.
+func WriteProperties(w io.Writer, terms []TermTranslated) error {
+	bw := bufio.NewWriter(w)
+	for i, t := range terms {
+		value, err := flatContent(t.Translation.Content)
+		if err != nil {
+			return err
+		}
+		if t.Comment != "" {
+			if i > 0 {
+				bw.WriteString("\n")
+			}
+			for _, c := range strings.Split(t.Comment, "\n") {
+				bw.WriteString("# " + escapeProperty(c, false, false) + "\n")
+			}
+		}
+		bw.WriteString(escapeProperty(t.Term.Term, true, false))
+		bw.WriteString("=")
+		bw.WriteString(escapeProperty(value, false, true))
+		bw.WriteString("\n")
+	}
+	return bw.Flush()
+}
+
+// splitProperty splits a logical line into an unescaped key and value
+func splitProperty(line string) (string, string, error) {
+	end := len(line)
+	for i := 0; i < len(line); i++ {
+		c := line[i]
+		if c == '\\' {
+			i++
+			continue
+		}
+		if c == '=' || c == ':' || c == ' ' || c == '\t' || c == '\f' {
+			end = i
+			break
+		}
+	}
+	key, err := unescapeProperty(line[:end])
+	if err != nil {
+		return "", "", err
+	}
+	rest := strings.TrimLeft(line[end:], " \t\f")
+	if rest != "" && (rest[0] == '=' || rest[0] == ':') {
+		rest = strings.TrimLeft(rest[1:], " \t\f")
+	}
+	value, err := unescapeProperty(rest)
+	return key, value, err
+}
+
+func unescapeProperty(s string) (string, error) {
+	if !strings.Contains(s, "\\") {
+		return s, nil
+	}
+	var (
+		b     strings.Builder
+		units []uint16
+	)
+	flush := func() {
+		if len(units) > 0 {
+			b.WriteString(string(utf16.Decode(units)))
+			units = nil
+		}
+	}
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if c != '\\' || i == len(s)-1 {
+			flush()
+			b.WriteByte(c)
+			continue
+		}
+		i++
+		switch s[i] {
+		case 't':
+			flush()
+			b.WriteByte('\t')
+		case 'n':
+			flush()
+			b.WriteByte('\n')
+		case 'r':
+			flush()
+			b.WriteByte('\r')
+		case 'f':
+			flush()
+			b.WriteByte('\f')
+		case 'u':
+			if i+4 >= len(s) {
+				return "", fmt.Errorf("malformed \\u escape %q", s[i-1:])
+			}
+			u, err := strconv.ParseUint(s[i+1:i+5], 16, 16)
+			if err != nil {
+				return "", fmt.Errorf("malformed \\u escape %q", s[i-1:i+5])
+			}
+			// Surrogate pairs are collected and decoded together
+			units = append(units, uint16(u))
+			i += 4
+		default:
+			flush()
+			b.WriteByte(s[i])
+		}
+	}
+	flush()
+	return b.String(), nil
+}
+
+func escapeProperty(s string, key, value bool) string {
+	var b strings.Builder
+	for i, r := range s {
+		switch {
+		case r == '\\':
+			b.WriteString(`\\`)
+		case r == '\t':
+			b.WriteString(`\t`)
+		case r == '\n':
+			b.WriteString(`\n`)
+		case r == '\r':
+			b.WriteString(`\r`)
+		case r == '\f':
+			b.WriteString(`\f`)
+		case key && (r == '=' || r == ':' || r == ' ' || r == '#' || r == '!'):
+			b.WriteByte('\\')
+			b.WriteRune(r)
+		case value && i == 0 && r == ' ':
+			b.WriteString(`\ `)
+		case r < 0x20 || r > 0x7e:
+			for _, u := range utf16.Encode([]rune{r}) {
+				fmt.Fprintf(&b, `\u%04X`, u)
+			}
+		default:
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+func latin1ToUTF8(b []byte) []byte {
+	rs := make([]rune, len(b))
+	for i, c := range b {
+		rs[i] = rune(c)
+	}
+	return []byte(string(rs))
+}
@@ -0,0 +1,130 @@
+package poeditor_test
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/blacksails/poeditor"
+)
+
+func TestReadProperties(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected []poeditor.TermTranslated
+	}{
+		{
+			name:  "separators",
+			input: "a=1\nb: 2\nc 3\nd\n",
+			expected: []poeditor.TermTranslated{
+				newTermTranslated("a", "1", ""),
+				newTermTranslated("b", "2", ""),
+				newTermTranslated("c", "3", ""),
+				newTermTranslated("d", "", ""),
+			},
+		},
+		{
+			name:  "comments",
+			input: "# Greeting\n! on the start page\nhello=Hi\n\n# Detached\n\nbye=Bye\n",
+			expected: []poeditor.TermTranslated{
+				newTermTranslated("hello", "Hi", "Greeting\non the start page"),
+				newTermTranslated("bye", "Bye", ""),
+			},
+		},
+		{
+			name:  "continuation",
+			input: "long = first \\\n    second \\\n\tthird\npath = C:\\\\\nnext = value\n",
+			expected: []poeditor.TermTranslated{
+				newTermTranslated("long", "first second third", ""),
+				newTermTranslated("path", `C:\`, ""),
+				newTermTranslated("next", "value", ""),
+			},
+		},
+		{
+			name:  "continuation at end of file",
+			input: "# Last entry\nkey = a \\",
+			expected: []poeditor.TermTranslated{
+				newTermTranslated("key", "a ", "Last entry"),
+			},
+		},
+		{
+			name:  "escapes",
+			input: "key\\ with\\=separators = tab\\there\\nnewline\n",
+			expected: []poeditor.TermTranslated{
+				newTermTranslated("key with=separators", "tab\there\nnewline", ""),
+			},
+		},
+		{
+			name:  "unicode escapes",
+			input: "accent=caf\\u00e9\nemoji=\\uD83D\\uDE00!\n",
+			expected: []poeditor.TermTranslated{
+				newTermTranslated("accent", "café", ""),
+				newTermTranslated("emoji", "😀!", ""),
+			},
+		},
+		{
+			name:  "latin1",
+			input: "# R\xe9sum\xe9\nname=caf\xe9\n",
+			expected: []poeditor.TermTranslated{
+				newTermTranslated("name", "café", "Résumé"),
+			},
+		},
+		{
+			name:  "utf8",
+			input: "name=café\n",
+			expected: []poeditor.TermTranslated{
+				newTermTranslated("name", "café", ""),
+			},
+		},
+		{
+			name:  "plural",
+			input: "items={count, plural, one{# item} other{# items}}\n",
+			expected: []poeditor.TermTranslated{
+				newTermTranslated("items", poeditor.Plural{One: "# item", Other: "# items"}, ""),
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := poeditor.ReadProperties(strings.NewReader(test.input))
+			if err != nil {
+				t.Fatalf("Unexpected error: %s", err)
+			}
+			if !reflect.DeepEqual(got, test.expected) {
+				t.Errorf("\nExpected %+v \nGot      %+v", test.expected, got)
+			}
+		})
+	}
+}
+
+func TestReadPropertiesMalformedEscape(t *testing.T) {
+	if _, err := poeditor.ReadProperties(strings.NewReader("a=1\nb=\\u12\n")); err == nil || !strings.Contains(err.Error(), "line 2") {
+		t.Errorf("Expected an error on line 2, got %v", err)
+	}
+}
+
+func TestWriteProperties(t *testing.T) {
+	terms := []poeditor.TermTranslated{
+		newTermTranslated("key with=separators", " leading space, é and 😀", "A comment\non two lines"),
+		newTermTranslated("items", poeditor.Plural{One: "# item", Other: "# items"}, ""),
+	}
+	var buf bytes.Buffer
+	if err := poeditor.WriteProperties(&buf, terms); err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	expected := "# A comment\n# on two lines\n" +
+		"key\\ with\\=separators=\\ leading space, \\u00E9 and \\uD83D\\uDE00\n" +
+		"items={count, plural, one{# item} other{# items}}\n"
+	if buf.String() != expected {
+		t.Errorf("\nExpected %q \nGot      %q", expected, buf.String())
+	}
+	got, err := poeditor.ReadProperties(&buf)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if !reflect.DeepEqual(got, terms) {
+		t.Errorf("\nExpected %+v \nGot      %+v", terms, got)
+	}
+}
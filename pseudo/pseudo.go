@@ -0,0 +1,269 @@
+// Package pseudo generates pseudo translations for UI testing. Letters are
+// replaced with accented look-alikes, translations are padded to simulate
+// languages longer than the source and wrapped in brackets, so truncated and
+// hardcoded strings stand out:
+//
+//	pseudo.Localizer{}.String("Hello %s, <b>welcome</b>!")
+//	// [Ĥéļļö %s, <b>ŵéļçöɱé</b>! one two]
+//
+// Placeholders such as %s, %1$s, {name} and {{.Count}}, HTML and XML tags
+// and character entities are kept unchanged. ICU messages such as
+// {count, plural, one {# file} other {# files}} keep their syntax, and only
+// the text of their sub-messages is pseudo translated.
+//
+// The pseudo translations of the source language of a project can be
+// exported to any local file format with Localizer.Export, or pushed to a
+// dedicated language such as en-XA with Localizer.Push.
+package pseudo
+
+import (
+	"io"
+	"math"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/blacksails/poeditor"
+	"github.com/blacksails/poeditor/icu"
+	"github.com/blacksails/poeditor/internal/placeholder"
+)
+
+// DefaultExpansion is the length added to translations, as a fraction of the
+// length of their text
+const DefaultExpansion = 0.4
+
+// padding are the words expanding translations
+var padding = strings.Fields("one two three four five six seven eight nine ten")
+
+var accents = map[rune]rune{
+	'A': 'Å', 'B': 'Ɓ', 'C': 'Ç', 'D': 'Đ', 'E': 'É', 'F': 'Ƒ', 'G': 'Ĝ',
+	'H': 'Ĥ', 'I': 'Î', 'J': 'Ĵ', 'K': 'Ķ', 'L': 'Ļ', 'M': 'Ṁ', 'N': 'Ñ',
+	'O': 'Ö', 'P': 'Þ', 'Q': 'Ǫ', 'R': 'Ŕ', 'S': 'Š', 'T': 'Ţ', 'U': 'Û',
+	'V': 'Ṽ', 'W': 'Ŵ', 'X': 'Ẋ', 'Y': 'Ý', 'Z': 'Ž',
+	'a': 'å', 'b': 'ƀ', 'c': 'ç', 'd': 'đ', 'e': 'é', 'f': 'ƒ', 'g': 'ĝ',
+	'h': 'ĥ', 'i': 'î', 'j': 'ĵ', 'k': 'ķ', 'l': 'ļ', 'm': 'ɱ', 'n': 'ñ',
+	'o': 'ö', 'p': 'þ', 'q': 'ǫ', 'r': 'ŕ', 's': 'š', 't': 'ţ', 'u': 'û',
+	'v': 'ṽ', 'w': 'ŵ', 'x': 'ẋ', 'y': 'ý', 'z': 'ž',
+}
+
+// Localizer generates pseudo translations. The zero value accents, expands
+// and brackets translations.
+type Localizer struct {
+	// Expansion is the length added to translations as a fraction of the
+	// length of their text. Zero means DefaultExpansion and a negative
+	// value disables expansion.
+	Expansion float64
+	// NoAccents keeps letters unchanged
+	NoAccents bool
+	// NoBrackets leaves out the brackets around translations
+	NoBrackets bool
+}
+
+// String returns the pseudo translation of s. Empty strings stay empty.
+func (l Localizer) String(s string) string {
+	if s == "" {
+		return ""
+	}
+	var (
+		b       strings.Builder
+		letters int
+	)
+	if !l.NoBrackets {
+		b.WriteString("[")
+	}
+	if msg, err := icu.Parse(s); err == nil && hasICUSyntax(msg) {
+		msg, letters = l.message(msg)
+		b.WriteString(msg.String())
+	} else {
+		letters = l.text(&b, s)
+	}
+	if pad := l.padding(letters); pad != "" {
+		b.WriteString(" " + pad)
+	}
+	if !l.NoBrackets {
+		b.WriteString("]")
+	}
+	return b.String()
+}
+
+// hasICUSyntax reports whether a message has arguments with a type, such as
+// {n, number} or {count, plural, ...}. Other messages are pseudo translated
+// as text, which keeps their quoting and {name} placeholders as they are.
+func hasICUSyntax(msg icu.Message) bool {
+	for _, n := range msg {
+		if a, ok := n.(*icu.Argument); ok && a.Type != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// message returns the pseudo translation of an ICU message and its number
+// of characters, counting the longest option of each argument
+func (l Localizer) message(msg icu.Message) (icu.Message, int) {
+	var (
+		pseudo  = make(icu.Message, len(msg))
+		letters int
+	)
+	for i, n := range msg {
+		switch n := n.(type) {
+		case icu.Text:
+			var b strings.Builder
+			letters += l.text(&b, string(n))
+			pseudo[i] = icu.Text(b.String())
+		case *icu.Argument:
+			a := *n
+			a.Options = make([]icu.Option, len(n.Options))
+			longest := 0
+			for j, o := range n.Options {
+				m, count := l.message(o.Message)
+				a.Options[j] = icu.Option{Selector: o.Selector, Message: m}
+				if count > longest {
+					longest = count
+				}
+			}
+			if n.Options == nil {
+				a.Options = nil
+			}
+			letters += longest
+			pseudo[i] = &a
+		default:
+			pseudo[i] = n
+		}
+	}
+	return pseudo, letters
+}
+
+// text writes the accented text, keeping its placeholders and markup, and
+// returns its number of characters
+func (l Localizer) text(b *strings.Builder, s string) int {
+	var (
+		letters int
+		pos     int
+	)
+	for _, r := range placeholder.Protected(s) {
+		letters += l.accent(b, s[pos:r[0]])
+		b.WriteString(s[r[0]:r[1]])
+		pos = r[1]
+	}
+	return letters + l.accent(b, s[pos:])
+}
+
+// accent writes the accented text and returns its number of characters
+func (l Localizer) accent(b *strings.Builder, s string) int {
+	for _, r := range s {
+		if a, ok := accents[r]; ok && !l.NoAccents {
+			r = a
+		}
+		b.WriteRune(r)
+	}
+	return utf8.RuneCountInString(s)
+}
+
+// padding returns the words expanding a text of n characters, with at least
+// the length of the expansion
+func (l Localizer) padding(n int) string {
+	expansion := l.Expansion
+	if expansion == 0 {
+		expansion = DefaultExpansion
+	}
+	if expansion < 0 || n == 0 {
+		return ""
+	}
+	size := int(math.Ceil(float64(n) * expansion))
+	var pad strings.Builder
+	for i := 0; pad.Len() < size; i++ {
+		if i > 0 {
+			pad.WriteString(" ")
+		}
+		pad.WriteString(padding[i%len(padding)])
+	}
+	return pad.String()
+}
+
+// Content returns the pseudo translation of a string or Plural translation
+// content. Other values are returned unchanged.
+func (l Localizer) Content(c interface{}) interface{} {
+	switch c := c.(type) {
+	case string:
+		return l.String(c)
+	case poeditor.Plural:
+		return poeditor.Plural{
+			Zero:  l.String(c.Zero),
+			One:   l.String(c.One),
+			Two:   l.String(c.Two),
+			Few:   l.String(c.Few),
+			Many:  l.String(c.Many),
+			Other: l.String(c.Other),
+		}
+	}
+	return c
+}
+
+// Terms returns the pseudo translations of the source terms. Untranslated
+// terms are pseudo translated from the term itself, and its plural for terms
+// with a plural, as for projects using the source text as terms.
+func (l Localizer) Terms(source []poeditor.TermTranslated) []poeditor.TermTranslated {
+	terms := make([]poeditor.TermTranslated, len(source))
+	for i, t := range source {
+		c := t.Translation.Content
+		if poeditor.EmptyContent(c) {
+			c = t.Term.Term
+			if t.Plural != "" {
+				c = poeditor.Plural{One: t.Term.Term, Other: t.Plural}
+			}
+		}
+		terms[i] = poeditor.TermTranslated{
+			Term:        t.Term,
+			Translation: poeditor.Translation{Content: l.Content(c)},
+		}
+	}
+	return terms
+}
+
+// Export lists the terms of the source language of the project and writes
+// their pseudo translations to w in the file format, see poeditor.Formats
+func (l Localizer) Export(p *poeditor.Project, source string, w io.Writer, format string) error {
+	src := poeditor.Language{Project: p, Code: source}
+	terms, err := src.ListTerms()
+	if err != nil {
+		return err
+	}
+	return poeditor.Encode(w, format, l.Terms(terms))
+}
+
+// Push lists the terms of the source language of the project and writes
+// their pseudo translations to the target language, which is added to the
+// project if needed. Languages with a private use region such as en-XA are
+// meant for pseudo translations.
+func (l Localizer) Push(p *poeditor.Project, source, target string) (poeditor.CountResult, error) {
+	src := poeditor.Language{Project: p, Code: source}
+	terms, err := src.ListTerms()
+	if err != nil {
+		return poeditor.CountResult{}, err
+	}
+	languages, err := p.ListLanguages()
+	if err != nil {
+		return poeditor.CountResult{}, err
+	}
+	found := false
+	for _, lang := range languages {
+		if lang.Code == target {
+			found = true
+			break
+		}
+	}
+	if !found {
+		if err := p.AddLanguage(target); err != nil {
+			return poeditor.CountResult{}, err
+		}
+	}
+	translations := make([]poeditor.TermTranslation, 0, len(terms))
+	for _, t := range l.Terms(terms) {
+		translations = append(translations, poeditor.TermTranslation{
+			TermBase:    t.TermBase,
+			Translation: t.Translation,
+		})
+	}
+	dst := poeditor.Language{Project: p, Code: target}
+	return dst.Update(translations)
+}
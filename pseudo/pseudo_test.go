@@ -0,0 +1,86 @@
+package pseudo_test
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+
+	"github.com/blacksails/poeditor"
+	"github.com/blacksails/poeditor/pseudo"
+)
+
+func TestString(t *testing.T) {
+	stringTests := []struct {
+		localizer pseudo.Localizer
+		input     string
+		expected  string
+	}{
+		{pseudo.Localizer{}, "", ""},
+		{pseudo.Localizer{}, "Hello %s, <b>welcome</b>!", "[Ĥéļļö %s, <b>ŵéļçöɱé</b>! one two]"},
+		{pseudo.Localizer{}, "{count} files", "[{count} ƒîļéš one]"},
+		{pseudo.Localizer{}, "Tom &amp; {{.Name}}", "[Ţöɱ &amp; {{.Name}} one]"},
+		{pseudo.Localizer{}, "%[2]d of %d", "[%[2]d öƒ %d one]"},
+		{pseudo.Localizer{Expansion: 1}, "Save", "[Šåṽé one two]"},
+		{pseudo.Localizer{Expansion: -1, NoBrackets: true}, "Save", "Šåṽé"},
+		{pseudo.Localizer{Expansion: -1, NoAccents: true}, "Save <br/>", "[Save <br/>]"},
+		{pseudo.Localizer{}, "%1$s of %2$d", "[%1$s öƒ %2$d one]"},
+		{pseudo.Localizer{}, "Hello %@", "[Ĥéļļö %@ one]"},
+		{pseudo.Localizer{Expansion: -1}, "{count, plural, one {# file} other {# files}}",
+			"[{count, plural, one {# ƒîļé} other {# ƒîļéš}}]"},
+		{pseudo.Localizer{}, "{gender, select, female {She} other {They}} said {n, number, integer}'s",
+			"[{gender, select, female {Šĥé} other {Ţĥéý}} šåîđ {n, number, integer}''š one two]"},
+		{pseudo.Localizer{Expansion: 3}, "Save now", "[Šåṽé ñöŵ one two three four five six]"},
+	}
+	for _, st := range stringTests {
+		if s := st.localizer.String(st.input); s != st.expected {
+			t.Errorf("%q: Expected %q, got %q", st.input, st.expected, s)
+		}
+	}
+}
+
+func TestTerms(t *testing.T) {
+	loc := pseudo.Localizer{Expansion: -1}
+	source := []poeditor.TermTranslated{
+		{
+			Term:        poeditor.Term{TermBase: poeditor.TermBase{Term: "greeting"}},
+			Translation: poeditor.Translation{Content: "Hi", Fuzzy: 1},
+		},
+		{
+			Term: poeditor.Term{TermBase: poeditor.TermBase{Term: "One file", Context: "x"}, Plural: "%d files"},
+		},
+		{
+			Term:        poeditor.Term{TermBase: poeditor.TermBase{Term: "items"}},
+			Translation: poeditor.Translation{Content: poeditor.Plural{One: "item", Other: "items"}},
+		},
+	}
+	expected := []poeditor.TermTranslated{
+		{
+			Term:        poeditor.Term{TermBase: poeditor.TermBase{Term: "greeting"}},
+			Translation: poeditor.Translation{Content: "[Ĥî]"},
+		},
+		{
+			Term:        poeditor.Term{TermBase: poeditor.TermBase{Term: "One file", Context: "x"}, Plural: "%d files"},
+			Translation: poeditor.Translation{Content: poeditor.Plural{One: "[Öñé ƒîļé]", Other: "[%d ƒîļéš]"}},
+		},
+		{
+			Term:        poeditor.Term{TermBase: poeditor.TermBase{Term: "items"}},
+			Translation: poeditor.Translation{Content: poeditor.Plural{One: "[îţéɱ]", Other: "[îţéɱš]"}},
+		},
+	}
+	terms := loc.Terms(source)
+	if !reflect.DeepEqual(terms, expected) {
+		t.Errorf("Expected %+v, got %+v", expected, terms)
+	}
+
+	var buf bytes.Buffer
+	if err := poeditor.Encode(&buf, poeditor.FileFormatKeyValueJSON, terms[:1]); err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	decoded, err := poeditor.Decode(&buf, poeditor.FileFormatKeyValueJSON)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if len(decoded) != 1 || decoded[0].Translation.Content != "[Ĥî]" {
+		t.Errorf("Expected the pseudo translation to round trip, got %+v", decoded)
+	}
+}
@@ -0,0 +1,263 @@
+package qa
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/blacksails/poeditor/internal/cldr"
+	"github.com/blacksails/poeditor/internal/placeholder"
+)
+
+// Placeholders reports printf verbs, {name} placeholders and {{template}}
+// actions missing in or added to the translation. Printf verbs may be
+// reordered with explicit argument indexes. Plural forms other than other may
+// leave out placeholders, since e.g. "one file" need not print the count.
+var Placeholders = Check{
+	Name:     "placeholders",
+	Severity: Error,
+	Run: func(p Pair) []string {
+		src, tr := placeholderCounts(p.Source), placeholderCounts(p.Translation)
+		var msgs []string
+		for _, k := range sortedKeys(src) {
+			if tr[k] < src[k] && (p.Form == "" || p.Form == cldr.Other) {
+				msgs = append(msgs, "missing placeholder "+k)
+			}
+		}
+		for _, k := range sortedKeys(tr) {
+			if tr[k] > src[k] {
+				msgs = append(msgs, "unexpected placeholder "+k)
+			}
+		}
+		return msgs
+	},
+}
+
+// placeholderCounts counts the placeholders of s by a key which ignores
+// formatting that may differ between languages
+func placeholderCounts(s string) map[string]int {
+	counts := make(map[string]int)
+	for _, p := range placeholder.Parse(s) {
+		key := p.Text
+		switch p.Kind {
+		case placeholder.Printf:
+			// Arguments are identified by index and verb, flags and widths
+			// may change
+			key = "%[" + strconv.Itoa(p.Index) + "]" + string(p.Verb)
+		case placeholder.Template:
+			key = "{{" + p.Name + "}}"
+		}
+		counts[key]++
+	}
+	return counts
+}
+
+func sortedKeys(m map[string]int) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+var tagPattern = regexp.MustCompile(`</?([a-zA-Z][a-zA-Z0-9-]*)\b[^<>]*?(/?)>`)
+
+// voidElements are HTML elements without closing tags
+var voidElements = map[string]bool{
+	"area": true, "base": true, "br": true, "col": true, "embed": true, "hr": true,
+	"img": true, "input": true, "link": true, "meta": true, "source": true, "track": true, "wbr": true,
+}
+
+// Markup reports unbalanced HTML or XML tags in the translation, and tags
+// missing in or added to it
+var Markup = Check{
+	Name:     "markup",
+	Severity: Error,
+	Run: func(p Pair) []string {
+		var msgs []string
+		if msg := balance(p.Translation); msg != "" {
+			msgs = append(msgs, msg)
+		}
+		src, tr := tagCounts(p.Source), tagCounts(p.Translation)
+		for _, k := range sortedKeys(src) {
+			if tr[k] < src[k] {
+				msgs = append(msgs, "missing tag "+k)
+			}
+		}
+		for _, k := range sortedKeys(tr) {
+			if tr[k] > src[k] {
+				msgs = append(msgs, "unexpected tag "+k)
+			}
+		}
+		return msgs
+	},
+}
+
+// tagCounts counts the opening, closing and self-closing tags of s
+func tagCounts(s string) map[string]int {
+	counts := make(map[string]int)
+	for _, m := range tagPattern.FindAllStringSubmatch(s, -1) {
+		name := strings.ToLower(m[1])
+		switch {
+		case strings.HasPrefix(m[0], "</"):
+			counts["</"+name+">"]++
+		case m[2] == "/" || voidElements[name]:
+			counts["<"+name+"/>"]++
+		default:
+			counts["<"+name+">"]++
+		}
+	}
+	return counts
+}
+
+// balance returns a message if the tags of s are not properly nested
+func balance(s string) string {
+	var open []string
+	for _, m := range tagPattern.FindAllStringSubmatch(s, -1) {
+		name := strings.ToLower(m[1])
+		switch {
+		case strings.HasPrefix(m[0], "</"):
+			if len(open) == 0 || open[len(open)-1] != name {
+				return fmt.Sprintf("unbalanced closing tag </%s>", name)
+			}
+			open = open[:len(open)-1]
+		case m[2] == "/" || voidElements[name]:
+		default:
+			open = append(open, name)
+		}
+	}
+	if len(open) > 0 {
+		return fmt.Sprintf("unclosed tag <%s>", open[len(open)-1])
+	}
+	return ""
+}
+
+// Whitespace reports leading or trailing whitespace differing from the
+// source
+var Whitespace = Check{
+	Name:     "whitespace",
+	Severity: Warning,
+	Run: func(p Pair) []string {
+		sl, _, st := trim(p.Source)
+		tl, _, tt := trim(p.Translation)
+		var msgs []string
+		if sl != tl {
+			msgs = append(msgs, fmt.Sprintf("leading whitespace %q, source has %q", tl, sl))
+		}
+		if st != tt {
+			msgs = append(msgs, fmt.Sprintf("trailing whitespace %q, source has %q", tt, st))
+		}
+		return msgs
+	},
+}
+
+// fullWidth maps full width punctuation to its ASCII equivalent
+var fullWidth = map[rune]rune{
+	'。': '.', '．': '.', '！': '!', '？': '?', '：': ':', '；': ';', '，': ',', '…': '.',
+}
+
+// Punctuation reports a translation ending in other punctuation than the
+// source, treating full width punctuation and ellipses like their ASCII
+// equivalents
+var Punctuation = Check{
+	Name:     "punctuation",
+	Severity: Warning,
+	Run: func(p Pair) []string {
+		_, src, _ := trim(p.Source)
+		_, tr, _ := trim(p.Translation)
+		s, t := endPunctuation(src), endPunctuation(tr)
+		if s == t {
+			return nil
+		}
+		switch {
+		case s == 0:
+			return []string{fmt.Sprintf("ends with %q, source does not", lastRune(tr))}
+		case t == 0:
+			return []string{fmt.Sprintf("does not end with %q like the source", lastRune(src))}
+		}
+		return []string{fmt.Sprintf("ends with %q, source ends with %q", lastRune(tr), lastRune(src))}
+	},
+}
+
+// endPunctuation returns the normalized punctuation s ends with, or 0
+func endPunctuation(s string) rune {
+	r := lastRune(s)
+	if n, ok := fullWidth[r]; ok {
+		return n
+	}
+	if strings.ContainsRune(".!?:;,", r) {
+		return r
+	}
+	return 0
+}
+
+func lastRune(s string) rune {
+	r, _ := utf8.DecodeLastRuneInString(s)
+	return r
+}
+
+// Newlines reports translations with another number of line breaks than the
+// source
+var Newlines = Check{
+	Name:     "newlines",
+	Severity: Warning,
+	Run: func(p Pair) []string {
+		s, t := strings.Count(p.Source, "\n"), strings.Count(p.Translation, "\n")
+		if s == t {
+			return nil
+		}
+		return []string{fmt.Sprintf("%d line breaks, source has %d", t, s)}
+	},
+}
+
+// minRatioLength is the length of the shortest source checked by
+// LengthRatio, as the length of short texts varies a lot between languages
+const minRatioLength = 10
+
+// LengthRatio returns a check reporting translations shorter than min or
+// longer than max times the length of the source, counted in characters.
+// Sources shorter than 10 characters are not checked.
+func LengthRatio(min, max float64) Check {
+	return Check{
+		Name:     "length",
+		Severity: Info,
+		Run: func(p Pair) []string {
+			s, t := utf8.RuneCountInString(p.Source), utf8.RuneCountInString(p.Translation)
+			if s < minRatioLength {
+				return nil
+			}
+			ratio := float64(t) / float64(s)
+			if ratio < min || ratio > max {
+				return []string{fmt.Sprintf("%d characters, %.1f times the source length", t, ratio)}
+			}
+			return nil
+		},
+	}
+}
+
+// Identical reports translations equal to their source, unless the source
+// has no letters
+var Identical = Check{
+	Name:     "identical",
+	Severity: Info,
+	Run: func(p Pair) []string {
+		if p.Translation != p.Source || strings.IndexFunc(stripPlaceholders(p.Source), unicode.IsLetter) < 0 {
+			return nil
+		}
+		return []string{"same as the source"}
+	},
+}
+
+// stripPlaceholders removes the placeholders of s
+func stripPlaceholders(s string) string {
+	ps := placeholder.Parse(s)
+	for i := len(ps) - 1; i >= 0; i-- {
+		s = s[:ps[i].Start] + s[ps[i].End:]
+	}
+	return s
+}
@@ -0,0 +1,206 @@
+// Package qa checks translations against the reference language for the
+// mistakes translators commonly make: broken placeholders and markup,
+// changed whitespace, punctuation and line breaks, suspicious lengths and
+// untranslated copies of the source.
+//
+//	findings, err := qa.RunLanguage(poeditor.Language{Project: p, Code: "de"}, "en")
+//	for _, f := range findings {
+//		fmt.Println(f)
+//	}
+package qa
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/blacksails/poeditor"
+)
+
+// Severities of findings, from the most to the least severe
+const (
+	// Error means the translation is broken, e.g. a placeholder is missing
+	Error = "error"
+	// Warning means the translation is likely wrong
+	Warning = "warning"
+	// Info means the translation may be worth a look
+	Info = "info"
+)
+
+var severityRank = map[string]int{Error: 3, Warning: 2, Info: 1}
+
+// AtLeast reports whether severity is at least as severe as min
+func AtLeast(severity, min string) bool {
+	return severityRank[severity] >= severityRank[min]
+}
+
+// Pair is a translation and the source text it translates. Plural forms are
+// checked one by one against the other form of the source, with Form set to
+// their CLDR category.
+type Pair struct {
+	Source      string
+	Translation string
+	// Form is the plural category of the translation, or empty for
+	// translations without plural forms
+	Form string
+}
+
+// Check is a single QA check
+type Check struct {
+	Name     string
+	Severity string
+	// Run returns a message for each problem of the pair
+	Run func(p Pair) []string
+}
+
+// Finding is a problem found by a check
+type Finding struct {
+	poeditor.TermBase
+	Check    string `json:"check"`
+	Severity string `json:"severity"`
+	// Form is the plural category of the translation, if it is a plural
+	Form    string `json:"form,omitempty"`
+	Message string `json:"message"`
+}
+
+func (f Finding) String() string {
+	name := f.Term
+	if f.Context != "" {
+		name += " (" + f.Context + ")"
+	}
+	if f.Form != "" {
+		name += " [" + f.Form + "]"
+	}
+	return fmt.Sprintf("%s: %s: %s: %s", f.Severity, name, f.Check, f.Message)
+}
+
+// DefaultChecks are run when no checks are given
+var DefaultChecks = []Check{
+	Placeholders,
+	Markup,
+	Whitespace,
+	Punctuation,
+	Newlines,
+	LengthRatio(0.3, 3),
+	Identical,
+}
+
+// Run checks the translations against the source terms, which are matched by
+// TermBase. Untranslated terms and terms missing in the source are skipped.
+// DefaultChecks are run when no checks are given.
+func Run(source, translations []poeditor.TermTranslated, checks ...Check) []Finding {
+	if len(checks) == 0 {
+		checks = DefaultChecks
+	}
+	sources := make(map[poeditor.TermBase]interface{}, len(source))
+	for _, t := range source {
+		sources[t.TermBase] = t.Translation.Content
+	}
+	var findings []Finding
+	for _, t := range translations {
+		src, ok := sources[t.TermBase]
+		if !ok {
+			continue
+		}
+		for _, p := range pairs(src, t.Translation.Content) {
+			for _, c := range checks {
+				for _, msg := range c.Run(p) {
+					findings = append(findings, Finding{
+						TermBase: t.TermBase,
+						Check:    c.Name,
+						Severity: c.Severity,
+						Form:     p.Form,
+						Message:  msg,
+					})
+				}
+			}
+		}
+	}
+	return findings
+}
+
+// pairs returns the translated forms of a translation along with their
+// source. All forms of plurals are compared with the other form of the
+// source, since categories of the same name need not cover the same numbers
+// in both languages: the English one is only 1, the Russian one is also 21.
+func pairs(source, translation interface{}) []Pair {
+	var src string
+	switch s := source.(type) {
+	case string:
+		src = s
+	case poeditor.Plural:
+		src = s.Other
+	}
+	if src == "" {
+		return nil
+	}
+	switch t := translation.(type) {
+	case string:
+		if t == "" {
+			return nil
+		}
+		return []Pair{{Source: src, Translation: t}}
+	case poeditor.Plural:
+		var ps []Pair
+		for _, f := range t.Forms() {
+			ps = append(ps, Pair{Source: src, Translation: f.Text, Form: f.Category})
+		}
+		return ps
+	}
+	return nil
+}
+
+// RunLanguage lists the terms of the language and of the source language and
+// checks them with Run
+func RunLanguage(l poeditor.Language, source string, checks ...Check) ([]Finding, error) {
+	src := poeditor.Language{Project: l.Project, Code: source}
+	sourceTerms, err := src.ListTerms()
+	if err != nil {
+		return nil, err
+	}
+	terms, err := l.ListTerms()
+	if err != nil {
+		return nil, err
+	}
+	return Run(sourceTerms, terms, checks...), nil
+}
+
+// AddComments comments on the terms with findings at least as severe as min,
+// with one comment per term listing its findings and the language
+func AddComments(p *poeditor.Project, language string, findings []Finding, min string) (poeditor.CountResult, error) {
+	var (
+		comments []poeditor.TermComment
+		index    = make(map[poeditor.TermBase]int)
+	)
+	for _, f := range findings {
+		if !AtLeast(f.Severity, min) {
+			continue
+		}
+		msg := f.Check + ": " + f.Message
+		if f.Form != "" {
+			msg = f.Form + " form " + msg
+		}
+		i, ok := index[f.TermBase]
+		if !ok {
+			i = len(comments)
+			index[f.TermBase] = i
+			comments = append(comments, poeditor.TermComment{
+				TermBase: f.TermBase,
+				Comment:  "QA " + language + ":",
+			})
+		}
+		comments[i].Comment += "\n- " + msg
+	}
+	if len(comments) == 0 {
+		return poeditor.CountResult{}, nil
+	}
+	return p.AddComments(comments)
+}
+
+// trim returns s without leading and trailing whitespace, and the removed
+// whitespace
+func trim(s string) (leading, text, trailing string) {
+	text = strings.TrimLeft(s, " \t\r\n 　")
+	leading = s[:len(s)-len(text)]
+	trimmed := strings.TrimRight(text, " \t\r\n 　")
+	return leading, trimmed, text[len(trimmed):]
+}
@@ -0,0 +1,73 @@
+package qa_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/blacksails/poeditor"
+	"github.com/blacksails/poeditor/internal/termtest"
+	"github.com/blacksails/poeditor/qa"
+)
+
+func TestChecks(t *testing.T) {
+	tests := []struct {
+		check       qa.Check
+		source, tr  string
+		form        string
+		wantProblem bool
+	}{
+		{qa.Placeholders, "%s has %d files", "%[2]d Dateien von %[1]s", "", false},
+		{qa.Placeholders, "Hello {name}", "Hallo {Name}", "", true},
+		{qa.Placeholders, "{{.Count}} cats", "{{ .Count }} Katzen", "", false},
+		{qa.Placeholders, "%d files", "eine Datei", "one", false},
+		{qa.Placeholders, "%d files", "Dateien", "other", true},
+		{qa.Placeholders, "files", "%d Dateien", "", true},
+		{qa.Markup, "<b>Bold</b> text<br>", "<b>Fett</b> Text<br/>", "", false},
+		{qa.Markup, "<b>Bold</b>", "<b>Fett<b>", "", true},
+		{qa.Markup, "<b>Bold</b>", "<i>Fett</i>", "", true},
+		{qa.Whitespace, "Name: ", "Name: ", "", false},
+		{qa.Whitespace, "Name: ", "Name:", "", true},
+		{qa.Punctuation, "Saved.", "保存しました。", "", false},
+		{qa.Punctuation, "Saved.", "Gespeichert!", "", true},
+		{qa.Punctuation, "Save", "Speichern.", "", true},
+		{qa.Newlines, "a\nb", "a b", "", true},
+		{qa.LengthRatio(0.5, 2), "A longer source text", "Kurz", "", true},
+		{qa.LengthRatio(0.5, 2), "Short", "Sehr viel länger als gedacht", "", false},
+		{qa.Identical, "Cancel", "Cancel", "", true},
+		{qa.Identical, "%d%%", "%d%%", "", false},
+	}
+	for _, test := range tests {
+		msgs := test.check.Run(qa.Pair{Source: test.source, Translation: test.tr, Form: test.form})
+		if (len(msgs) > 0) != test.wantProblem {
+			t.Errorf("%s %q -> %q: expected problem %v, got %q", test.check.Name, test.source, test.tr, test.wantProblem, msgs)
+		}
+	}
+}
+
+func TestRun(t *testing.T) {
+	source := []poeditor.TermTranslated{
+		termtest.Translated("greeting", "Hello %s!"),
+		termtest.Translated("files", poeditor.Plural{One: "%d file", Other: "%d files"}),
+		termtest.Translated("untranslated", "Text"),
+		termtest.Translated("folders", poeditor.Plural{One: "one folder", Other: "%d folders"}),
+	}
+	translations := []poeditor.TermTranslated{
+		termtest.Translated("greeting", "Hallo!"),
+		termtest.Translated("files", poeditor.Plural{One: "Eine Datei", Few: "%d soubory", Other: "%s Dateien"}),
+		termtest.Translated("untranslated", ""),
+		termtest.Translated("unknown", "Text"),
+		termtest.Translated("folders", poeditor.Plural{One: "%d папка", Few: "%d папки", Many: "%d папок", Other: "%d папки"}),
+	}
+	got := qa.Run(source, translations, qa.Placeholders)
+	want := []qa.Finding{
+		{TermBase: poeditor.TermBase{Term: "greeting"}, Check: "placeholders", Severity: qa.Error, Message: "missing placeholder %[1]s"},
+		{TermBase: poeditor.TermBase{Term: "files"}, Check: "placeholders", Severity: qa.Error, Form: "other", Message: "missing placeholder %[1]d"},
+		{TermBase: poeditor.TermBase{Term: "files"}, Check: "placeholders", Severity: qa.Error, Form: "other", Message: "unexpected placeholder %[1]s"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("\nExpected %v\nGot      %v", want, got)
+	}
+	if !qa.AtLeast(qa.Error, qa.Warning) || qa.AtLeast(qa.Info, qa.Warning) {
+		t.Error("Expected errors to be more severe than warnings, and infos less")
+	}
+}
@@ -0,0 +1,84 @@
+package poeditor
+
+import (
+	"bufio"
+	"encoding/xml"
+	"io"
+	"strings"
+)
+
+// ReadRESX decodes a .NET .resx or .resw file. Each string <data> element
+// becomes a term and its <comment> the term comment. Resources with a type or
+// mimetype attribute, such as embedded images, are skipped.
+func ReadRESX(r io.Reader) ([]TermTranslated, error) {
+	var doc resxDocument
+	if err := xml.NewDecoder(r).Decode(&doc); err != nil {
+		return nil, err
+	}
+	terms := make([]TermTranslated, 0, len(doc.Data))
+	for _, d := range doc.Data {
+		if d.Type != "" || d.MimeType != "" {
+			continue
+		}
+		t := flatTerm(d.Name, d.Value)
+		t.Comment = d.Comment
+		terms = append(terms, t)
+	}
+	return terms, nil
+}
+
+// WriteRESX encodes terms as a .resx file including the standard resource
+// headers. The output is also a valid .resw file. Plurals are written as ICU
+// plural messages.
+func WriteRESX(w io.Writer, terms []TermTranslated) error {
+	doc := resxDocument{Headers: resxHeaders}
+	doc.Data = make([]resxData, len(terms))
+	for i, t := range terms {
+		value, err := flatContent(t.Translation.Content)
+		if err != nil {
+			return err
+		}
+		doc.Data[i] = resxData{
+			Name:    t.Term.Term,
+			Space:   "preserve",
+			Value:   value,
+			Comment: t.Comment,
+		}
+	}
+	bw := bufio.NewWriter(w)
+	bw.WriteString(strings.TrimSpace(xml.Header) + "\n")
+	enc := xml.NewEncoder(bw)
+	enc.Indent("", "  ")
+	if err := enc.Encode(doc); err != nil {
+		return err
+	}
+	bw.WriteString("\n")
+	return bw.Flush()
+}
+
+var resxHeaders = []resxHeader{
+	{Name: "resmimetype", Value: "text/microsoft-resx"},
+	{Name: "version", Value: "2.0"},
+	{Name: "reader", Value: "System.Resources.ResXResourceReader, System.Windows.Forms, Version=4.0.0.0, Culture=neutral, PublicKeyToken=b77a5c561934e089"},
+	{Name: "writer", Value: "System.Resources.ResXResourceWriter, System.Windows.Forms, Version=4.0.0.0, Culture=neutral, PublicKeyToken=b77a5c561934e089"},
+}
+
+type resxDocument struct {
+	XMLName xml.Name     `xml:"root"`
+	Headers []resxHeader `xml:"resheader"`
+	Data    []resxData   `xml:"data"`
+}
+
+type resxHeader struct {
+	Name  string `xml:"name,attr"`
+	Value string `xml:"value"`
+}
+
+type resxData struct {
+	Name     string `xml:"name,attr"`
+	Type     string `xml:"type,attr,omitempty"`
+	MimeType string `xml:"mimetype,attr,omitempty"`
+	Space    string `xml:"http://www.w3.org/XML/1998/namespace space,attr,omitempty"`
+	Value    string `xml:"value"`
+	Comment  string `xml:"comment,omitempty"`
+}
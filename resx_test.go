@@ -0,0 +1,68 @@
+package poeditor_test
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/blacksails/poeditor"
+)
+
+const testRESX = `<?xml version="1.0" encoding="utf-8"?>
+<root>
+  <resheader name="resmimetype">
+    <value>text/microsoft-resx</value>
+  </resheader>
+  <data name="Greeting" xml:space="preserve">
+    <value>Hello, &lt;b&gt;world&lt;/b&gt;</value>
+    <comment>Shown on the start page</comment>
+  </data>
+  <data name="Logo" type="System.Drawing.Bitmap, System.Drawing" mimetype="application/x-microsoft.net.object.bytearray.base64">
+    <value>iVBORw0KGgo=</value>
+  </data>
+  <data name="Timeout" type="System.Int32, mscorlib">
+    <value>30</value>
+  </data>
+  <data name="Items" xml:space="preserve">
+    <value>{count, plural, one{# item} other{# items}}</value>
+  </data>
+</root>
+`
+
+func TestReadRESX(t *testing.T) {
+	got, err := poeditor.ReadRESX(strings.NewReader(testRESX))
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	expected := []poeditor.TermTranslated{
+		newTermTranslated("Greeting", "Hello, <b>world</b>", "Shown on the start page"),
+		newTermTranslated("Items", poeditor.Plural{One: "# item", Other: "# items"}, ""),
+	}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("\nExpected %+v \nGot      %+v", expected, got)
+	}
+}
+
+func TestWriteRESX(t *testing.T) {
+	terms := []poeditor.TermTranslated{
+		newTermTranslated("Greeting", "  Hello, <b>world</b> & more  ", "Shown on the start page"),
+		newTermTranslated("Items", poeditor.Plural{One: "# item", Other: "# items"}, ""),
+	}
+	var buf bytes.Buffer
+	if err := poeditor.WriteRESX(&buf, terms); err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	for _, s := range []string{`<?xml version="1.0" encoding="UTF-8"?>`, `<value>text/microsoft-resx</value>`, `xml:space="preserve"`} {
+		if !strings.Contains(buf.String(), s) {
+			t.Errorf("Expected output to contain %s\n%s", s, buf.String())
+		}
+	}
+	got, err := poeditor.ReadRESX(&buf)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if !reflect.DeepEqual(got, terms) {
+		t.Errorf("\nExpected %+v \nGot      %+v", terms, got)
+	}
+}
@@ -0,0 +1,281 @@
+package poeditor
+
+import (
+	"bufio"
+	"encoding/csv"
+	"errors"
+	"io"
+	"strings"
+)
+
+// ErrSheetHeader is returned when a spreadsheet has no term column
+var ErrSheetHeader = errors.New("spreadsheet header must contain a term column")
+
+// Sheet is a multi language spreadsheet of a project. There is one row per
+// term and context, and one column per language. Plural translations are
+// written to cells as ICU plural messages.
+type Sheet struct {
+	Languages []string
+	Rows      []SheetRow
+}
+
+// SheetRow is a single term along with its translations keyed by language
+// code
+type SheetRow struct {
+	Term
+	Translations map[string]string
+}
+
+// SheetChange is a translation which differs between a spreadsheet and
+// POEditor
+type SheetChange struct {
+	TermBase
+	Language string
+	Old      string
+	New      string
+}
+
+// SheetReport is returned from Project.ApplySheet
+type SheetReport struct {
+	// Changes lists the translations which were, or in a dry run would be,
+	// updated
+	Changes []SheetChange
+	// Unknown lists rows whose term does not exist in the project
+	Unknown []TermBase
+	// Results holds the update counts per language code
+	Results map[string]CountResult
+}
+
+// The fixed columns of a spreadsheet. All other columns are language codes.
+var sheetColumns = []string{"term", "context", "plural", "comment", "tags", "reference"}
+
+// Sheet builds a spreadsheet of the project's terms along with translations
+// for the given language codes. If no codes are given all languages of the
+// project are included.
+func (p *Project) Sheet(codes []string) (Sheet, error) {
+	if len(codes) == 0 {
+		ls, err := p.ListLanguages()
+		if err != nil {
+			return Sheet{}, err
+		}
+		for _, l := range ls {
+			codes = append(codes, l.Code)
+		}
+	}
+	terms, err := p.ListTerms()
+	if err != nil {
+		return Sheet{}, err
+	}
+	s := Sheet{Languages: codes, Rows: make([]SheetRow, len(terms))}
+	index := make(map[TermBase]int, len(terms))
+	for i, t := range terms {
+		s.Rows[i] = SheetRow{Term: t, Translations: make(map[string]string)}
+		index[t.TermBase] = i
+	}
+	for _, code := range codes {
+		l := Language{Project: p, Code: code}
+		translated, err := l.ListTerms()
+		if err != nil {
+			return Sheet{}, err
+		}
+		for _, t := range translated {
+			i, ok := index[t.TermBase]
+			if !ok {
+				continue
+			}
+			c, err := flatContent(t.Translation.Content)
+			if err != nil {
+				return Sheet{}, err
+			}
+			s.Rows[i].Translations[code] = c
+		}
+	}
+	return s, nil
+}
+
+// ApplySheet updates the translations of the project with the ones found in
+// the spreadsheet using Language.Update. Only cells which differ from POEditor
+// are sent and empty cells are ignored, so translations are never removed.
+// With dryRun set nothing is updated, but the report lists the changes that
+// would have been made.
+func (p *Project) ApplySheet(s Sheet, dryRun bool) (SheetReport, error) {
+	report := SheetReport{Results: make(map[string]CountResult)}
+	terms, err := p.ListTerms()
+	if err != nil {
+		return report, err
+	}
+	known := make(map[TermBase]bool, len(terms))
+	for _, t := range terms {
+		known[t.TermBase] = true
+	}
+	for _, row := range s.Rows {
+		if !known[row.TermBase] {
+			report.Unknown = append(report.Unknown, row.TermBase)
+		}
+	}
+	for _, code := range s.Languages {
+		l := Language{Project: p, Code: code}
+		current, err := l.ListTerms()
+		if err != nil {
+			return report, err
+		}
+		old := make(map[TermBase]string, len(current))
+		for _, t := range current {
+			c, err := flatContent(t.Translation.Content)
+			if err != nil {
+				return report, err
+			}
+			old[t.TermBase] = c
+		}
+		var updates []TermTranslation
+		for _, row := range s.Rows {
+			cell := row.Translations[code]
+			if !known[row.TermBase] || cell == "" || cell == old[row.TermBase] {
+				continue
+			}
+			report.Changes = append(report.Changes, SheetChange{
+				TermBase: row.TermBase,
+				Language: code,
+				Old:      old[row.TermBase],
+				New:      cell,
+			})
+			t := flatTerm(row.Term.Term, cell)
+			if row.Plural == "" {
+				t.Translation.Content = cell
+			}
+			updates = append(updates, TermTranslation{
+				TermBase:    row.TermBase,
+				Translation: Translation{Content: t.Translation.Content},
+			})
+		}
+		if dryRun || len(updates) == 0 {
+			continue
+		}
+		res, err := l.Update(updates)
+		if err != nil {
+			return report, err
+		}
+		report.Results[code] = res
+	}
+	return report, nil
+}
+
+// ReadSheetCSV reads a spreadsheet from a CSV file
+func ReadSheetCSV(r io.Reader) (Sheet, error) {
+	br := bufio.NewReader(r)
+	// Skip the byte order mark written by WriteSheetCSV and most spreadsheet
+	// applications
+	if b, err := br.Peek(3); err == nil && string(b) == "\xef\xbb\xbf" {
+		br.Discard(3)
+	}
+	cr := csv.NewReader(br)
+	cr.FieldsPerRecord = -1
+	records, err := cr.ReadAll()
+	if err != nil {
+		return Sheet{}, err
+	}
+	return sheetFromRecords(records)
+}
+
+// WriteSheetCSV writes a spreadsheet as a CSV file. A UTF-8 byte order mark
+// is written first so spreadsheet applications detect the encoding.
+func WriteSheetCSV(w io.Writer, s Sheet) error {
+	if _, err := io.WriteString(w, "\xef\xbb\xbf"); err != nil {
+		return err
+	}
+	cw := csv.NewWriter(w)
+	if err := cw.WriteAll(s.records()); err != nil {
+		return err
+	}
+	return cw.Error()
+}
+
+// ReadSheetXLSX reads a spreadsheet from the first worksheet of an .xlsx file
+func ReadSheetXLSX(r io.ReaderAt, size int64) (Sheet, error) {
+	rows, err := readXLSX(r, size)
+	if err != nil {
+		return Sheet{}, err
+	}
+	return sheetFromRecords(rows)
+}
+
+// WriteSheetXLSX writes a spreadsheet as an .xlsx file
+func WriteSheetXLSX(w io.Writer, s Sheet) error {
+	return writeXLSX(w, "Terms", s.records())
+}
+
+func (s Sheet) records() [][]string {
+	header := append(append([]string{}, sheetColumns...), s.Languages...)
+	records := [][]string{header}
+	for _, row := range s.Rows {
+		r := []string{
+			row.Term.Term,
+			row.Context,
+			row.Plural,
+			row.Comment,
+			strings.Join(row.Tags, ", "),
+			row.Reference,
+		}
+		for _, code := range s.Languages {
+			r = append(r, row.Translations[code])
+		}
+		records = append(records, r)
+	}
+	return records
+}
+
+func sheetFromRecords(records [][]string) (Sheet, error) {
+	var s Sheet
+	if len(records) == 0 {
+		return s, ErrSheetHeader
+	}
+	columns := make(map[string]int)
+	languages := make(map[int]string)
+	for i, name := range records[0] {
+		name = strings.TrimSpace(name)
+		fixed := false
+		for _, c := range sheetColumns {
+			if strings.EqualFold(name, c) {
+				columns[c] = i
+				fixed = true
+			}
+		}
+		if !fixed && name != "" {
+			languages[i] = name
+			s.Languages = append(s.Languages, name)
+		}
+	}
+	if _, ok := columns["term"]; !ok {
+		return s, ErrSheetHeader
+	}
+	cell := func(record []string, column string) string {
+		i, ok := columns[column]
+		if !ok || i >= len(record) {
+			return ""
+		}
+		return record[i]
+	}
+	for _, record := range records[1:] {
+		row := SheetRow{Translations: make(map[string]string)}
+		row.Term.Term = cell(record, "term")
+		if row.Term.Term == "" {
+			continue
+		}
+		row.Context = cell(record, "context")
+		row.Plural = cell(record, "plural")
+		row.Comment = cell(record, "comment")
+		row.Reference = cell(record, "reference")
+		for _, tag := range strings.Split(cell(record, "tags"), ",") {
+			if tag = strings.TrimSpace(tag); tag != "" {
+				row.Tags = append(row.Tags, tag)
+			}
+		}
+		for i, code := range languages {
+			if i < len(record) && record[i] != "" {
+				row.Translations[code] = record[i]
+			}
+		}
+		s.Rows = append(s.Rows, row)
+	}
+	return s, nil
+}
@@ -0,0 +1,303 @@
+package poeditor_test
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/blacksails/poeditor"
+)
+
+// newTestPOEditor returns a POEditor backed by a test server. The handler
+// gets the endpoint and form of each request and returns the result or an
+// error message.
+func newTestPOEditor(t *testing.T, handler func(endpoint string, form map[string]string) (interface{}, string)) *poeditor.POEditor {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseMultipartForm(1 << 20); err != nil {
+			t.Errorf("Unexpected error: %s", err)
+		}
+		form := make(map[string]string)
+		for k, v := range r.MultipartForm.Value {
+			form[k] = v[0]
+		}
+		result, msg := handler(r.URL.Path, form)
+		res := map[string]interface{}{
+			"response": map[string]string{"status": "success", "code": "200", "message": "OK"},
+			"result":   result,
+		}
+		if msg != "" {
+			res["response"] = map[string]string{"status": "fail", "code": "4011", "message": msg}
+		}
+		json.NewEncoder(w).Encode(res)
+	}))
+	t.Cleanup(srv.Close)
+	return poeditor.NewWithEndpoint("token", srv.URL)
+}
+
+// sheetProject serves a project with English and German translations and
+// records the updates
+func sheetProject(t *testing.T, updates map[string][]poeditor.TermTranslation) *poeditor.Project {
+	terms := []poeditor.Term{
+		{TermBase: poeditor.TermBase{Term: "hello"}, Comment: "Greeting", Tags: []string{"ui", "start"}},
+		{TermBase: poeditor.TermBase{Term: "items", Context: "cart"}, Plural: "items"},
+	}
+	translations := map[string][]poeditor.TermTranslated{
+		"en": {
+			{Term: terms[0], Translation: poeditor.Translation{Content: "Hello"}},
+			{Term: terms[1], Translation: poeditor.Translation{Content: poeditor.Plural{One: "# item", Other: "# items"}}},
+		},
+		"de": {
+			{Term: terms[0], Translation: poeditor.Translation{Content: "Hallo"}},
+			{Term: terms[1], Translation: poeditor.Translation{Content: ""}},
+		},
+	}
+	poe := newTestPOEditor(t, func(endpoint string, form map[string]string) (interface{}, string) {
+		switch endpoint {
+		case "/languages/list":
+			return map[string]interface{}{"languages": []map[string]string{{"code": "en"}, {"code": "de"}}}, ""
+		case "/terms/list":
+			if form["language"] == "" {
+				return map[string]interface{}{"terms": terms}, ""
+			}
+			return map[string]interface{}{"terms": translations[form["language"]]}, ""
+		case "/languages/update":
+			var ts []poeditor.TermTranslation
+			if err := json.Unmarshal([]byte(form["data"]), &ts); err != nil {
+				t.Errorf("Unexpected error: %s", err)
+			}
+			updates[form["language"]] = append(updates[form["language"]], ts...)
+			return map[string]interface{}{"translations": poeditor.CountResult{Parsed: len(ts), Updated: len(ts)}}, ""
+		}
+		t.Errorf("Unexpected request to %s", endpoint)
+		return nil, "unexpected request"
+	})
+	return poe.Project(1)
+}
+
+func TestProjectSheet(t *testing.T) {
+	p := sheetProject(t, nil)
+	s, err := p.Sheet(nil)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if !reflect.DeepEqual(s.Languages, []string{"en", "de"}) {
+		t.Errorf("Expected all languages, got %v", s.Languages)
+	}
+	if len(s.Rows) != 2 {
+		t.Fatalf("Expected 2 rows, got %d", len(s.Rows))
+	}
+	expected := []map[string]string{
+		{"en": "Hello", "de": "Hallo"},
+		{"en": "{count, plural, one{# item} other{# items}}", "de": ""},
+	}
+	for i, row := range s.Rows {
+		if !reflect.DeepEqual(row.Translations, expected[i]) {
+			t.Errorf("%s: \nExpected %v \nGot      %v", row.Term.Term, expected[i], row.Translations)
+		}
+	}
+	if s.Rows[0].Comment != "Greeting" || s.Rows[1].Context != "cart" {
+		t.Errorf("Expected term details, got %+v", s.Rows)
+	}
+}
+
+func TestProjectApplySheet(t *testing.T) {
+	s := poeditor.Sheet{
+		Languages: []string{"en", "de"},
+		Rows: []poeditor.SheetRow{
+			{
+				Term:         poeditor.Term{TermBase: poeditor.TermBase{Term: "hello"}},
+				Translations: map[string]string{"en": "Hello", "de": "Guten Tag"},
+			},
+			{
+				Term:         poeditor.Term{TermBase: poeditor.TermBase{Term: "items", Context: "cart"}, Plural: "items"},
+				Translations: map[string]string{"de": "{count, plural, one{# Artikel} other{# Artikel}}"},
+			},
+			{
+				Term:         poeditor.Term{TermBase: poeditor.TermBase{Term: "unknown"}},
+				Translations: map[string]string{"de": "Unbekannt"},
+			},
+		},
+	}
+	changes := []poeditor.SheetChange{
+		{TermBase: poeditor.TermBase{Term: "hello"}, Language: "de", Old: "Hallo", New: "Guten Tag"},
+		{TermBase: poeditor.TermBase{Term: "items", Context: "cart"}, Language: "de", New: "{count, plural, one{# Artikel} other{# Artikel}}"},
+	}
+	unknown := []poeditor.TermBase{{Term: "unknown"}}
+
+	updates := make(map[string][]poeditor.TermTranslation)
+	report, err := sheetProject(t, updates).ApplySheet(s, true)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if !reflect.DeepEqual(report.Changes, changes) || !reflect.DeepEqual(report.Unknown, unknown) {
+		t.Errorf("\nExpected %+v %+v \nGot      %+v %+v", changes, unknown, report.Changes, report.Unknown)
+	}
+	if len(updates) != 0 || len(report.Results) != 0 {
+		t.Errorf("Expected a dry run not to update, got %+v", updates)
+	}
+
+	report, err = sheetProject(t, updates).ApplySheet(s, false)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if !reflect.DeepEqual(report.Changes, changes) {
+		t.Errorf("\nExpected %+v \nGot      %+v", changes, report.Changes)
+	}
+	expected := map[string][]poeditor.TermTranslation{
+		"de": {
+			{TermBase: poeditor.TermBase{Term: "hello"}, Translation: poeditor.Translation{Content: "Guten Tag"}},
+			{TermBase: poeditor.TermBase{Term: "items", Context: "cart"}, Translation: poeditor.Translation{Content: poeditor.Plural{One: "# Artikel", Other: "# Artikel"}}},
+		},
+	}
+	for _, ts := range updates {
+		for i := range ts {
+			ts[i].Translation.Updated = poeditor.Translation{}.Updated
+		}
+	}
+	if !reflect.DeepEqual(updates, expected) {
+		t.Errorf("\nExpected %+v \nGot      %+v", expected, updates)
+	}
+	if report.Results["de"].Updated != 2 {
+		t.Errorf("Expected the update counts in the report, got %+v", report.Results)
+	}
+}
+
+var testSheet = poeditor.Sheet{
+	Languages: []string{"en", "de"},
+	Rows: []poeditor.SheetRow{
+		{
+			Term: poeditor.Term{
+				TermBase:  poeditor.TermBase{Term: "hello", Context: "start"},
+				Comment:   "Greeting, \"quoted\"\non two lines",
+				Tags:      []string{"ui", "start"},
+				Reference: "main.go:12",
+			},
+			Translations: map[string]string{"en": "Hello", "de": "Hallo & <b>Willkommen</b>"},
+		},
+		{
+			Term:         poeditor.Term{TermBase: poeditor.TermBase{Term: "items"}, Plural: "items"},
+			Translations: map[string]string{"en": "{count, plural, one{# item} other{# items}}"},
+		},
+	},
+}
+
+func TestSheetCSV(t *testing.T) {
+	var buf bytes.Buffer
+	if err := poeditor.WriteSheetCSV(&buf, testSheet); err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if !strings.HasPrefix(buf.String(), "\xef\xbb\xbfterm,context,") {
+		t.Errorf("Expected a byte order mark and header, got %q", buf.String())
+	}
+	for name, input := range map[string]string{
+		"with byte order mark":    buf.String(),
+		"without byte order mark": strings.TrimPrefix(buf.String(), "\xef\xbb\xbf"),
+	} {
+		s, err := poeditor.ReadSheetCSV(strings.NewReader(input))
+		if err != nil {
+			t.Fatalf("%s: Unexpected error: %s", name, err)
+		}
+		if !reflect.DeepEqual(s, testSheet) {
+			t.Errorf("%s: \nExpected %+v \nGot      %+v", name, testSheet, s)
+		}
+	}
+	if _, err := poeditor.ReadSheetCSV(strings.NewReader("key,en\nhello,Hello\n")); err != poeditor.ErrSheetHeader {
+		t.Errorf("Expected ErrSheetHeader, got %v", err)
+	}
+}
+
+func TestSheetXLSX(t *testing.T) {
+	var buf bytes.Buffer
+	if err := poeditor.WriteSheetXLSX(&buf, testSheet); err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	s, err := poeditor.ReadSheetXLSX(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if !reflect.DeepEqual(s, testSheet) {
+		t.Errorf("\nExpected %+v \nGot      %+v", testSheet, s)
+	}
+}
+
+func TestReadSheetXLSXSharedStrings(t *testing.T) {
+	b := newXLSX(t, `<row r="1"><c r="A1" t="s"><v>0</v></c><c r="C1" t="s"><v>1</v></c></row>`+
+		`<row r="3"><c r="A3" t="inlineStr"><is><t>hello</t></is></c><c r="C3" t="s"><v>2</v></c></row>`,
+		`<si><t>term</t></si><si><t>de</t></si><si><r><t>Hal</t></r><r><t>lo</t></r></si>`)
+	s, err := poeditor.ReadSheetXLSX(bytes.NewReader(b), int64(len(b)))
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	expected := poeditor.Sheet{
+		Languages: []string{"de"},
+		Rows: []poeditor.SheetRow{{
+			Term:         poeditor.Term{TermBase: poeditor.TermBase{Term: "hello"}},
+			Translations: map[string]string{"de": "Hallo"},
+		}},
+	}
+	if !reflect.DeepEqual(s, expected) {
+		t.Errorf("\nExpected %+v \nGot      %+v", expected, s)
+	}
+}
+
+func TestReadSheetXLSXMalformed(t *testing.T) {
+	tests := []struct {
+		name  string
+		sheet string
+	}{
+		{"lower case reference", `<row r="1"><c r="a1"><v>term</v></c></row>`},
+		{"reference without column", `<row r="1"><c r="1A"><v>term</v></c></row>`},
+		{"reference without row", `<row r="1"><c r="A"><v>term</v></c></row>`},
+		{"huge column", `<row r="1"><c r="ZZZZZZZZZZ1"><v>term</v></c></row>`},
+		{"huge reference row", `<row r="1"><c r="A99999999"><v>term</v></c></row>`},
+		{"negative row", `<row r="-1"><c><v>term</v></c></row>`},
+		{"huge row", `<row r="99999999"><c><v>term</v></c></row>`},
+		{"shared string out of range", `<row r="1"><c r="A1" t="s"><v>5</v></c></row>`},
+	}
+	for _, test := range tests {
+		b := newXLSX(t, test.sheet, "")
+		if _, err := poeditor.ReadSheetXLSX(bytes.NewReader(b), int64(len(b))); err == nil {
+			t.Errorf("%s: Expected an error", test.name)
+		}
+	}
+	if _, err := poeditor.ReadSheetXLSX(strings.NewReader("not a zip"), 9); err == nil {
+		t.Errorf("Expected an error for a file which is not a zip archive")
+	}
+}
+
+// newXLSX returns a workbook with a worksheet of the given rows and an
+// optional shared string table
+func newXLSX(t *testing.T, rows, shared string) []byte {
+	t.Helper()
+	files := map[string]string{
+		"xl/workbook.xml": `<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">` +
+			`<sheets><sheet name="Terms" sheetId="1" r:id="rId1"/></sheets></workbook>`,
+		"xl/_rels/workbook.xml.rels": `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+			`<Relationship Id="rId1" Target="worksheets/sheet1.xml"/></Relationships>`,
+		"xl/worksheets/sheet1.xml": `<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>` +
+			rows + `</sheetData></worksheet>`,
+	}
+	if shared != "" {
+		files["xl/sharedStrings.xml"] = `<sst xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">` + shared + `</sst>`
+	}
+	var buf bytes.Buffer
+	z := zip.NewWriter(&buf)
+	for name, body := range files {
+		w, err := z.Create(name)
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		io.WriteString(w, body)
+	}
+	if err := z.Close(); err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	return buf.Bytes()
+}
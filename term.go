@@ -61,9 +61,13 @@ func (l *Language) ListTerms() ([]TermTranslated, error) {
 	return res.Terms, err
 }
 
-// AddTerms adds the given terms to the project
+// AddTerms adds the given terms to the project. The term validators of the
+// project are run first, see AddTermValidator.
 func (p *Project) AddTerms(terms []Term) (CountResult, error) {
 	var res termsCountResult
+	if err := p.validateTerms(OperationAdd, terms); err != nil {
+		return res.Terms, err
+	}
 	jsonTerms, err := json.Marshal(terms)
 	if err != nil {
 		return res.Terms, err
@@ -76,6 +80,9 @@ func (p *Project) AddTerms(terms []Term) (CountResult, error) {
 // terms. Setting fuzzyTrigger to true marks associated translations as fuzzy.
 func (p *Project) UpdateTerms(terms []TermUpdate, fuzzyTrigger bool) (CountResult, error) {
 	var res termsCountResult
+	if err := p.validateUpdates(terms); err != nil {
+		return res.Terms, err
+	}
 	jsonTerms, err := json.Marshal(terms)
 	if err != nil {
 		return res.Terms, err
@@ -136,23 +143,77 @@ func (t *Translation) UnmarshalJSON(bytes []byte) (err error) {
 	}
 	if m, ok := c.(map[string]interface{}); ok {
 		p := Plural{}
-		if s, ok := m["one"].(string); ok {
-			p.One = s
-		}
-		if s, ok := m["other"].(string); ok {
-			p.Other = s
+		for k, f := range pluralFields(&p) {
+			if s, ok := m[k].(string); ok {
+				*f = s
+			}
 		}
 		t.Content = p
 	}
 	return nil
 }
 
-// Plural is a plural translation
+// Plural is a plural translation. One and Other are used by most languages,
+// the remaining CLDR plural categories are only sent when they are set.
 type Plural struct {
+	Zero  string `json:"zero,omitempty"`
 	One   string `json:"one"`
+	Two   string `json:"two,omitempty"`
+	Few   string `json:"few,omitempty"`
+	Many  string `json:"many,omitempty"`
 	Other string `json:"other"`
 }
 
+// PluralForm is the text of a CLDR plural category
+type PluralForm struct {
+	Category string
+	Text     string
+}
+
+// Forms returns the forms of the plural which are set, in CLDR order
+func (p Plural) Forms() []PluralForm {
+	var forms []PluralForm
+	for _, f := range []PluralForm{
+		{"zero", p.Zero}, {"one", p.One}, {"two", p.Two},
+		{"few", p.Few}, {"many", p.Many}, {"other", p.Other},
+	} {
+		if f.Text != "" {
+			forms = append(forms, f)
+		}
+	}
+	return forms
+}
+
+// Form returns the text of a CLDR plural category, or an empty string if the
+// category is unknown
+func (p Plural) Form(category string) string {
+	if f, ok := pluralFields(&p)[category]; ok {
+		return *f
+	}
+	return ""
+}
+
+// SetForm sets the text of a CLDR plural category. Unknown categories are
+// ignored.
+func (p *Plural) SetForm(category, text string) {
+	if f, ok := pluralFields(p)[category]; ok {
+		*f = text
+	}
+}
+
+// EmptyContent reports whether translation content has no text. Plurals are
+// empty when none of their forms are set, and content which is neither a
+// string nor a Plural is empty.
+func EmptyContent(c interface{}) bool {
+	switch c := c.(type) {
+	case string:
+		return c == ""
+	case Plural:
+		return c == Plural{}
+	}
+	return true
+}
+
 type listTermsResult struct {
 	Terms []Term
 }
@@ -35,3 +35,51 @@ func TestTranslationUnmarshalJSON(t *testing.T) {
 		}
 	}
 }
+
+func TestEmptyContent(t *testing.T) {
+	tests := []struct {
+		content interface{}
+		empty   bool
+	}{
+		{"", true},
+		{"text", false},
+		{poeditor.Plural{}, true},
+		{poeditor.Plural{Few: "few"}, false},
+		{nil, true},
+		{42, true},
+	}
+	for _, test := range tests {
+		if got := poeditor.EmptyContent(test.content); got != test.empty {
+			t.Errorf("%#v: expected %t, got %t", test.content, test.empty, got)
+		}
+	}
+}
+
+func TestPluralForms(t *testing.T) {
+	p := poeditor.Plural{Other: "%d files", Few: "%d файла", Zero: "none"}
+	expected := []poeditor.PluralForm{{"zero", "none"}, {"few", "%d файла"}, {"other", "%d files"}}
+	if got := p.Forms(); !reflect.DeepEqual(got, expected) {
+		t.Errorf("\nExpected %+v \nGot      %+v", expected, got)
+	}
+	if got := (poeditor.Plural{}).Forms(); got != nil {
+		t.Errorf("Expected no forms, got %+v", got)
+	}
+}
+
+func TestPluralForm(t *testing.T) {
+	var p poeditor.Plural
+	for _, c := range []string{"zero", "one", "two", "few", "many", "other"} {
+		p.SetForm(c, c+" text")
+	}
+	p.SetForm("unknown", "ignored")
+	expected := poeditor.Plural{Zero: "zero text", One: "one text", Two: "two text", Few: "few text", Many: "many text", Other: "other text"}
+	if p != expected {
+		t.Errorf("\nExpected %+v \nGot      %+v", expected, p)
+	}
+	if got := p.Form("few"); got != "few text" {
+		t.Errorf("Expected the few form, got %q", got)
+	}
+	if got := p.Form("unknown"); got != "" {
+		t.Errorf("Expected no text for an unknown category, got %q", got)
+	}
+}
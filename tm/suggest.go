@@ -0,0 +1,80 @@
+package tm
+
+import (
+	"github.com/blacksails/poeditor"
+)
+
+// Suggestion is the best match of the memory for an untranslated term
+type Suggestion struct {
+	poeditor.TermBase
+	// Source is the text of the term in the source language
+	Source string `json:"source"`
+	Match  Match  `json:"match"`
+}
+
+// Suggest returns the best match with a score of at least min for each
+// untranslated target term, looking up the translation of the term in the
+// source language, or the term itself when it is untranslated. Terms with a
+// plural are skipped.
+func (m *Memory) Suggest(sourceLanguage string, source []poeditor.TermTranslated, language string, target []poeditor.TermTranslated, min float64) []Suggestion {
+	sources := make(map[poeditor.TermBase]poeditor.TermTranslated, len(source))
+	for _, t := range source {
+		sources[t.TermBase] = t
+	}
+	var suggestions []Suggestion
+	for _, t := range target {
+		if t.Plural != "" || !poeditor.EmptyContent(t.Translation.Content) {
+			continue
+		}
+		text := t.Term.Term
+		if src, ok := sources[t.TermBase]; ok {
+			if s, ok := src.Translation.Content.(string); ok && s != "" {
+				text = s
+			}
+		}
+		matches := m.Lookup(sourceLanguage, language, text, min)
+		if len(matches) == 0 {
+			continue
+		}
+		suggestions = append(suggestions, Suggestion{TermBase: t.TermBase, Source: text, Match: matches[0]})
+	}
+	return suggestions
+}
+
+// SuggestLanguage lists the terms of the language and of the source language
+// of its project, and returns the suggestions of Suggest
+func (m *Memory) SuggestLanguage(l poeditor.Language, source string, min float64) ([]Suggestion, error) {
+	src := poeditor.Language{Project: l.Project, Code: source}
+	sourceTerms, err := src.ListTerms()
+	if err != nil {
+		return nil, err
+	}
+	terms, err := l.ListTerms()
+	if err != nil {
+		return nil, err
+	}
+	return m.Suggest(source, sourceTerms, l.Code, terms, min), nil
+}
+
+// Prefill writes the suggestions of SuggestLanguage to the language with
+// Language.Update. Translations of matches whose source differs from the
+// text of the term are marked fuzzy.
+func (m *Memory) Prefill(l poeditor.Language, source string, min float64) ([]Suggestion, poeditor.CountResult, error) {
+	suggestions, err := m.SuggestLanguage(l, source, min)
+	if err != nil || len(suggestions) == 0 {
+		return suggestions, poeditor.CountResult{}, err
+	}
+	translations := make([]poeditor.TermTranslation, len(suggestions))
+	for i, s := range suggestions {
+		fuzzy := 0
+		if s.Match.Source != s.Source {
+			fuzzy = 1
+		}
+		translations[i] = poeditor.TermTranslation{
+			TermBase:    s.TermBase,
+			Translation: poeditor.Translation{Content: s.Match.Translation, Fuzzy: fuzzy},
+		}
+	}
+	c, err := l.Update(translations)
+	return suggestions, c, err
+}
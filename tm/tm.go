@@ -0,0 +1,301 @@
+// Package tm is a local translation memory built from the translations of
+// POEditor projects. It looks up translations of a source text exactly or
+// fuzzily, scoring matches by edit distance, and suggests or pre-fills
+// translations of untranslated terms:
+//
+//	m, err := tm.Build(poe)
+//	err = m.Save(f)
+//	suggestions, err := m.SuggestLanguage(poeditor.Language{Project: p, Code: "de"}, "en", 0.75)
+//
+// Plural translations are remembered form by form, but only terms without
+// plural get suggestions.
+package tm
+
+import (
+	"encoding/json"
+	"io"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/blacksails/poeditor"
+)
+
+// Entry is a remembered translation
+type Entry struct {
+	SourceLanguage string `json:"source_language"`
+	Language       string `json:"language"`
+	Source         string `json:"source"`
+	Translation    string `json:"translation"`
+	// Project is the id of the project the translation comes from
+	Project int    `json:"project"`
+	Term    string `json:"term"`
+	Context string `json:"context,omitempty"`
+}
+
+// Match is an entry found by Lookup
+type Match struct {
+	Entry
+	// Score is the similarity of the sources, 1 for an exact match
+	Score float64 `json:"score"`
+}
+
+// Memory is a translation memory
+type Memory struct {
+	entries []Entry
+	// pairs indexes the entries by language pair
+	pairs map[string][]int
+	// exact indexes the entries by language pair and source
+	exact map[string][]int
+}
+
+// New returns an empty memory
+func New() *Memory {
+	return &Memory{pairs: make(map[string][]int), exact: make(map[string][]int)}
+}
+
+func pairKey(sourceLanguage, language string) string {
+	return strings.ToLower(sourceLanguage) + "\x00" + strings.ToLower(language)
+}
+
+// Entries returns the entries of the memory
+func (m *Memory) Entries() []Entry {
+	return m.entries
+}
+
+// Add adds entries to the memory. Entries with an empty source or
+// translation, and entries already remembered for the same source and
+// translation, are skipped.
+func (m *Memory) Add(entries ...Entry) {
+	for _, e := range entries {
+		if e.Source == "" || e.Translation == "" {
+			continue
+		}
+		pair := pairKey(e.SourceLanguage, e.Language)
+		key := pair + "\x00" + e.Source
+		duplicate := false
+		for _, i := range m.exact[key] {
+			if m.entries[i].Translation == e.Translation {
+				duplicate = true
+				break
+			}
+		}
+		if duplicate {
+			continue
+		}
+		m.pairs[pair] = append(m.pairs[pair], len(m.entries))
+		m.exact[key] = append(m.exact[key], len(m.entries))
+		m.entries = append(m.entries, e)
+	}
+}
+
+// AddTerms remembers the translations of a project in a language, paired
+// with the translations of the source language. Plurals are paired form by
+// form. Fuzzy translations are skipped, as they still need a review.
+func (m *Memory) AddTerms(project int, sourceLanguage string, source []poeditor.TermTranslated, language string, translations []poeditor.TermTranslated) {
+	sources := make(map[poeditor.TermBase]interface{}, len(source))
+	for _, t := range source {
+		sources[t.TermBase] = t.Translation.Content
+	}
+	for _, t := range translations {
+		if t.Translation.Fuzzy != 0 {
+			continue
+		}
+		for _, p := range pairs(sources[t.TermBase], t.Translation.Content) {
+			m.Add(Entry{
+				SourceLanguage: sourceLanguage,
+				Language:       language,
+				Source:         p[0],
+				Translation:    p[1],
+				Project:        project,
+				Term:           t.Term.Term,
+				Context:        t.Context,
+			})
+		}
+	}
+}
+
+// pairs returns the source and translation texts of a term, form by form
+// for plurals
+func pairs(source, translation interface{}) [][2]string {
+	switch s := source.(type) {
+	case string:
+		if t, ok := translation.(string); ok {
+			return [][2]string{{s, t}}
+		}
+	case poeditor.Plural:
+		if t, ok := translation.(poeditor.Plural); ok {
+			return [][2]string{
+				{s.Zero, t.Zero}, {s.One, t.One}, {s.Two, t.Two},
+				{s.Few, t.Few}, {s.Many, t.Many}, {s.Other, t.Other},
+			}
+		}
+	}
+	return nil
+}
+
+// AddProject remembers the translations of every language of the project,
+// paired with the reference language. Projects without reference language
+// are skipped.
+func (m *Memory) AddProject(p *poeditor.Project) error {
+	if p.ReferenceLanguage == "" {
+		view, err := p.POEditor.ViewProject(p.ID)
+		if err != nil {
+			return err
+		}
+		if view.ReferenceLanguage == "" {
+			return nil
+		}
+		p = view
+	}
+	src := poeditor.Language{Project: p, Code: p.ReferenceLanguage}
+	source, err := src.ListTerms()
+	if err != nil {
+		return err
+	}
+	languages, err := p.ListLanguages()
+	if err != nil {
+		return err
+	}
+	for _, l := range languages {
+		if l.Code == src.Code {
+			continue
+		}
+		l.Project = p
+		terms, err := l.ListTerms()
+		if err != nil {
+			return err
+		}
+		m.AddTerms(p.ID, src.Code, source, l.Code, terms)
+	}
+	return nil
+}
+
+// Build builds a memory from the translations of all projects accessible by
+// the API token
+func Build(poe *poeditor.POEditor) (*Memory, error) {
+	m := New()
+	projects, err := poe.ListProjects()
+	if err != nil {
+		return nil, err
+	}
+	for _, p := range projects {
+		if err := m.AddProject(p); err != nil {
+			return nil, err
+		}
+	}
+	return m, nil
+}
+
+// Save writes the memory as JSON
+func (m *Memory) Save(w io.Writer) error {
+	entries := m.entries
+	if entries == nil {
+		entries = []Entry{}
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(entries)
+}
+
+// Load reads a memory written by Save
+func Load(r io.Reader) (*Memory, error) {
+	var entries []Entry
+	if err := json.NewDecoder(r).Decode(&entries); err != nil {
+		return nil, err
+	}
+	m := New()
+	m.Add(entries...)
+	return m, nil
+}
+
+// Lookup returns the translations of text from the source language to the
+// language with a score of at least min, best first. Exact matches score 1.
+// Fuzzy matches are scored by the edit distance of the sources, ignoring
+// case and surrounding whitespace.
+func (m *Memory) Lookup(sourceLanguage, language, text string, min float64) []Match {
+	pair := pairKey(sourceLanguage, language)
+	var matches []Match
+	for _, i := range m.exact[pair+"\x00"+text] {
+		matches = append(matches, Match{Entry: m.entries[i], Score: 1})
+	}
+	if min < 1 {
+		norm := normalize(text)
+		n := utf8.RuneCountInString(norm)
+		for _, i := range m.pairs[pair] {
+			e := m.entries[i]
+			if e.Source == text {
+				continue
+			}
+			// Skip sources whose length alone rules out the minimum score
+			other := normalize(e.Source)
+			l := utf8.RuneCountInString(other)
+			if longest := maxInt(n, l); longest > 0 && float64(abs(n-l))/float64(longest) > 1-min {
+				continue
+			}
+			if score := Similarity(norm, other); score >= min {
+				matches = append(matches, Match{Entry: e, Score: score})
+			}
+		}
+	}
+	sort.SliceStable(matches, func(i, j int) bool { return matches[i].Score > matches[j].Score })
+	return matches
+}
+
+func normalize(s string) string {
+	return strings.ToLower(strings.TrimSpace(s))
+}
+
+func maxInt(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
+
+// Similarity returns 1 minus the Levenshtein distance of a and b divided by
+// the length of the longest, so equal strings score 1 and strings without
+// anything in common 0
+func Similarity(a, b string) float64 {
+	ra, rb := []rune(a), []rune(b)
+	longest := maxInt(len(ra), len(rb))
+	if longest == 0 {
+		return 1
+	}
+	return 1 - float64(levenshtein(ra, rb))/float64(longest)
+}
+
+// levenshtein returns the number of insertions, deletions and substitutions
+// turning a into b
+func levenshtein(a, b []rune) int {
+	prev := make([]int, len(b)+1)
+	cur := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = prev[j] + 1
+			if cur[j-1]+1 < cur[j] {
+				cur[j] = cur[j-1] + 1
+			}
+			if prev[j-1]+cost < cur[j] {
+				cur[j] = prev[j-1] + cost
+			}
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(b)]
+}
@@ -0,0 +1,112 @@
+package tm_test
+
+import (
+	"bytes"
+	"math"
+	"reflect"
+	"testing"
+
+	"github.com/blacksails/poeditor"
+	"github.com/blacksails/poeditor/internal/termtest"
+	"github.com/blacksails/poeditor/tm"
+)
+
+func memory() *tm.Memory {
+	m := tm.New()
+	discard := termtest.Translated("discard", "Änderungen verwerfen")
+	discard.Translation.Fuzzy = 1
+	m.AddTerms(1, "en", []poeditor.TermTranslated{
+		termtest.Translated("save", "Save changes"),
+		termtest.Translated("cancel", "Cancel"),
+		termtest.Translated("files", poeditor.Plural{One: "One file", Other: "%d files"}),
+		termtest.Translated("discard", "Discard changes"),
+	}, "de", []poeditor.TermTranslated{
+		termtest.Translated("save", "Änderungen speichern"),
+		termtest.Translated("cancel", ""),
+		termtest.Translated("files", poeditor.Plural{One: "Eine Datei", Other: "%d Dateien"}),
+		discard,
+	})
+	m.AddTerms(2, "en", []poeditor.TermTranslated{termtest.Translated("save", "Save changes")},
+		"de", []poeditor.TermTranslated{termtest.Translated("save", "Änderungen speichern")})
+	return m
+}
+
+func TestLookup(t *testing.T) {
+	m := memory()
+	if n := len(m.Entries()); n != 3 {
+		t.Errorf("Expected 3 entries, got %d", n)
+	}
+	lookupTests := []struct {
+		text     string
+		min      float64
+		expected []string
+	}{
+		{"Save changes", 1, []string{"Änderungen speichern"}},
+		{"Save change", 1, nil},
+		{"Save change", 0.9, []string{"Änderungen speichern"}},
+		{"%d files", 0.6, []string{"%d Dateien"}},
+		{"Cancel", 0.5, nil},
+	}
+	for _, lt := range lookupTests {
+		var translations []string
+		for _, match := range m.Lookup("en", "DE", lt.text, lt.min) {
+			translations = append(translations, match.Translation)
+		}
+		if !reflect.DeepEqual(translations, lt.expected) {
+			t.Errorf("%q: Expected %q, got %q", lt.text, lt.expected, translations)
+		}
+	}
+	matches := m.Lookup("en", "de", "save changes!", 0.5)
+	if len(matches) != 1 || matches[0].Score < 0.9 || matches[0].Score >= 1 || matches[0].Project != 1 {
+		t.Errorf("Expected a fuzzy match from project 1, got %+v", matches)
+	}
+}
+
+func TestSaveLoad(t *testing.T) {
+	m := memory()
+	var buf bytes.Buffer
+	if err := m.Save(&buf); err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	loaded, err := tm.Load(&buf)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if !reflect.DeepEqual(loaded.Entries(), m.Entries()) {
+		t.Errorf("Expected %+v, got %+v", m.Entries(), loaded.Entries())
+	}
+}
+
+func TestSuggest(t *testing.T) {
+	m := memory()
+	source := []poeditor.TermTranslated{termtest.Translated("save.button", "Save changes."), termtest.Translated("quit", "Quit")}
+	target := []poeditor.TermTranslated{termtest.Translated("save.button", ""), termtest.Translated("quit", ""), termtest.Translated("Save changes", "")}
+	suggestions := m.Suggest("en", source, "de", target, 0.8)
+	if len(suggestions) != 2 {
+		t.Fatalf("Expected 2 suggestions, got %+v", suggestions)
+	}
+	if s := suggestions[0]; s.Term != "save.button" || s.Source != "Save changes." || s.Match.Translation != "Änderungen speichern" || s.Match.Score >= 1 {
+		t.Errorf("Unexpected fuzzy suggestion %+v", s)
+	}
+	if s := suggestions[1]; s.Term != "Save changes" || s.Match.Score != 1 {
+		t.Errorf("Unexpected exact suggestion %+v", s)
+	}
+}
+
+func TestSimilarity(t *testing.T) {
+	similarityTests := []struct {
+		a, b     string
+		expected float64
+	}{
+		{"", "", 1},
+		{"abc", "abc", 1},
+		{"abc", "xyz", 0},
+		{"kitten", "sitting", 1 - 3.0/7},
+		{"äbc", "abc", 1 - 1.0/3},
+	}
+	for _, st := range similarityTests {
+		if s := tm.Similarity(st.a, st.b); math.Abs(s-st.expected) > 1e-9 {
+			t.Errorf("%q, %q: Expected %f, got %f", st.a, st.b, st.expected, s)
+		}
+	}
+}
@@ -0,0 +1,342 @@
+package poeditor
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// ErrFormatUnknown is returned when the format of a file cannot be detected
+var ErrFormatUnknown = errors.New("unable to detect file format")
+
+const (
+	// SeverityError is the severity of a diagnostic which blocks an upload
+	SeverityError = "error"
+	// SeverityWarning is the severity of a diagnostic which is only reported
+	SeverityWarning = "warning"
+)
+
+// Diagnostic is a problem found in a file. Line is 0 when the problem is not
+// tied to a particular line.
+type Diagnostic struct {
+	Line     int
+	Severity string
+	Message  string
+}
+
+func (d Diagnostic) String() string {
+	if d.Line == 0 {
+		return fmt.Sprintf("%s: %s", d.Severity, d.Message)
+	}
+	return fmt.Sprintf("line %d: %s: %s", d.Line, d.Severity, d.Message)
+}
+
+// DiagnosticsError is returned from Project.UploadFile when a file fails
+// validation
+type DiagnosticsError struct {
+	Name        string
+	Diagnostics []Diagnostic
+}
+
+func (e *DiagnosticsError) Error() string {
+	lines := make([]string, 0, len(e.Diagnostics)+1)
+	lines = append(lines, fmt.Sprintf("%s failed validation", e.Name))
+	for _, d := range e.Diagnostics {
+		lines = append(lines, fmt.Sprintf("%s:%s", e.Name, d))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// UploadFile detects the format of a file, validates it locally and only then
+// uploads it using Project.Upload. If validation finds any errors a
+// *DiagnosticsError listing every problem is returned and nothing is
+// uploaded. Plural forms are mapped by the language of the options when the
+// file does not state its language.
+func (p *Project) UploadFile(name string, r io.Reader, options UploadOptions) (UploadResult, error) {
+	content, err := ioutil.ReadAll(r)
+	if err != nil {
+		return UploadResult{}, err
+	}
+	format, err := DetectFormat(name, content)
+	if err != nil {
+		return UploadResult{}, err
+	}
+	diags := ValidateFileLanguage(content, format, options.Language.Code)
+	for _, d := range diags {
+		if d.Severity == SeverityError {
+			return UploadResult{}, &DiagnosticsError{Name: name, Diagnostics: diags}
+		}
+	}
+	// POEditor determines the format by the file extension
+	name = filepath.Base(name)
+	if FormatFromFilename(name) != format {
+		name += formatUploadExtensions[format]
+	}
+	return p.Upload(namedReader{Reader: bytes.NewReader(content), name: name}, options)
+}
+
+var formatUploadExtensions = map[string]string{
+	FileFormatPO:             ".po",
+	FileFormatKeyValueJSON:   ".json",
+	FileFormatJSON:           ".json",
+	FileFormatAndroidStrings: ".xml",
+	FileFormatAppleStrings:   ".strings",
+	FileFormatXLIFF:          ".xliff",
+	FileFormatARB:            ".arb",
+	FileFormatProperties:     ".properties",
+	FileFormatRESX:           ".resx",
+	FileFormatRESW:           ".resw",
+	FileFormatCSV:            ".csv",
+}
+
+var (
+	arbSniff        = regexp.MustCompile(`"@[^"]*"\s*:`)
+	poSniff         = regexp.MustCompile(`(?m)^\s*msgid\s+"`)
+	appleSniff      = regexp.MustCompile(`(?m)^\s*"(?:[^"\\]|\\.)*"\s*=\s*"`)
+	propertiesSniff = regexp.MustCompile(`(?m)^\s*[^\s#!=:]+\s*[=:]`)
+)
+
+// DetectFormat determines the format of a file from its name and content.
+// The content is used to tell formats sharing an extension apart, such as
+// the JSON formats, and when the extension is unknown.
+func DetectFormat(name string, content []byte) (string, error) {
+	byName := FormatFromFilename(name)
+	trimmed := bytes.TrimSpace(bytes.TrimPrefix(content, []byte("\xef\xbb\xbf")))
+	switch {
+	case bytes.HasPrefix(trimmed, []byte("[")):
+		return FileFormatJSON, nil
+	case bytes.HasPrefix(trimmed, []byte("{")):
+		if byName == FileFormatARB || arbSniff.Match(trimmed) {
+			return FileFormatARB, nil
+		}
+		return FileFormatKeyValueJSON, nil
+	case bytes.HasPrefix(trimmed, []byte("<")):
+		switch xmlRoot(trimmed) {
+		case "resources":
+			return FileFormatAndroidStrings, nil
+		case "xliff":
+			return FileFormatXLIFF, nil
+		case "root":
+			if byName == FileFormatRESW {
+				return FileFormatRESW, nil
+			}
+			return FileFormatRESX, nil
+		}
+	}
+	if byName != "" {
+		return byName, nil
+	}
+	switch {
+	case bytes.HasPrefix(content, []byte("\xff\xfe")) || bytes.HasPrefix(content, []byte("\xfe\xff")):
+		return FileFormatAppleStrings, nil
+	case poSniff.Match(content):
+		return FileFormatPO, nil
+	case appleSniff.Match(content):
+		return FileFormatAppleStrings, nil
+	case propertiesSniff.Match(content):
+		return FileFormatProperties, nil
+	}
+	return "", ErrFormatUnknown
+}
+
+// ValidateFile parses a file locally and reports encoding problems, syntax
+// errors, duplicate terms, malformed plurals and constructs which are not
+// imported by POEditor.
+func ValidateFile(content []byte, format string) []Diagnostic {
+	return ValidateFileLanguage(content, format, "")
+}
+
+// ValidateFileLanguage validates a file of a language like ValidateFile. The
+// language maps the plural forms of files which do not state their language,
+// such as a .po file without a Language header.
+func ValidateFileLanguage(content []byte, format, language string) []Diagnostic {
+	var diags []Diagnostic
+	add := func(line int, severity, format string, args ...interface{}) {
+		diags = append(diags, Diagnostic{Line: line, Severity: severity, Message: fmt.Sprintf(format, args...)})
+	}
+	utf16 := bytes.HasPrefix(content, []byte("\xff\xfe")) || bytes.HasPrefix(content, []byte("\xfe\xff"))
+	if !utf16 {
+		if i := invalidUTF8(content); i >= 0 {
+			if format == FileFormatProperties {
+				add(lineAt(content, i), SeverityWarning, "file is not valid UTF-8 and is read as ISO-8859-1")
+			} else {
+				add(lineAt(content, i), SeverityError, "invalid UTF-8 encoding")
+				return diags
+			}
+		}
+		if i := bytes.IndexByte(content, 0); i >= 0 {
+			add(lineAt(content, i), SeverityError, "file contains a NUL byte")
+			return diags
+		}
+	} else if format != FileFormatAppleStrings {
+		add(0, SeverityError, "UTF-16 is only supported for Apple strings files")
+		return diags
+	}
+	terms, language, err := decode(bytes.NewReader(content), format, language)
+	if err != nil {
+		add(errorLine(content, err), SeverityError, "%s", err)
+		return diags
+	}
+	// Formats like .po only hold the forms the language uses for whole
+	// numbers, which need not include other
+	needsOther := true
+	if c := codecs[format]; c.categories != nil {
+		needsOther = containsString(c.categories(language), "other")
+	}
+	for _, u := range unsupportedConstructs[format] {
+		for _, loc := range u.pattern.FindAllIndex(content, -1) {
+			add(lineAt(content, loc[0]), SeverityWarning, "%s", u.message)
+		}
+	}
+	seen := make(map[TermBase]int)
+	searchFrom := make(map[string]int)
+	for _, t := range terms {
+		needle := keyNeedle(format, t.Term.Term)
+		line := 0
+		if i := bytes.Index(content[searchFrom[needle]:], []byte(needle)); needle != "" && i >= 0 {
+			line = lineAt(content, searchFrom[needle]+i)
+			searchFrom[needle] += i + len(needle)
+		}
+		if t.Term.Term == "" {
+			add(line, SeverityError, "empty term")
+			continue
+		}
+		if first, ok := seen[t.TermBase]; ok {
+			if t.Context != "" {
+				add(line, SeverityError, "duplicate term %q with context %q, first defined on line %d", t.Term.Term, t.Context, first)
+			} else {
+				add(line, SeverityError, "duplicate term %q, first defined on line %d", t.Term.Term, first)
+			}
+		} else {
+			seen[t.TermBase] = line
+		}
+		switch c := t.Translation.Content.(type) {
+		case Plural:
+			if needsOther && c.Other == "" && c != (Plural{}) {
+				add(line, SeverityError, "plural %q is missing the other form", t.Term.Term)
+			}
+		case string:
+			if strings.Contains(c, ", plural,") && strings.HasPrefix(strings.TrimSpace(c), "{") {
+				if exactICUPlural(c) {
+					add(line, SeverityWarning, "%q uses exact value selectors or an offset, which are kept as text", t.Term.Term)
+				} else if _, _, ok := parseICUPlural(c); !ok {
+					add(line, SeverityWarning, "%q looks like a malformed ICU plural", t.Term.Term)
+				}
+			}
+		}
+	}
+	return diags
+}
+
+type unsupportedConstruct struct {
+	pattern *regexp.Regexp
+	message string
+}
+
+var unsupportedConstructs = map[string][]unsupportedConstruct{
+	FileFormatAndroidStrings: {
+		{regexp.MustCompile(`<string-array\b`), "string arrays are not supported and will be skipped"},
+	},
+	FileFormatRESX: {
+		{regexp.MustCompile(`<data\b[^>]*\b(?:type|mimetype)=`), "non string resources will be skipped"},
+	},
+	FileFormatRESW: {
+		{regexp.MustCompile(`<data\b[^>]*\b(?:type|mimetype)=`), "non string resources will be skipped"},
+	},
+	FileFormatPO: {
+		{regexp.MustCompile(`(?m)^#~`), "obsolete entries will be skipped"},
+	},
+	FileFormatXLIFF: {
+		{regexp.MustCompile(`restype="x-gettext-plurals"`), "XLIFF plural groups are imported as separate terms"},
+	},
+}
+
+// keyNeedle returns the text identifying a term's key in a file of the given
+// format, used to find the line a term is defined on
+func keyNeedle(format, key string) string {
+	switch format {
+	case FileFormatPO:
+		return "msgid " + quotePO(key)
+	case FileFormatKeyValueJSON, FileFormatJSON, FileFormatARB:
+		b, _ := json.Marshal(key)
+		return string(b)
+	case FileFormatAndroidStrings, FileFormatRESX, FileFormatRESW:
+		return `name="` + xmlEscape(key) + `"`
+	case FileFormatXLIFF:
+		return `resname="` + xmlEscape(key) + `"`
+	case FileFormatAppleStrings:
+		return quoteApple(key)
+	case FileFormatProperties:
+		return escapeProperty(key, true, false)
+	case FileFormatCSV:
+		return key
+	}
+	return ""
+}
+
+var errorLinePattern = regexp.MustCompile(`line (\d+)`)
+
+// errorLine finds the line of a decoding error
+func errorLine(content []byte, err error) int {
+	var (
+		syntaxErr *json.SyntaxError
+		typeErr   *json.UnmarshalTypeError
+		xmlErr    *xml.SyntaxError
+	)
+	switch {
+	case errors.As(err, &syntaxErr):
+		return lineAt(content, int(syntaxErr.Offset))
+	case errors.As(err, &typeErr):
+		return lineAt(content, int(typeErr.Offset))
+	case errors.As(err, &xmlErr):
+		return xmlErr.Line
+	}
+	if m := errorLinePattern.FindStringSubmatch(err.Error()); m != nil {
+		n, _ := strconv.Atoi(m[1])
+		return n
+	}
+	return 0
+}
+
+// lineAt returns the line number of a byte offset
+func lineAt(content []byte, offset int) int {
+	if offset > len(content) {
+		offset = len(content)
+	}
+	return bytes.Count(content[:offset], []byte("\n")) + 1
+}
+
+// invalidUTF8 returns the offset of the first invalid UTF-8 sequence or -1
+func invalidUTF8(b []byte) int {
+	for i := 0; i < len(b); {
+		r, size := utf8.DecodeRune(b[i:])
+		if r == utf8.RuneError && size == 1 {
+			return i
+		}
+		i += size
+	}
+	return -1
+}
+
+// xmlRoot returns the name of the root element of an XML document
+func xmlRoot(content []byte) string {
+	dec := xml.NewDecoder(bytes.NewReader(content))
+	for {
+		tok, err := dec.Token()
+		if err != nil {
+			return ""
+		}
+		if se, ok := tok.(xml.StartElement); ok {
+			return se.Name.Local
+		}
+	}
+}
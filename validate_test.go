@@ -0,0 +1,114 @@
+package poeditor_test
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/blacksails/poeditor"
+)
+
+func TestDetectFormat(t *testing.T) {
+	detectTests := []struct {
+		name     string
+		content  string
+		expected string
+	}{
+		{"en.json", `{"hello": "Hello"}`, poeditor.FileFormatKeyValueJSON},
+		{"en.json", `{"hello": "Hello", "@hello": {"description": "Greeting"}}`, poeditor.FileFormatARB},
+		{"en.json", `[{"term": "hello", "definition": "Hello"}]`, poeditor.FileFormatJSON},
+		{"strings.xml", `<resources><string name="hello">Hello</string></resources>`, poeditor.FileFormatAndroidStrings},
+		{"Resources.resw", `<?xml version="1.0"?><root></root>`, poeditor.FileFormatRESW},
+		{"messages.txt", "msgid \"hello\"\nmsgstr \"Hello\"\n", poeditor.FileFormatPO},
+		{"messages.txt", "\"hello\" = \"Hello\";\n", poeditor.FileFormatAppleStrings},
+		{"messages.txt", "# comment\nhello = Hello\n", poeditor.FileFormatProperties},
+	}
+	for _, dt := range detectTests {
+		format, err := poeditor.DetectFormat(dt.name, []byte(dt.content))
+		if err != nil {
+			t.Errorf("%s: Unexpected error: %s", dt.name, err)
+		}
+		if format != dt.expected {
+			t.Errorf("%s: Expected %s, got %s", dt.name, dt.expected, format)
+		}
+	}
+}
+
+func TestValidateFile(t *testing.T) {
+	validateTests := []struct {
+		format   string
+		content  string
+		expected []poeditor.Diagnostic
+	}{
+		{
+			poeditor.FileFormatPO,
+			"msgid \"hello\"\nmsgstr \"Hej\"\n\nmsgid \"bye\"\nmsgstr \"Farvel\"\n\nmsgid \"hello\"\nmsgstr \"Hejsa\"\n",
+			[]poeditor.Diagnostic{
+				{Line: 7, Severity: poeditor.SeverityError, Message: `duplicate term "hello", first defined on line 1`},
+			},
+		},
+		{
+			poeditor.FileFormatKeyValueJSON,
+			"{\n  \"hello\": \"Hej\",\n  \"bye\" \"Farvel\"\n}\n",
+			[]poeditor.Diagnostic{
+				{Line: 3, Severity: poeditor.SeverityError, Message: "invalid character '\"' after object key"},
+			},
+		},
+		{
+			poeditor.FileFormatAndroidStrings,
+			"<resources>\n  <string name=\"a\">A</string>\n  <string-array name=\"b\"></string-array>\n</resources>\n",
+			[]poeditor.Diagnostic{
+				{Line: 3, Severity: poeditor.SeverityWarning, Message: "string arrays are not supported and will be skipped"},
+			},
+		},
+		{
+			poeditor.FileFormatPO,
+			"msgid \"hello\"\nmsgstr \"H\xe9j\"\n",
+			[]poeditor.Diagnostic{
+				{Line: 2, Severity: poeditor.SeverityError, Message: "invalid UTF-8 encoding"},
+			},
+		},
+	}
+	for _, vt := range validateTests {
+		diags := poeditor.ValidateFile([]byte(vt.content), vt.format)
+		if !reflect.DeepEqual(diags, vt.expected) {
+			t.Errorf("%s: \nExpected %+v \nGot      %+v", vt.format, vt.expected, diags)
+		}
+	}
+}
+
+func TestProjectUploadFileLanguage(t *testing.T) {
+	// Three plural forms cannot be mapped without knowing the language
+	content := "msgid \"\"\nmsgstr \"\"\n\"Plural-Forms: nplurals=3;\\n\"\n\n" +
+		"msgid \"file\"\nmsgid_plural \"files\"\nmsgstr[0] \"%d файл\"\nmsgstr[1] \"%d файла\"\nmsgstr[2] \"%d файлов\"\n"
+	if diags := poeditor.ValidateFileLanguage([]byte(content), poeditor.FileFormatPO, "ru"); len(diags) != 0 {
+		t.Errorf("Expected no diagnostics, got %+v", diags)
+	}
+	if diags := poeditor.ValidateFile([]byte(content), poeditor.FileFormatPO); len(diags) != 1 {
+		t.Errorf("Expected an unmapped plural forms diagnostic, got %+v", diags)
+	}
+	var uploads []string
+	poe := newTestPOEditor(t, func(endpoint string, form map[string]string) (interface{}, string) {
+		if endpoint != "/projects/upload" {
+			t.Errorf("Unexpected request to %s", endpoint)
+			return nil, "unexpected request"
+		}
+		uploads = append(uploads, form["language"])
+		return poeditor.UploadResult{}, ""
+	})
+	p := poe.Project(1)
+	options := poeditor.UploadOptions{Updating: poeditor.UploadTranslations, Language: poeditor.Language{Code: "ru"}}
+	if _, err := p.UploadFile("ru.po", strings.NewReader(content), options); err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	options.Language.Code = "de"
+	_, err := p.UploadFile("de.po", strings.NewReader(content), options)
+	var diagErr *poeditor.DiagnosticsError
+	if !errors.As(err, &diagErr) {
+		t.Fatalf("Expected a *DiagnosticsError, got %v", err)
+	}
+	if !reflect.DeepEqual(uploads, []string{"ru"}) {
+		t.Errorf("Expected only the Russian file to be uploaded, got %v", uploads)
+	}
+}
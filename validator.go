@@ -0,0 +1,280 @@
+package poeditor
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+// Term operations passed to term validators
+const (
+	// OperationAdd is Project.AddTerms
+	OperationAdd = "add"
+	// OperationUpdate is Project.UpdateTerms
+	OperationUpdate = "update"
+	// OperationSync is Project.Sync
+	OperationSync = "sync"
+)
+
+// TermValidator checks a term before the operation sends it to POEditor, and
+// returns a message for each problem. Updated terms are passed with their
+// new text and context.
+type TermValidator func(operation string, t Term) []string
+
+// TranslationValidator checks a translation before Language.Update sends it
+// to POEditor, and returns a message for each problem
+type TranslationValidator func(language string, t TermTranslation) []string
+
+// Violation is a problem found by a validator
+type Violation struct {
+	TermBase
+	// Language is set for problems of translations
+	Language string
+	Message  string
+}
+
+func (v Violation) String() string {
+	name := v.Term
+	if v.Context != "" {
+		name += " (" + v.Context + ")"
+	}
+	if v.Language != "" {
+		name += " [" + v.Language + "]"
+	}
+	return name + ": " + v.Message
+}
+
+// ValidationError is returned when validators reject terms or translations.
+// Nothing is sent to POEditor when any validator fails.
+type ValidationError struct {
+	Violations []Violation
+}
+
+func (e *ValidationError) Error() string {
+	lines := make([]string, 0, len(e.Violations)+1)
+	lines = append(lines, fmt.Sprintf("validation failed with %d violations", len(e.Violations)))
+	for _, v := range e.Violations {
+		lines = append(lines, v.String())
+	}
+	return strings.Join(lines, "\n")
+}
+
+// AddTermValidator registers validators run by Project.AddTerms,
+// Project.UpdateTerms and Project.Sync. Validators are kept by projects
+// returned from Project.Update. Register validators before using the project
+// from several goroutines.
+func (p *Project) AddTermValidator(v ...TermValidator) {
+	if p.validators == nil {
+		p.validators = &validators{}
+	}
+	p.validators.terms = append(p.validators.terms, v...)
+}
+
+// AddTranslationValidator registers validators run by Language.Update for
+// the languages of the project
+func (p *Project) AddTranslationValidator(v ...TranslationValidator) {
+	if p.validators == nil {
+		p.validators = &validators{}
+	}
+	p.validators.translations = append(p.validators.translations, v...)
+}
+
+// validators are the validators registered on a project. They are kept
+// behind a pointer so that projects stay comparable.
+type validators struct {
+	terms        []TermValidator
+	translations []TranslationValidator
+}
+
+// copy returns a copy of the validators which can be added to without
+// changing v
+func (v *validators) copy() *validators {
+	if v == nil {
+		return nil
+	}
+	return &validators{
+		terms:        append([]TermValidator(nil), v.terms...),
+		translations: append([]TranslationValidator(nil), v.translations...),
+	}
+}
+
+// validateTerms runs the term validators, and returns a *ValidationError
+// holding every violation
+func (p *Project) validateTerms(operation string, terms []Term) error {
+	if p.validators == nil || len(p.validators.terms) == 0 {
+		return nil
+	}
+	var violations []Violation
+	for _, t := range terms {
+		for _, v := range p.validators.terms {
+			for _, msg := range v(operation, t) {
+				violations = append(violations, Violation{TermBase: t.TermBase, Message: msg})
+			}
+		}
+	}
+	if violations != nil {
+		return &ValidationError{Violations: violations}
+	}
+	return nil
+}
+
+// validateUpdates validates the terms of an update as they will be after it
+func (p *Project) validateUpdates(terms []TermUpdate) error {
+	if p.validators == nil || len(p.validators.terms) == 0 {
+		return nil
+	}
+	updated := make([]Term, len(terms))
+	for i, t := range terms {
+		updated[i] = t.Term
+		if t.NewTerm != "" {
+			updated[i].Term = t.NewTerm
+		}
+		if t.NewContext != "" {
+			updated[i].Context = t.NewContext
+		}
+	}
+	return p.validateTerms(OperationUpdate, updated)
+}
+
+// validateTranslations runs the translation validators of the project of
+// the language
+func (l *Language) validateTranslations(terms []TermTranslation) error {
+	if l.Project == nil || l.Project.validators == nil || len(l.Project.validators.translations) == 0 {
+		return nil
+	}
+	var violations []Violation
+	for _, t := range terms {
+		for _, v := range l.Project.validators.translations {
+			for _, msg := range v(l.Code, t) {
+				violations = append(violations, Violation{TermBase: t.TermBase, Language: l.Code, Message: msg})
+			}
+		}
+	}
+	if violations != nil {
+		return &ValidationError{Violations: violations}
+	}
+	return nil
+}
+
+// TermPattern returns a validator requiring terms to match a pattern, e.g.
+// ^[a-z][a-z0-9_.]*$ for keys in snake case
+func TermPattern(pattern *regexp.Regexp) TermValidator {
+	return func(operation string, t Term) []string {
+		if pattern.MatchString(t.Term) {
+			return nil
+		}
+		return []string{fmt.Sprintf("term does not match %s", pattern)}
+	}
+}
+
+// MaxTermLength returns a validator limiting the number of characters of
+// terms
+func MaxTermLength(n int) TermValidator {
+	return func(operation string, t Term) []string {
+		if l := utf8.RuneCountInString(t.Term); l > n {
+			return []string{fmt.Sprintf("term has %d characters, at most %d are allowed", l, n)}
+		}
+		return nil
+	}
+}
+
+// ForbidTermCharacters returns a validator rejecting terms and contexts
+// containing any of the characters
+func ForbidTermCharacters(chars string) TermValidator {
+	return func(operation string, t Term) []string {
+		var msgs []string
+		if i := strings.IndexAny(t.Term, chars); i >= 0 {
+			r, _ := utf8.DecodeRuneInString(t.Term[i:])
+			msgs = append(msgs, fmt.Sprintf("term contains forbidden character %q", r))
+		}
+		if i := strings.IndexAny(t.Context, chars); i >= 0 {
+			r, _ := utf8.DecodeRuneInString(t.Context[i:])
+			msgs = append(msgs, fmt.Sprintf("context contains forbidden character %q", r))
+		}
+		return msgs
+	}
+}
+
+// RequireComment returns a validator requiring new terms to have a comment.
+// It applies to Project.AddTerms only, since updates and syncs may leave the
+// comments of existing terms unchanged.
+func RequireComment() TermValidator {
+	return func(operation string, t Term) []string {
+		if operation == OperationAdd && strings.TrimSpace(t.Comment) == "" {
+			return []string{"new terms require a comment"}
+		}
+		return nil
+	}
+}
+
+// RequireTags returns a validator requiring terms to have at least one of
+// the tags, or any tag when none are given. Project.UpdateTerms leaves the
+// tags of terms unchanged unless new ones are given, so updates are only
+// checked when they set tags.
+func RequireTags(tags ...string) TermValidator {
+	return func(operation string, t Term) []string {
+		if operation == OperationUpdate && len(t.Tags) == 0 {
+			return nil
+		}
+		if len(tags) == 0 {
+			if len(t.Tags) == 0 {
+				return []string{"term requires a tag"}
+			}
+			return nil
+		}
+		if hasTag(t.Tags, tags) {
+			return nil
+		}
+		return []string{fmt.Sprintf("term requires one of the tags %s", strings.Join(tags, ", "))}
+	}
+}
+
+// MaxTranslationLength returns a validator limiting the number of characters
+// of translations, and of each form of plural translations
+func MaxTranslationLength(n int) TranslationValidator {
+	return func(language string, t TermTranslation) []string {
+		var msgs []string
+		for _, s := range contentForms(t.Translation.Content) {
+			if l := utf8.RuneCountInString(s); l > n {
+				msgs = append(msgs, fmt.Sprintf("translation has %d characters, at most %d are allowed", l, n))
+			}
+		}
+		return msgs
+	}
+}
+
+// ForbidTranslationCharacters returns a validator rejecting translations
+// containing any of the characters
+func ForbidTranslationCharacters(chars string) TranslationValidator {
+	return func(language string, t TermTranslation) []string {
+		var msgs []string
+		for _, s := range contentForms(t.Translation.Content) {
+			if i := strings.IndexAny(s, chars); i >= 0 {
+				r, _ := utf8.DecodeRuneInString(s[i:])
+				msgs = append(msgs, fmt.Sprintf("translation contains forbidden character %q", r))
+			}
+		}
+		return msgs
+	}
+}
+
+// contentForms returns the non-empty strings of a translation content
+func contentForms(c interface{}) []string {
+	var forms []string
+	switch c := c.(type) {
+	case string:
+		forms = []string{c}
+	case Plural:
+		for _, f := range pluralForms(c) {
+			forms = append(forms, pluralValues(c)[f])
+		}
+	}
+	var set []string
+	for _, s := range forms {
+		if s != "" {
+			set = append(set, s)
+		}
+	}
+	return set
+}
@@ -0,0 +1,142 @@
+package poeditor_test
+
+import (
+	"errors"
+	"reflect"
+	"regexp"
+	"testing"
+
+	"github.com/blacksails/poeditor"
+)
+
+func TestTermValidators(t *testing.T) {
+	validatorTests := []struct {
+		name      string
+		validator poeditor.TermValidator
+		operation string
+		term      poeditor.Term
+		expected  []string
+	}{
+		{"pattern", poeditor.TermPattern(regexp.MustCompile(`^[a-z_.]+$`)), poeditor.OperationAdd,
+			poeditor.Term{TermBase: poeditor.TermBase{Term: "home.title"}}, nil},
+		{"pattern", poeditor.TermPattern(regexp.MustCompile(`^[a-z_.]+$`)), poeditor.OperationAdd,
+			poeditor.Term{TermBase: poeditor.TermBase{Term: "Home Title"}}, []string{"term does not match ^[a-z_.]+$"}},
+		{"max length", poeditor.MaxTermLength(5), poeditor.OperationSync,
+			poeditor.Term{TermBase: poeditor.TermBase{Term: "héllo"}}, nil},
+		{"max length", poeditor.MaxTermLength(5), poeditor.OperationSync,
+			poeditor.Term{TermBase: poeditor.TermBase{Term: "héllo!"}}, []string{"term has 6 characters, at most 5 are allowed"}},
+		{"forbidden", poeditor.ForbidTermCharacters("/ "), poeditor.OperationUpdate,
+			poeditor.Term{TermBase: poeditor.TermBase{Term: "a/b", Context: "c d"}},
+			[]string{`term contains forbidden character '/'`, `context contains forbidden character ' '`}},
+		{"comment", poeditor.RequireComment(), poeditor.OperationAdd,
+			poeditor.Term{TermBase: poeditor.TermBase{Term: "a"}}, []string{"new terms require a comment"}},
+		{"comment", poeditor.RequireComment(), poeditor.OperationSync,
+			poeditor.Term{TermBase: poeditor.TermBase{Term: "a"}}, nil},
+		{"any tag", poeditor.RequireTags(), poeditor.OperationAdd,
+			poeditor.Term{TermBase: poeditor.TermBase{Term: "a"}}, []string{"term requires a tag"}},
+		{"tags", poeditor.RequireTags("web", "app"), poeditor.OperationAdd,
+			poeditor.Term{TermBase: poeditor.TermBase{Term: "a"}, Tags: []string{"app"}}, nil},
+		{"tags", poeditor.RequireTags("web", "app"), poeditor.OperationAdd,
+			poeditor.Term{TermBase: poeditor.TermBase{Term: "a"}, Tags: []string{"new"}}, []string{"term requires one of the tags web, app"}},
+		{"tags", poeditor.RequireTags("web", "app"), poeditor.OperationUpdate,
+			poeditor.Term{TermBase: poeditor.TermBase{Term: "a"}}, nil},
+		{"tags", poeditor.RequireTags("web", "app"), poeditor.OperationUpdate,
+			poeditor.Term{TermBase: poeditor.TermBase{Term: "a"}, Tags: []string{"new"}}, []string{"term requires one of the tags web, app"}},
+		{"any tag", poeditor.RequireTags(), poeditor.OperationSync,
+			poeditor.Term{TermBase: poeditor.TermBase{Term: "a"}}, []string{"term requires a tag"}},
+	}
+	for _, vt := range validatorTests {
+		msgs := vt.validator(vt.operation, vt.term)
+		if !reflect.DeepEqual(msgs, vt.expected) {
+			t.Errorf("%s: Expected %q, got %q", vt.name, vt.expected, msgs)
+		}
+	}
+}
+
+func TestTranslationValidators(t *testing.T) {
+	tr := poeditor.TermTranslation{
+		TermBase:    poeditor.TermBase{Term: "files"},
+		Translation: poeditor.Translation{Content: poeditor.Plural{One: "eine Datei", Other: "%d Dateien|"}},
+	}
+	msgs := poeditor.MaxTranslationLength(10)("de", tr)
+	expected := []string{"translation has 11 characters, at most 10 are allowed"}
+	if !reflect.DeepEqual(msgs, expected) {
+		t.Errorf("Expected %q, got %q", expected, msgs)
+	}
+	msgs = poeditor.ForbidTranslationCharacters("|")("de", tr)
+	expected = []string{"translation contains forbidden character '|'"}
+	if !reflect.DeepEqual(msgs, expected) {
+		t.Errorf("Expected %q, got %q", expected, msgs)
+	}
+}
+
+func TestProjectValidators(t *testing.T) {
+	// The project has no client, so any request reaching POEditor would
+	// panic
+	p := &poeditor.Project{}
+	p.AddTermValidator(poeditor.MaxTermLength(3), poeditor.RequireComment())
+	p.AddTranslationValidator(poeditor.MaxTranslationLength(3))
+
+	_, err := p.AddTerms([]poeditor.Term{
+		{TermBase: poeditor.TermBase{Term: "abcd"}},
+		{TermBase: poeditor.TermBase{Term: "abc", Context: "x"}, Comment: "ok"},
+	})
+	var verr *poeditor.ValidationError
+	if !errors.As(err, &verr) {
+		t.Fatalf("Expected a ValidationError, got %v", err)
+	}
+	expected := "validation failed with 2 violations\n" +
+		"abcd: term has 4 characters, at most 3 are allowed\n" +
+		"abcd: new terms require a comment"
+	if err.Error() != expected {
+		t.Errorf("Expected %q, got %q", expected, err.Error())
+	}
+
+	_, err = p.UpdateTerms([]poeditor.TermUpdate{
+		{Term: poeditor.Term{TermBase: poeditor.TermBase{Term: "abc"}}, NewTerm: "abcde"},
+	}, false)
+	if !errors.As(err, &verr) || len(verr.Violations) != 1 || verr.Violations[0].Term != "abcde" {
+		t.Errorf("Expected the updated term to be rejected, got %v", err)
+	}
+
+	_, err = p.Sync([]poeditor.Term{{TermBase: poeditor.TermBase{Term: "abcd"}}})
+	if !errors.As(err, &verr) || len(verr.Violations) != 1 {
+		t.Errorf("Expected the synced term to be rejected, got %v", err)
+	}
+
+	l := poeditor.Language{Project: p, Code: "de"}
+	_, err = l.Update([]poeditor.TermTranslation{
+		{TermBase: poeditor.TermBase{Term: "abc"}, Translation: poeditor.Translation{Content: "abcd"}},
+	})
+	expected = "validation failed with 1 violations\nabc [de]: translation has 4 characters, at most 3 are allowed"
+	if err == nil || err.Error() != expected {
+		t.Errorf("Expected %q, got %v", expected, err)
+	}
+
+	if !reflect.TypeOf(poeditor.Project{}).Comparable() {
+		t.Error("Expected projects with validators to be comparable")
+	}
+}
+
+func TestProjectUpdateKeepsValidators(t *testing.T) {
+	poe := newTestPOEditor(t, func(endpoint string, form map[string]string) (interface{}, string) {
+		return map[string]interface{}{"project": map[string]interface{}{"id": 1, "name": form["name"], "created": ""}}, ""
+	})
+	p := &poeditor.Project{POEditor: poe, ID: 1}
+	p.AddTermValidator(poeditor.MaxTermLength(3))
+	updated, err := p.Update(map[string]string{"name": "renamed"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	updated.AddTermValidator(poeditor.RequireComment())
+
+	var verr *poeditor.ValidationError
+	_, err = updated.AddTerms([]poeditor.Term{{TermBase: poeditor.TermBase{Term: "abcd"}}})
+	if !errors.As(err, &verr) || len(verr.Violations) != 2 {
+		t.Errorf("Expected both validators to reject the term, got %v", err)
+	}
+	_, err = p.AddTerms([]poeditor.Term{{TermBase: poeditor.TermBase{Term: "abcd"}}})
+	if !errors.As(err, &verr) || len(verr.Violations) != 1 {
+		t.Errorf("Expected validators added to the updated project to leave the original unchanged, got %v", err)
+	}
+}
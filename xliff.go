@@ -0,0 +1,149 @@
+package poeditor
+
+import (
+	"bufio"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// The context type used to store term contexts in XLIFF context groups
+const xliffContextType = "x-poeditor-context"
+
+// ReadXLIFF decodes an XLIFF 1.2 file. The resname, or id, of a trans-unit is
+// the term and its target the translation. Notes become the term comment and
+// targets in a needs-review state are marked fuzzy. XLIFF plurals are not
+// supported.
+func ReadXLIFF(r io.Reader) ([]TermTranslated, error) {
+	var doc xliffDocument
+	if err := xml.NewDecoder(r).Decode(&doc); err != nil {
+		return nil, err
+	}
+	var terms []TermTranslated
+	for _, f := range doc.Files {
+		for _, u := range f.units() {
+			t := TermTranslated{}
+			t.Term.Term = u.Resname
+			if t.Term.Term == "" {
+				t.Term.Term = u.ID
+			}
+			var notes []string
+			for _, n := range u.Notes {
+				notes = append(notes, strings.TrimSpace(n))
+			}
+			t.Comment = strings.Join(notes, "\n")
+			for _, g := range u.ContextGroups {
+				for _, c := range g.Contexts {
+					if c.Type == xliffContextType {
+						t.Context = c.Value
+					}
+				}
+			}
+			t.Translation.Content = decodeXMLText(u.Target.Value)
+			if strings.HasPrefix(u.Target.State, "needs-review") {
+				t.Translation.Fuzzy = 1
+			}
+			if u.Approved == "yes" {
+				t.Translation.Proofread = 1
+			}
+			terms = append(terms, t)
+		}
+	}
+	return terms, nil
+}
+
+// WriteXLIFF encodes terms as an XLIFF 1.2 file. The term is used as both the
+// resname and the source text. Only the other form of plurals is written.
+func WriteXLIFF(w io.Writer, terms []TermTranslated) error {
+	bw := bufio.NewWriter(w)
+	bw.WriteString(xml.Header)
+	bw.WriteString(`<xliff version="1.2" xmlns="urn:oasis:names:tc:xliff:document:1.2">` + "\n")
+	bw.WriteString(`  <file original="poeditor" datatype="plaintext" source-language="en">` + "\n")
+	bw.WriteString("    <body>\n")
+	for i, t := range terms {
+		var value string
+		switch c := t.Translation.Content.(type) {
+		case Plural:
+			value = c.Other
+		case string:
+			value = c
+		case nil:
+		default:
+			return ErrTranslationInvalid
+		}
+		attrs := ""
+		if t.Translation.Proofread == 1 {
+			attrs = ` approved="yes"`
+		}
+		fmt.Fprintf(bw, "      <trans-unit id=\"%d\" resname=\"%s\"%s>\n", i+1, xmlEscape(t.Term.Term), attrs)
+		fmt.Fprintf(bw, "        <source>%s</source>\n", encodeXMLText(t.Term.Term))
+		state := "translated"
+		if t.Translation.Fuzzy == 1 {
+			state = "needs-review-translation"
+		} else if value == "" {
+			state = "needs-translation"
+		}
+		fmt.Fprintf(bw, "        <target state=\"%s\">%s</target>\n", state, encodeXMLText(value))
+		if t.Comment != "" {
+			fmt.Fprintf(bw, "        <note>%s</note>\n", xmlEscape(t.Comment))
+		}
+		if t.Context != "" {
+			fmt.Fprintf(bw, "        <context-group purpose=\"information\"><context context-type=\"%s\">%s</context></context-group>\n",
+				xliffContextType, xmlEscape(t.Context))
+		}
+		bw.WriteString("      </trans-unit>\n")
+	}
+	bw.WriteString("    </body>\n  </file>\n</xliff>\n")
+	return bw.Flush()
+}
+
+type xliffDocument struct {
+	Files []xliffFile `xml:"file"`
+}
+
+type xliffFile struct {
+	Body struct {
+		Units  []xliffUnit  `xml:"trans-unit"`
+		Groups []xliffGroup `xml:"group"`
+	} `xml:"body"`
+}
+
+// units returns all trans-units of the file including those nested in groups
+func (f xliffFile) units() []xliffUnit {
+	units := f.Body.Units
+	for _, g := range f.Body.Groups {
+		units = append(units, g.units()...)
+	}
+	return units
+}
+
+type xliffGroup struct {
+	Units  []xliffUnit  `xml:"trans-unit"`
+	Groups []xliffGroup `xml:"group"`
+}
+
+func (g xliffGroup) units() []xliffUnit {
+	units := g.Units
+	for _, sub := range g.Groups {
+		units = append(units, sub.units()...)
+	}
+	return units
+}
+
+type xliffUnit struct {
+	ID       string `xml:"id,attr"`
+	Resname  string `xml:"resname,attr"`
+	Approved string `xml:"approved,attr"`
+	Target   struct {
+		State string `xml:"state,attr"`
+		Value string `xml:",innerxml"`
+	} `xml:"target"`
+	Notes         []string `xml:"note"`
+	ContextGroups []struct {
+		Contexts []struct {
+			Type  string `xml:"context-type,attr"`
+			Value string `xml:",chardata"`
+		} `xml:"context"`
+	} `xml:"context-group"`
+}
@@ -0,0 +1,257 @@
+package poeditor
+
+import (
+	"archive/zip"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"strconv"
+	"strings"
+)
+
+// ErrXLSXNoSheet is returned when an .xlsx file contains no worksheets
+var ErrXLSXNoSheet = errors.New("xlsx: workbook contains no worksheets")
+
+// The size limits of a worksheet, as in Excel
+const (
+	xlsxMaxRows    = 1048576
+	xlsxMaxColumns = 16384
+)
+
+// writeXLSX writes a workbook with a single worksheet containing rows. Cells
+// are written as inline strings, so no shared string table is needed.
+func writeXLSX(w io.Writer, sheetName string, rows [][]string) error {
+	z := zip.NewWriter(w)
+	files := []struct{ name, body string }{
+		{"[Content_Types].xml", xml.Header + `<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">` +
+			`<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>` +
+			`<Default Extension="xml" ContentType="application/xml"/>` +
+			`<Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/>` +
+			`<Override PartName="/xl/worksheets/sheet1.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/>` +
+			`</Types>`},
+		{"_rels/.rels", xml.Header + `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+			`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/>` +
+			`</Relationships>`},
+		{"xl/workbook.xml", xml.Header + `<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">` +
+			`<sheets><sheet name="` + xmlEscape(sheetName) + `" sheetId="1" r:id="rId1"/></sheets>` +
+			`</workbook>`},
+		{"xl/_rels/workbook.xml.rels", xml.Header + `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+			`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet1.xml"/>` +
+			`</Relationships>`},
+	}
+	for _, f := range files {
+		fw, err := z.Create(f.name)
+		if err != nil {
+			return err
+		}
+		if _, err := io.WriteString(fw, f.body); err != nil {
+			return err
+		}
+	}
+	fw, err := z.Create("xl/worksheets/sheet1.xml")
+	if err != nil {
+		return err
+	}
+	var b strings.Builder
+	b.WriteString(xml.Header)
+	b.WriteString(`<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>`)
+	for i, row := range rows {
+		fmt.Fprintf(&b, `<row r="%d">`, i+1)
+		for j, cell := range row {
+			if cell == "" {
+				continue
+			}
+			fmt.Fprintf(&b, `<c r="%s%d" t="inlineStr"><is><t xml:space="preserve">%s</t></is></c>`,
+				xlsxColumn(j), i+1, xmlEscape(cell))
+		}
+		b.WriteString(`</row>`)
+	}
+	b.WriteString(`</sheetData></worksheet>`)
+	if _, err := io.WriteString(fw, b.String()); err != nil {
+		return err
+	}
+	return z.Close()
+}
+
+// readXLSX returns the cells of the first worksheet of a workbook
+func readXLSX(r io.ReaderAt, size int64) ([][]string, error) {
+	z, err := zip.NewReader(r, size)
+	if err != nil {
+		return nil, err
+	}
+	files := make(map[string]*zip.File, len(z.File))
+	for _, f := range z.File {
+		files[f.Name] = f
+	}
+	var wb struct {
+		Sheets []struct {
+			ID string `xml:"http://schemas.openxmlformats.org/officeDocument/2006/relationships id,attr"`
+		} `xml:"sheets>sheet"`
+	}
+	if err := decodeZipXML(files["xl/workbook.xml"], &wb); err != nil {
+		return nil, err
+	}
+	if len(wb.Sheets) == 0 {
+		return nil, ErrXLSXNoSheet
+	}
+	var rels struct {
+		Relationships []struct {
+			ID     string `xml:"Id,attr"`
+			Target string `xml:"Target,attr"`
+		} `xml:"Relationship"`
+	}
+	if err := decodeZipXML(files["xl/_rels/workbook.xml.rels"], &rels); err != nil {
+		return nil, err
+	}
+	sheetPath := ""
+	for _, rel := range rels.Relationships {
+		if rel.ID == wb.Sheets[0].ID {
+			sheetPath = rel.Target
+			if strings.HasPrefix(sheetPath, "/") {
+				sheetPath = sheetPath[1:]
+			} else {
+				sheetPath = path.Join("xl", sheetPath)
+			}
+		}
+	}
+	if files[sheetPath] == nil {
+		return nil, ErrXLSXNoSheet
+	}
+	var shared []string
+	if f := files["xl/sharedStrings.xml"]; f != nil {
+		var sst struct {
+			Items []xlsxText `xml:"si"`
+		}
+		if err := decodeZipXML(f, &sst); err != nil {
+			return nil, err
+		}
+		shared = make([]string, len(sst.Items))
+		for i, si := range sst.Items {
+			shared[i] = si.String()
+		}
+	}
+	var ws struct {
+		Rows []struct {
+			R     int `xml:"r,attr"`
+			Cells []struct {
+				R      string   `xml:"r,attr"`
+				T      string   `xml:"t,attr"`
+				V      string   `xml:"v"`
+				Inline xlsxText `xml:"is"`
+			} `xml:"c"`
+		} `xml:"sheetData>row"`
+	}
+	if err := decodeZipXML(files[sheetPath], &ws); err != nil {
+		return nil, err
+	}
+	var rows [][]string
+	for i, row := range ws.Rows {
+		n := row.R
+		if n == 0 {
+			n = i + 1
+		}
+		if n < 1 || n > xlsxMaxRows {
+			return nil, fmt.Errorf("xlsx: invalid row number %d", row.R)
+		}
+		for len(rows) < n {
+			rows = append(rows, nil)
+		}
+		var cells []string
+		for j, c := range row.Cells {
+			col := j
+			if c.R != "" {
+				var err error
+				if col, err = xlsxColumnIndex(c.R); err != nil {
+					return nil, err
+				}
+			}
+			if col >= xlsxMaxColumns {
+				return nil, fmt.Errorf("xlsx: too many cells in row %d", n)
+			}
+			for len(cells) <= col {
+				cells = append(cells, "")
+			}
+			switch c.T {
+			case "s":
+				idx, err := strconv.Atoi(c.V)
+				if err != nil || idx < 0 || idx >= len(shared) {
+					return nil, fmt.Errorf("xlsx: invalid shared string reference in %s", c.R)
+				}
+				cells[col] = shared[idx]
+			case "inlineStr":
+				cells[col] = c.Inline.String()
+			default:
+				cells[col] = c.V
+			}
+		}
+		rows[n-1] = cells
+	}
+	return rows, nil
+}
+
+// xlsxText is a string item which is either plain or made of rich text runs
+type xlsxText struct {
+	T    string `xml:"t"`
+	Runs []struct {
+		T string `xml:"t"`
+	} `xml:"r"`
+}
+
+func (t xlsxText) String() string {
+	if len(t.Runs) == 0 {
+		return t.T
+	}
+	var b strings.Builder
+	for _, r := range t.Runs {
+		b.WriteString(r.T)
+	}
+	return b.String()
+}
+
+func decodeZipXML(f *zip.File, v interface{}) error {
+	if f == nil {
+		return ErrXLSXNoSheet
+	}
+	rc, err := f.Open()
+	if err != nil {
+		return err
+	}
+	defer rc.Close()
+	return xml.NewDecoder(rc).Decode(v)
+}
+
+// xlsxColumn converts a zero based column index to a column name like AB
+func xlsxColumn(i int) string {
+	name := ""
+	for i++; i > 0; i = (i - 1) / 26 {
+		name = string(rune('A'+(i-1)%26)) + name
+	}
+	return name
+}
+
+// xlsxColumnIndex converts a cell reference like AB12 to a zero based column
+// index
+func xlsxColumnIndex(ref string) (int, error) {
+	letters := 0
+	n := 0
+	for letters < len(ref) && ref[letters] >= 'A' && ref[letters] <= 'Z' {
+		n = n*26 + int(ref[letters]-'A'+1)
+		letters++
+		if n > xlsxMaxColumns {
+			return 0, fmt.Errorf("xlsx: invalid cell reference %q", ref)
+		}
+	}
+	row, err := strconv.Atoi(ref[letters:])
+	if letters == 0 || err != nil || row < 1 || row > xlsxMaxRows || ref[letters] == '+' {
+		return 0, fmt.Errorf("xlsx: invalid cell reference %q", ref)
+	}
+	return n - 1, nil
+}
+
+func xmlEscape(s string) string {
+	var b strings.Builder
+	xml.EscapeText(&b, []byte(s))
+	return b.String()
+}
@@ -0,0 +1,75 @@
+package poeditor
+
+import (
+	"encoding/xml"
+	"io"
+	"strings"
+)
+
+// decodeXMLText turns the inner XML of an element into text. Entities are
+// resolved while inline markup such as <b> is kept as written.
+func decodeXMLText(inner string) string {
+	if !strings.ContainsAny(inner, "&<") {
+		return inner
+	}
+	var b strings.Builder
+	dec := xml.NewDecoder(strings.NewReader("<x>" + inner + "</x>"))
+	dec.Strict = false
+	dec.Entity = xml.HTMLEntity
+	depth := 0
+	for {
+		tok, err := dec.RawToken()
+		if err != nil {
+			return b.String()
+		}
+		switch tok := tok.(type) {
+		case xml.CharData:
+			if depth > 0 {
+				b.WriteString(string(tok))
+			}
+		case xml.StartElement:
+			if depth > 0 {
+				b.WriteString("<" + xmlName(tok.Name))
+				for _, a := range tok.Attr {
+					b.WriteString(" " + xmlName(a.Name) + `="` + xmlEscape(a.Value) + `"`)
+				}
+				b.WriteString(">")
+			}
+			depth++
+		case xml.EndElement:
+			depth--
+			if depth > 0 {
+				b.WriteString("</" + xmlName(tok.Name) + ">")
+			}
+		}
+	}
+}
+
+// encodeXMLText is the inverse of decodeXMLText. Text which is well formed
+// markup is written as is, anything else is escaped.
+func encodeXMLText(s string) string {
+	if !strings.ContainsAny(s, "&<>") {
+		return s
+	}
+	if strings.Contains(s, "<") && wellFormedXML(s) {
+		return strings.Replace(s, "&", "&amp;", -1)
+	}
+	return xmlEscape(s)
+}
+
+func wellFormedXML(s string) bool {
+	dec := xml.NewDecoder(strings.NewReader("<x>" + strings.Replace(s, "&", "&amp;", -1) + "</x>"))
+	for {
+		_, err := dec.Token()
+		if err != nil {
+			return err == io.EOF
+		}
+	}
+}
+
+func xmlName(n xml.Name) string {
+	if n.Space != "" {
+		return n.Space + ":" + n.Local
+	}
+	return n.Local
+}